@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/nurfianqodar/school-microservices/api/handlers"
+	"github.com/nurfianqodar/school-microservices/api/middleware"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -41,6 +42,9 @@ func main() {
 	userHandler := handlers.NewUserHandler(userSvc)
 	userHandler.RegisterRouter(r)
 
+	// Wrap router with authentication middleware
+	handler := middleware.NewAuth(userSvc, r)
+
 	// Run http server
 	host, ok := os.LookupEnv("HOST")
 	if !ok {
@@ -53,7 +57,7 @@ func main() {
 	addr := fmt.Sprintf("%s:%s", host, port)
 
 	log.Printf("server listening on %s\n", addr)
-	if err = http.ListenAndServe(addr, r); err != nil {
+	if err = http.ListenAndServe(addr, handler); err != nil {
 		log.Fatal(err)
 	}
 }
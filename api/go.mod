@@ -6,6 +6,7 @@ require (
 	github.com/nurfianqodar/school-microservices/services/users v0.0.0-20250621230453-238a5996ede3
 	github.com/nurfianqodar/school-microservices/utils v0.0.0-20250621230453-238a5996ede3
 	google.golang.org/grpc v1.73.0
+	google.golang.org/protobuf v1.36.6
 )
 
 require (
@@ -13,5 +14,4 @@ require (
 	golang.org/x/sys v0.32.0 // indirect
 	golang.org/x/text v0.24.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
 )
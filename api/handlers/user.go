@@ -2,13 +2,29 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/nurfianqodar/school-microservices/api/middleware"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
 	"github.com/nurfianqodar/school-microservices/utils/httperr"
 	"github.com/nurfianqodar/school-microservices/utils/httpres"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	policyStaff              = middleware.Policy{Roles: []string{middleware.RoleStaff}}
+	policyStaffTeacher       = middleware.Policy{Roles: []string{middleware.RoleStaff, middleware.RoleTeacher}}
+	policyStaffOrSelf        = middleware.Policy{Roles: []string{middleware.RoleStaff}, SelfParam: "id"}
+	policyStaffTeacherOrSelf = middleware.Policy{Roles: []string{middleware.RoleStaff, middleware.RoleTeacher}, SelfParam: "id"}
+	policySelf               = middleware.Policy{SelfParam: "id"}
 )
 
 type userHandler struct {
@@ -16,10 +32,35 @@ type userHandler struct {
 }
 
 func (h *userHandler) RegisterRouter(mux *http.ServeMux) {
-	mux.HandleFunc("POST /api/v1/users/{$}", h.handleCreateOneUser)
-	mux.HandleFunc("GET /api/v1/users/{$}", h.handleListUser)
+	mux.Handle("POST /api/v1/users/{$}", middleware.Authorize(policyStaff, h.handleCreateOneUser))
+	mux.Handle("GET /api/v1/users/{$}", middleware.Authorize(policyStaffTeacher, h.handleListUser))
+	mux.Handle("GET /api/v1/users/search", middleware.Authorize(policyStaffTeacher, h.handleSearchUsers))
+	mux.Handle("GET /api/v1/users/search/{$}", middleware.Authorize(policyStaffTeacher, h.handleSearchUsers))
+	mux.Handle("GET /api/v1/users/{id}/{$}", middleware.Authorize(policyStaffTeacherOrSelf, h.handleGetOneUser))
+	mux.Handle("PATCH /api/v1/users/{id}/{$}", middleware.Authorize(policyStaffOrSelf, h.handleUpdateOneUser))
+	mux.Handle("PUT /api/v1/users/{id}/password/{$}", middleware.Authorize(policyStaffOrSelf, h.handleUpdateOnePasswordUser))
+	mux.Handle("DELETE /api/v1/users/{id}/{$}", middleware.Authorize(policyStaff, h.handleDeleteOneUser))
+	mux.Handle("POST /api/v1/users/{id}/unlock/{$}", middleware.Authorize(policyStaff, h.handleUnlockUser))
+	mux.Handle("POST /api/v1/users/{id}/mfa/totp/{$}", middleware.Authorize(policySelf, h.handleEnrollTotpUser))
+	mux.Handle("POST /api/v1/users/{id}/mfa/totp/confirm/{$}", middleware.Authorize(policySelf, h.handleConfirmTotpUser))
+	mux.Handle("DELETE /api/v1/users/{id}/mfa/totp/{$}", middleware.Authorize(policyStaffOrSelf, h.handleDisableTotpUser))
+	mux.Handle("POST /api/v1/users/{id}/mfa/enrollment/{$}", middleware.Authorize(policyStaff, h.handleIssueMfaEnrollmentUser))
+
+	mux.Handle("POST /api/v1/auth/login/{$}", middleware.Public(h.handleLoginUser))
+	mux.Handle("POST /api/v1/auth/refresh/{$}", middleware.Public(h.handleRefreshTokenUser))
+	mux.Handle("POST /api/v1/auth/verify/{$}", middleware.Public(h.handleVerifyTokenUser))
+	mux.Handle("POST /api/v1/auth/logout/{$}", middleware.Public(h.handleLogoutUser))
+	mux.Handle("POST /api/v1/auth/mfa/verify/{$}", middleware.Public(h.handleVerifyMfaUser))
+	mux.Handle("PUT /api/v1/auth/mfa/roles/{$}", middleware.Authorize(policyStaff, h.handleUpdateMfaRoleUser))
+	mux.Handle("POST /api/v1/auth/mfa/enrollment/{id}/totp/{$}", middleware.ForwardBearer(h.handleEnrollTotpUser))
+	mux.Handle("POST /api/v1/auth/mfa/enrollment/{id}/totp/confirm/{$}", middleware.ForwardBearer(h.handleConfirmTotpUser))
+	mux.Handle("POST /api/v1/auth/password/reset/{$}", middleware.Public(h.handleRequestPasswordReset))
+	mux.Handle("POST /api/v1/auth/password/reset/confirm/{$}", middleware.Public(h.handleConfirmPasswordReset))
+	mux.Handle("GET /api/v1/auth/email/verify/{$}", middleware.Public(h.handleVerifyEmailUser))
+	mux.HandleFunc("GET /api/v1/auth/sessions/{$}", h.handleListSessionsUser)
+	mux.HandleFunc("DELETE /api/v1/auth/sessions/{session_id}/{$}", h.handleRevokeSessionUser)
 
-	mux.HandleFunc("POST /api/v1/auth/login/{$}", h.handleLoginUser)
+	mux.Handle("GET /.well-known/jwks.json", middleware.Public(h.handleGetJwksUser))
 }
 
 func NewUserHandler(s pbusers.UserServiceClient) Handler {
@@ -47,39 +88,308 @@ func (h *userHandler) handleCreateOneUser(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(res)
 }
 
+// userSorts map sort query value to GetManyUser sort order
+var userSorts = map[string]pbusers.UserSort{
+	"created_asc":  pbusers.UserSort_CreatedAsc,
+	"created_desc": pbusers.UserSort_CreatedDesc,
+	"email_asc":    pbusers.UserSort_EmailAsc,
+	"email_desc":   pbusers.UserSort_EmailDesc,
+}
+
+// parseRole convert case insensitive role name of query to user role
+func parseRole(role string) (pbusers.UserRole, httperr.HTTPErr) {
+	for value, name := range pbusers.UserRole_name {
+		if value != int32(pbusers.UserRole_Unspecified) && strings.EqualFold(name, role) {
+			return pbusers.UserRole(value), nil
+		}
+	}
+	return pbusers.UserRole_Unspecified, httperr.New(http.StatusBadRequest, "unknown role")
+}
+
+// parseSearchUserQuery build SearchUsers request from query. q is
+// required, role may be repeated to match any of them.
+func parseSearchUserQuery(query url.Values) (*pbusers.SearchUsersRequest, httperr.HTTPErr) {
+	req := &pbusers.SearchUsersRequest{Query: query.Get("q")}
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, httperr.New(http.StatusBadRequest, "q query is required")
+	}
+	if pageSize := query.Get("page_size"); pageSize != "" {
+		size, err := strconv.ParseUint(pageSize, 10, 32)
+		if err != nil {
+			return nil, httperr.New(http.StatusBadRequest, "page_size query must be positive integer")
+		}
+		req.PageSize = uint32(size)
+	}
+	for _, role := range query["role"] {
+		r, httpErr := parseRole(role)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+		req.Roles = append(req.Roles, r)
+	}
+	return req, nil
+}
+
+// parseListUserQuery build GetManyUser request from query. Every param
+// is optional.
+func parseListUserQuery(query url.Values) (*pbusers.GetManyUserRequest, httperr.HTTPErr) {
+	req := &pbusers.GetManyUserRequest{
+		PageToken:   query.Get("page_token"),
+		EmailPrefix: query.Get("email_prefix"),
+	}
+
+	// take is kept as alias of page_size for old clients, skip can not
+	// be translated to cursor
+	pageSize := query.Get("page_size")
+	if pageSize == "" {
+		pageSize = query.Get("take")
+	}
+	if pageSize != "" {
+		size, err := strconv.ParseUint(pageSize, 10, 32)
+		if err != nil {
+			return nil, httperr.New(http.StatusBadRequest, "page_size query must be positive integer")
+		}
+		req.PageSize = uint32(size)
+	}
+	if query.Get("skip") != "" {
+		return nil, httperr.New(http.StatusBadRequest, "skip query is not supported, use page_token")
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		s, ok := userSorts[sort]
+		if !ok {
+			return nil, httperr.New(http.StatusBadRequest, "sort query must be one of created_asc, created_desc, email_asc or email_desc")
+		}
+		req.Sort = s
+	}
+
+	if role := query.Get("role"); role != "" {
+		r, httpErr := parseRole(role)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+		req.Role = r
+	}
+
+	for param, dst := range map[string]**timestamppb.Timestamp{
+		"created_after":  &req.CreatedAfter,
+		"created_before": &req.CreatedBefore,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, httperr.New(http.StatusBadRequest, param+" query must be RFC 3339 time")
+		}
+		*dst = timestamppb.New(t)
+	}
+	return req, nil
+}
+
 func (h *userHandler) handleListUser(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	takeQuery := r.URL.Query().Get("take")
-	skipQuery := r.URL.Query().Get("skip")
+	req, httpErr := parseListUserQuery(r.URL.Query())
+	if httpErr != nil {
+		httpErr.Send(w)
+		return
+	}
 
-	limit, err := strconv.Atoi(takeQuery)
+	res, err := h.s.GetManyUser(r.Context(), req)
 	if err != nil {
-		httperr.New(http.StatusBadRequest, "take query must be integer").Send(w)
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+
+	users := res.GetUsers()
+	if users == nil {
+		users = []*pbusers.UserSummary{}
+	}
+	json.NewEncoder(w).Encode(httpres.NewPaginated(users, httpres.Pagination{
+		PageSize:      len(users),
+		NextPageToken: res.GetNextPageToken(),
+		TotalCount:    res.GetTotalCount(),
+	}))
+}
+
+func (h *userHandler) handleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	req, httpErr := parseSearchUserQuery(r.URL.Query())
+	if httpErr != nil {
+		httpErr.Send(w)
 		return
 	}
 
-	offset, err := strconv.Atoi(skipQuery)
+	res, err := h.s.SearchUsers(r.Context(), req)
 	if err != nil {
-		httperr.New(http.StatusBadRequest, "skip query must be integer").Send(w)
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
 		return
 	}
 
-	if limit == 0 {
-		limit = 10
+	results := res.GetResults()
+	if results == nil {
+		results = []*pbusers.UserSearchResult{}
 	}
+	json.NewEncoder(w).Encode(httpres.New(true, results))
+}
 
-	res, err := h.s.GetManyUser(r.Context(), &pbusers.GetManyUserRequest{
-		Limit:  uint64(limit),
-		Offset: uint64(offset),
+func (h *userHandler) handleGetOneUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	res, err := h.s.GetOneUser(r.Context(), &pbusers.GetOneUserRequest{
+		Id: r.PathValue("id"),
 	})
 	if err != nil {
 		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
 	}
 
 	json.NewEncoder(w).Encode(httpres.New(true, res))
 }
 
+type updateOneUserBody struct {
+	Email *string `json:"email"`
+	// Role name, matched case insensitively like role query
+	Role *string `json:"role"`
+}
+
+func (h *userHandler) handleUpdateOneUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(updateOneUserBody)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+	if body.Email == nil && body.Role == nil {
+		httperr.New(http.StatusBadRequest, "email or role must be provided").Send(w)
+		return
+	}
+	// Email and role are updated by separate calls, changing both at once
+	// could leave one of them applied when the other fail
+	if body.Email != nil && body.Role != nil {
+		httperr.New(http.StatusBadRequest, "email and role must be updated separately").Send(w)
+		return
+	}
+
+	// Authorize and validate role before changing anything
+	id := r.PathValue("id")
+	var role pbusers.UserRole
+	if body.Role != nil {
+		sub, _ := middleware.Subject(r.Context())
+		callerRole, _ := middleware.Role(r.Context())
+		if !policyStaff.Allows(sub, callerRole, r) {
+			middleware.ErrPermissionDenied.Send(w)
+			return
+		}
+		var httpErr httperr.HTTPErr
+		role, httpErr = parseRole(*body.Role)
+		if httpErr != nil {
+			httpErr.Send(w)
+			return
+		}
+	}
+
+	if body.Email != nil {
+		_, err := h.s.UpdateOneEmailUser(r.Context(), &pbusers.UpdateOneEmailUserRequest{
+			Id:    id,
+			Email: *body.Email,
+		})
+		if err != nil {
+			httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+			return
+		}
+	}
+	if body.Role != nil {
+		_, err := h.s.UpdateOneRoleUser(r.Context(), &pbusers.UpdateOneRoleUserRequest{
+			Id:   id,
+			Role: role,
+		})
+		if err != nil {
+			httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+			return
+		}
+	}
+
+	res, err := h.s.GetOneUser(r.Context(), &pbusers.GetOneUserRequest{Id: id})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleUpdateOnePasswordUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.UpdateOnePasswordUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+	body.Id = r.PathValue("id")
+
+	res, err := h.s.UpdateOnePasswordUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleDeleteOneUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	hard := false
+	if hardQuery := r.URL.Query().Get("hard"); hardQuery != "" {
+		var err error
+		hard, err = strconv.ParseBool(hardQuery)
+		if err != nil {
+			httperr.New(http.StatusBadRequest, "hard query must be boolean").Send(w)
+			return
+		}
+	}
+
+	id := r.PathValue("id")
+	var (
+		res any
+		err error
+	)
+	if hard {
+		res, err = h.s.DeleteHardOneUser(r.Context(), &pbusers.DeleteHardOneUserRequest{Id: id})
+	} else {
+		res, err = h.s.DeleteSoftOneUser(r.Context(), &pbusers.DeleteSoftOneUserRequest{Id: id})
+	}
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleUnlockUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	res, err := h.s.UnlockUser(r.Context(), &pbusers.UnlockUserRequest{
+		Id: r.PathValue("id"),
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
 func (h *userHandler) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -92,10 +402,278 @@ func (h *userHandler) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := h.s.LoginUser(r.Context(), body)
+	// forward client address used to throttle failed attempts
+	ctx := metadata.AppendToOutgoingContext(r.Context(), "x-real-ip", middleware.ClientIP(r))
+	res, err := h.s.LoginUser(ctx, body)
 	if err != nil {
 		log.Println(err)
 		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleRefreshTokenUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.RefreshTokenUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+
+	res, err := h.s.RefreshTokenUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleVerifyTokenUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.VerifyTokenUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+
+	res, err := h.s.VerifyTokenUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleLogoutUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.LogoutUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+	if accessToken, ok := middleware.BearerToken(r); ok && body.AccessToken == "" {
+		body.AccessToken = accessToken
+	}
+
+	res, err := h.s.LogoutUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleRequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.RequestPasswordResetRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+
+	// forward client address used to throttle reset requests
+	ctx := metadata.AppendToOutgoingContext(r.Context(), "x-real-ip", middleware.ClientIP(r))
+	res, err := h.s.RequestPasswordReset(ctx, body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.ConfirmPasswordResetRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+
+	res, err := h.s.ConfirmPasswordReset(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
 	}
 	json.NewEncoder(w).Encode(httpres.New(true, res))
 }
+
+func (h *userHandler) handleVerifyEmailUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// token is read from query so verification link can be opened directly
+	res, err := h.s.VerifyEmailUser(r.Context(), &pbusers.VerifyEmailUserRequest{
+		Token: r.URL.Query().Get("token"),
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleVerifyMfaUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.VerifyMfaUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+
+	res, err := h.s.VerifyMfaUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleEnrollTotpUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	res, err := h.s.EnrollTotpUser(r.Context(), &pbusers.EnrollTotpUserRequest{
+		Id: r.PathValue("id"),
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleConfirmTotpUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.ConfirmTotpUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+	body.Id = r.PathValue("id")
+
+	res, err := h.s.ConfirmTotpUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleIssueMfaEnrollmentUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	res, err := h.s.IssueMfaEnrollmentUser(r.Context(), &pbusers.IssueMfaEnrollmentUserRequest{
+		Id: r.PathValue("id"),
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleDisableTotpUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body, staff disabling other user may omit it
+	defer r.Body.Close()
+	body := new(pbusers.DisableTotpUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil && !errors.Is(err, io.EOF) {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+	body.Id = r.PathValue("id")
+
+	res, err := h.s.DisableTotpUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleUpdateMfaRoleUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request body
+	defer r.Body.Close()
+	body := new(pbusers.UpdateMfaRoleUserRequest)
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		httperr.ErrInvalidRequestBody.Send(w)
+		return
+	}
+
+	res, err := h.s.UpdateMfaRoleUser(r.Context(), body)
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleListSessionsUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	sub, _ := middleware.Subject(r.Context())
+	res, err := h.s.ListSessionsUser(r.Context(), &pbusers.ListSessionsUserRequest{
+		Id: sub,
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleRevokeSessionUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	sub, _ := middleware.Subject(r.Context())
+	res, err := h.s.RevokeSessionUser(r.Context(), &pbusers.RevokeSessionUserRequest{
+		Id:        sub,
+		SessionId: r.PathValue("session_id"),
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+	json.NewEncoder(w).Encode(httpres.New(true, res))
+}
+
+func (h *userHandler) handleGetJwksUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	res, err := h.s.GetJwksUser(r.Context(), &pbusers.GetJwksUserRequest{})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+
+	// JWKS is served without envelope so it can be consumed by standard clients
+	keys := res.GetKeys()
+	if keys == nil {
+		keys = []*pbusers.Jwk{}
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+}
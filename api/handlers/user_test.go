@@ -0,0 +1,158 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nurfianqodar/school-microservices/api/handlers"
+	"github.com/nurfianqodar/school-microservices/api/middleware"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeUserService authenticate every bearer token as role and record
+// request it received. Method which is not overridden panic.
+type fakeUserService struct {
+	pbusers.UserServiceClient
+	role        string
+	search      *pbusers.SearchUsersRequest
+	updateEmail *pbusers.UpdateOneEmailUserRequest
+	updateRole  *pbusers.UpdateOneRoleUserRequest
+	realIP      []string
+}
+
+const subject = "0197a1b2-0000-7000-8000-000000000001"
+
+func (f *fakeUserService) VerifyTokenUser(ctx context.Context, in *pbusers.VerifyTokenUserRequest, opts ...grpc.CallOption) (*pbusers.VerifyTokenUserResponse, error) {
+	return &pbusers.VerifyTokenUserResponse{Sub: subject, Role: f.role}, nil
+}
+
+func (f *fakeUserService) SearchUsers(ctx context.Context, in *pbusers.SearchUsersRequest, opts ...grpc.CallOption) (*pbusers.SearchUsersResponse, error) {
+	f.search = in
+	return &pbusers.SearchUsersResponse{}, nil
+}
+
+func (f *fakeUserService) UpdateOneEmailUser(ctx context.Context, in *pbusers.UpdateOneEmailUserRequest, opts ...grpc.CallOption) (*pbusers.UpdateOneEmailUserResponse, error) {
+	f.updateEmail = in
+	return &pbusers.UpdateOneEmailUserResponse{Id: in.Id}, nil
+}
+
+func (f *fakeUserService) UpdateOneRoleUser(ctx context.Context, in *pbusers.UpdateOneRoleUserRequest, opts ...grpc.CallOption) (*pbusers.UpdateOneRoleUserResponse, error) {
+	f.updateRole = in
+	return &pbusers.UpdateOneRoleUserResponse{Id: in.Id}, nil
+}
+
+func (f *fakeUserService) RequestPasswordReset(ctx context.Context, in *pbusers.RequestPasswordResetRequest, opts ...grpc.CallOption) (*pbusers.RequestPasswordResetResponse, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.realIP = md.Get("x-real-ip")
+	return &pbusers.RequestPasswordResetResponse{}, nil
+}
+
+func (f *fakeUserService) GetOneUser(ctx context.Context, in *pbusers.GetOneUserRequest, opts ...grpc.CallOption) (*pbusers.GetOneUserResponse, error) {
+	return &pbusers.GetOneUserResponse{Id: in.Id}, nil
+}
+
+// serve route request through gateway mux and auth middleware
+func serve(s pbusers.UserServiceClient, method, target, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	handlers.NewUserHandler(s).RegisterRouter(mux)
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	middleware.NewAuth(s, mux).ServeHTTP(w, r)
+	return w
+}
+
+func TestSearchUsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		target string
+		code   int
+		query  string
+		roles  []pbusers.UserRole
+	}{
+		{"Should search without trailing slash", middleware.RoleStaff, "/api/v1/users/search?q=john", http.StatusOK, "john", nil},
+		{"Should search with trailing slash", middleware.RoleTeacher, "/api/v1/users/search/?q=john", http.StatusOK, "john", nil},
+		{"Should filter by repeated role", middleware.RoleStaff, "/api/v1/users/search?q=doe&role=teacher&role=Student", http.StatusOK, "doe", []pbusers.UserRole{pbusers.UserRole_Teacher, pbusers.UserRole_Student}},
+		{"Should refuse missing query", middleware.RoleStaff, "/api/v1/users/search", http.StatusBadRequest, "", nil},
+		{"Should refuse unknown role", middleware.RoleStaff, "/api/v1/users/search?q=doe&role=admin", http.StatusBadRequest, "", nil},
+		{"Should refuse student", middleware.RoleStudent, "/api/v1/users/search?q=john", http.StatusForbidden, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeUserService{role: tt.role}
+			w := serve(s, http.MethodGet, tt.target, "")
+			if w.Code != tt.code {
+				t.Fatalf("expected status %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.code != http.StatusOK {
+				if s.search != nil {
+					t.Fatal("search service invoked for refused request")
+				}
+				return
+			}
+			if s.search == nil || s.search.Query != tt.query || !slices.Equal(s.search.Roles, tt.roles) {
+				t.Fatalf("unexpected search request %v", s.search)
+			}
+		})
+	}
+}
+
+func TestUpdateOneUser(t *testing.T) {
+	other := "0197a1b2-0000-7000-8000-000000000002"
+	tests := []struct {
+		name   string
+		role   string
+		target string
+		body   string
+		code   int
+		email  bool
+		update pbusers.UserRole
+	}{
+		{"Should update own email", middleware.RoleStudent, "/api/v1/users/" + subject + "/", `{"email":"new@email.com"}`, http.StatusOK, true, pbusers.UserRole_Unspecified},
+		{"Should refuse own role", middleware.RoleStudent, "/api/v1/users/" + subject + "/", `{"role":"staff"}`, http.StatusForbidden, false, pbusers.UserRole_Unspecified},
+		{"Should update role by name", middleware.RoleStaff, "/api/v1/users/" + other + "/", `{"role":"Teacher"}`, http.StatusOK, false, pbusers.UserRole_Teacher},
+		{"Should refuse unknown role", middleware.RoleStaff, "/api/v1/users/" + other + "/", `{"role":"admin"}`, http.StatusBadRequest, false, pbusers.UserRole_Unspecified},
+		{"Should refuse email and role together", middleware.RoleStaff, "/api/v1/users/" + other + "/", `{"email":"new@email.com","role":"Teacher"}`, http.StatusBadRequest, false, pbusers.UserRole_Unspecified},
+		{"Should refuse numeric role", middleware.RoleStaff, "/api/v1/users/" + other + "/", `{"role":2}`, http.StatusBadRequest, false, pbusers.UserRole_Unspecified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeUserService{role: tt.role}
+			w := serve(s, http.MethodPatch, tt.target, tt.body)
+			if w.Code != tt.code {
+				t.Fatalf("expected status %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if (s.updateEmail != nil) != tt.email {
+				t.Fatalf("expected email updated %v, got %v", tt.email, s.updateEmail)
+			}
+			if tt.update == pbusers.UserRole_Unspecified {
+				if s.updateRole != nil {
+					t.Fatalf("unexpected role update %v", s.updateRole)
+				}
+				return
+			}
+			if s.updateRole == nil || s.updateRole.Role != tt.update {
+				t.Fatalf("expected role %s, got %v", tt.update, s.updateRole)
+			}
+		})
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	s := &fakeUserService{}
+	w := serve(s, http.MethodPost, "/api/v1/auth/password/reset/", `{"email":"john@email.com"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// httptest request come from 192.0.2.1
+	if !slices.Equal(s.realIP, []string{"192.0.2.1"}) {
+		t.Fatalf("expected client address forwarded, got %v", s.realIP)
+	}
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/utils/httperr"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	ErrMissingToken = httperr.New(http.StatusUnauthorized, "missing bearer token")
+)
+
+type contextKey int
+
+const (
+	subjectKey contextKey = iota
+	roleKey
+)
+
+// publicHandler mark route handler which does not require authentication
+type publicHandler struct {
+	http.Handler
+}
+
+// Public wrap route handler so auth middleware skip authentication
+// for that route
+func Public(h http.HandlerFunc) http.Handler {
+	return publicHandler{h}
+}
+
+// ForwardBearer wrap route handler so auth middleware skip it while
+// bearer token is still forwarded to downstream gRPC call. It is used by
+// route authenticated by token which is not access token, such as MFA
+// enrollment token, leaving verification to users service.
+func ForwardBearer(h http.HandlerFunc) http.Handler {
+	return publicHandler{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := BearerToken(r); ok {
+			ctx := metadata.AppendToOutgoingContext(r.Context(), "authorization", "Bearer "+token)
+			r = r.WithContext(ctx)
+		}
+		h(w, r)
+	})}
+}
+
+type auth struct {
+	s    pbusers.UserServiceClient
+	next *http.ServeMux
+}
+
+// NewAuth create middleware which authenticate every request routed by
+// mux using bearer token from Authorization header. Authenticated
+// subject and role are stored inside request context.
+func NewAuth(s pbusers.UserServiceClient, mux *http.ServeMux) http.Handler {
+	return &auth{s: s, next: mux}
+}
+
+func (a *auth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Unmatched route and public route are passed through directly
+	if h, pattern := a.next.Handler(r); pattern == "" || isPublic(h) {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	accessToken, ok := BearerToken(r)
+	if !ok {
+		ErrMissingToken.Send(w)
+		return
+	}
+
+	claims, err := a.s.VerifyTokenUser(r.Context(), &pbusers.VerifyTokenUserRequest{
+		AccessToken: accessToken,
+	})
+	if err != nil {
+		httperr.ConvertGRPCErrorToHTTPErr(err).Send(w)
+		return
+	}
+
+	// Forward token to every downstream gRPC call
+	ctx := metadata.AppendToOutgoingContext(r.Context(), "authorization", "Bearer "+accessToken)
+
+	ctx = context.WithValue(ctx, subjectKey, claims.Sub)
+	ctx = context.WithValue(ctx, roleKey, claims.Role)
+	a.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Subject return authenticated user id from request context
+func Subject(ctx context.Context) (string, bool) {
+	sub, ok := ctx.Value(subjectKey).(string)
+	return sub, ok
+}
+
+// Role return authenticated user role from request context
+func Role(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(roleKey).(string)
+	return role, ok
+}
+
+func isPublic(h http.Handler) bool {
+	_, ok := h.(publicHandler)
+	return ok
+}
+
+// BearerToken return token from Authorization header
+func BearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// ClientIP return address of client connected to gateway
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
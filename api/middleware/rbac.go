@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/nurfianqodar/school-microservices/utils/httperr"
+)
+
+// User roles as returned by user service
+const (
+	RoleTeacher = "teacher"
+	RoleStaff   = "staff"
+	RoleStudent = "student"
+	RoleParent  = "parent"
+)
+
+var (
+	ErrPermissionDenied = httperr.New(http.StatusForbidden, "permission denied")
+)
+
+// Policy describe who may access a route
+type Policy struct {
+	// Roles allowed to access route
+	Roles []string
+	// SelfParam is name of path value holding target user id. When it
+	// equal to authenticated subject access is granted regardless role.
+	SelfParam string
+}
+
+// Allows report whether user with given id and role may access r
+func (p Policy) Allows(sub, role string, r *http.Request) bool {
+	if slices.Contains(p.Roles, role) {
+		return true
+	}
+	return p.SelfParam != "" && r.PathValue(p.SelfParam) == sub
+}
+
+// Authorize wrap route handler so it only served when authenticated
+// user satisfy policy p. It must be used behind auth middleware.
+func Authorize(p Policy, h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub, _ := Subject(r.Context())
+		role, _ := Role(r.Context())
+		if !p.Allows(sub, role, r) {
+			w.Header().Set("Content-Type", "application/json")
+			ErrPermissionDenied.Send(w)
+			return
+		}
+		h(w, r)
+	})
+}
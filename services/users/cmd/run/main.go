@@ -2,16 +2,35 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nurfianqodar/school-microservices/services/users/db"
+	migrationsfs "github.com/nurfianqodar/school-microservices/services/users/misc/db/migrations"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
 	svc "github.com/nurfianqodar/school-microservices/services/users/services"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/migrate"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/passwordpolicy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token/revocation"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -20,21 +39,125 @@ func main() {
 		log.Fatalln("DSN environment variable was not set")
 	}
 
-	// Create database connection
+	// Create database pool, waiting until database is reachable
 	ctx := context.Background()
-	dbConn, err := pgx.Connect(ctx, dsn)
+	dbConfig, err := database.LoadConfig(dsn)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	defer dbConn.Close(ctx)
+	pool, err := database.Connect(ctx, dbConfig)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer pool.Close()
+
+	// Run migrate subcommand, otherwise make sure schema match binary
+	migrations, err := migrate.Load(migrationsfs.FS)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	migrator := migrate.New(pool, migrations)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, migrator, os.Args[2:]); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if autoMigrate, _ := strconv.ParseBool(os.Getenv("MIGRATE_ON_START")); autoMigrate {
+		if err := migrator.Up(ctx); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			log.Fatalln(err)
+		}
+	}
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Load token signing keys, fallback to SECRET when not configured.
+	// Once loaded SECRET signed token is only accepted until
+	// TOKEN_LEGACY_SECRET_UNTIL.
+	if keysDir, ok := os.LookupEnv("TOKEN_KEYS_DIR"); ok {
+		if err := token.LoadKeys(keysDir); err != nil {
+			log.Fatalln(err)
+		}
+		go reloadKeys(keysDir)
+	}
 
 	// Create queries instance
-	q := db.New(dbConn)
+	q := db.New(pool)
+
+	// Configure token revocation store
+	switch store := os.Getenv("REVOCATION_STORE"); store {
+	case "", "postgres":
+		token.SetRevocationStore(revocation.NewPostgres(q))
+	case "memory":
+		token.SetRevocationStore(revocation.NewMemory())
+	default:
+		log.Fatalf("unknown REVOCATION_STORE %s\n", store)
+	}
+
+	// Configure notifier used to deliver message to user. It must be
+	// chosen explicitly since log notifier print live reset and
+	// verification token.
+	var n notifier.Notifier
+	switch sink := os.Getenv("NOTIFIER"); sink {
+	case "":
+		log.Fatal("NOTIFIER environment variable was not set, use log or file")
+	case "log":
+		log.Println("warning: log notifier print tokens to standard log, use it for local development only")
+		n = notifier.NewLog()
+	case "file":
+		path, ok := os.LookupEnv("NOTIFIER_FILE")
+		if !ok {
+			log.Fatal("NOTIFIER_FILE environment variable was not set")
+		}
+		n = notifier.NewFile(path)
+	default:
+		log.Fatalf("unknown NOTIFIER %s\n", sink)
+	}
+
+	// Load password hash parameters
+	hashConfig, err := hasher.LoadConfig()
+	if err != nil {
+		log.Fatalf("unable to load hash config. %s\n", err.Error())
+	}
+	hasher.DefaultConfig = hashConfig
+	if err := hasher.LoadPeppers(); err != nil {
+		log.Fatalf("unable to load hash peppers. %s\n", err.Error())
+	}
+	if err := hasher.LoadLimiter(); err != nil {
+		log.Fatalf("unable to load hash limiter. %s\n", err.Error())
+	}
+
+	// Serve metrics on /debug/vars and health on /healthz when
+	// METRICS_ADDR is set
+	expvar.Publish("hasher", expvar.Func(func() any { return hasher.Stats() }))
+	expvar.Publish("db_pool", expvar.Func(func() any { return database.PoolStats(pool) }))
+	if metricsAddr, ok := os.LookupEnv("METRICS_ADDR"); ok {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		mux.HandleFunc("/healthz", healthHandler(pool))
+		go func() {
+			if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+				log.Fatalf("unable to serve metrics. %s\n", err.Error())
+			}
+		}()
+	}
+
+	// Load password policy
+	pp, err := passwordpolicy.FromEnv()
+	if err != nil {
+		log.Fatalf("unable to load password policy. %s\n", err.Error())
+	}
 
 	// Create server
-	server := grpc.NewServer()
-	service := svc.New(q)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(policy.UnaryServerInterceptor()),
+	)
+	service := svc.New(q, database.NewTxRunner(pool, q, dbConfig), n, pp)
 	pbusers.RegisterUserServiceServer(server, service)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go watchHealth(pool, healthServer)
 
 	// Create listener and runserver
 	host, ok := os.LookupEnv("HOST")
@@ -57,3 +180,73 @@ func main() {
 		log.Fatalln(err)
 	}
 }
+
+// reloadKeys reload token keys periodically and on SIGHUP so keys can
+// be rotated without restarting service
+func reloadKeys(dir string) {
+	interval := time.Minute
+	if env, ok := os.LookupEnv("TOKEN_KEYS_RELOAD_INTERVAL"); ok {
+		d, err := time.ParseDuration(env)
+		if err != nil {
+			log.Fatalf("invalid TOKEN_KEYS_RELOAD_INTERVAL. %s\n", err.Error())
+		}
+		interval = d
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sighup:
+		case <-ticker.C:
+		}
+		if err := token.LoadKeys(dir); err != nil {
+			log.Printf("error: failed to reload token keys. %s\n", err.Error())
+		}
+	}
+}
+
+// watchHealth ping database periodically and report it as serving
+// status of gRPC health service
+func watchHealth(pool *pgxpool.Pool, hs *health.Server) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := pool.Ping(ctx)
+		cancel()
+
+		st := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			log.Printf("error: database health check failed. %s\n", err.Error())
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		hs.SetServingStatus("", st)
+		hs.SetServingStatus(pbusers.UserService_ServiceDesc.ServiceName, st)
+
+		<-ticker.C
+	}
+}
+
+// healthHandler report database reachability along with pool statistics
+func healthHandler(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		code, st := http.StatusOK, "ok"
+		if err := pool.Ping(ctx); err != nil {
+			code, st = http.StatusServiceUnavailable, "unavailable"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": st,
+			"pool":   database.PoolStats(pool),
+		})
+	}
+}
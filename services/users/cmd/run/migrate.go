@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nurfianqodar/school-microservices/services/users/utils/migrate"
+)
+
+const migrateUsage = `usage: run migrate <command>
+
+commands:
+  up              apply every pending migration
+  down [n]        revert n latest migration, default 1
+  status          print migration status
+  goto <version>  migrate up or down to version, 0 revert everything
+  force <version> set version and clear dirty flag without running migration`
+
+// runMigrate execute migrate subcommand
+func runMigrate(ctx context.Context, m *migrate.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	var err error
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		n := 1
+		if len(rest) > 0 {
+			n, err = strconv.Atoi(rest[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step count %s", rest[0])
+			}
+		}
+		err = m.Down(ctx, n)
+	case "goto", "force":
+		if len(rest) != 1 {
+			return errors.New(migrateUsage)
+		}
+		version, perr := strconv.ParseInt(rest[0], 10, 64)
+		if perr != nil || version < 0 {
+			return fmt.Errorf("invalid version %s", rest[0])
+		}
+		if cmd == "goto" {
+			err = m.Goto(ctx, version)
+		} else {
+			err = m.Force(ctx, version)
+		}
+	case "status":
+		return printMigrateStatus(ctx, m)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	return err
+}
+
+func printMigrateStatus(ctx context.Context, m *migrate.Migrator) error {
+	version, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d (dirty: %t, latest: %d)\n", version, dirty, m.Latest())
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied"
+		}
+		fmt.Printf("%-8s %d_%s\n", state, s.Migration.Version, s.Migration.Name)
+	}
+	return nil
+}
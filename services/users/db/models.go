@@ -56,12 +56,87 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
-type User struct {
+type EmailVerificationToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Email     string
+	TokenHash string
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+}
+
+type LoginThrottle struct {
+	Key          string
+	Failures     int32
+	LastFailedAt pgtype.Timestamptz
+	LockedUntil  pgtype.Timestamptz
+}
+
+type MfaRecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	CreatedAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+}
+
+type MfaRolePolicy struct {
+	Role     UserRole
+	Required bool
+}
+
+type PasswordHistory struct {
 	ID           uuid.UUID
-	Email        string
-	Role         UserRole
+	UserID       uuid.UUID
 	PasswordHash string
 	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
-	DeletedAt    pgtype.Timestamptz
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+}
+
+type Session struct {
+	ID        uuid.UUID
+	FamilyID  uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	RotatedAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+}
+
+type TokenRevocation struct {
+	Jti       uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+type User struct {
+	ID              uuid.UUID
+	Email           string
+	Role            UserRole
+	PasswordHash    string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	DeletedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+}
+
+type UserMfa struct {
+	UserID       uuid.UUID
+	Secret       string
+	LastUsedStep int64
+	CreatedAt    pgtype.Timestamptz
+	ConfirmedAt  pgtype.Timestamptz
+}
+
+type UserTokenRevocation struct {
+	UserID       uuid.UUID
+	IssuedBefore pgtype.Timestamptz
 }
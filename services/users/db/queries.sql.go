@@ -12,9 +12,23 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const confirmOneUserMfa = `-- name: ConfirmOneUserMfa :execrows
+UPDATE user_mfa
+SET confirmed_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND confirmed_at IS NULL
+`
+
+func (q *Queries) ConfirmOneUserMfa(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, confirmOneUserMfa, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const countEmailUser = `-- name: CountEmailUser :one
 SELECT COUNT(*) FROM users
-WHERE email = $1
+WHERE email = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountEmailUser(ctx context.Context, email string) (int64, error) {
@@ -36,6 +50,184 @@ func (q *Queries) CountIDUser(ctx context.Context, id uuid.UUID) (int64, error)
 	return count, err
 }
 
+const countManyUser = `-- name: CountManyUser :one
+SELECT COUNT(*) FROM users
+WHERE
+    deleted_at IS NULL
+    AND ($1::user_role IS NULL OR role = $1)
+    AND ($2::text = '' OR email LIKE $2)
+    AND ($3::timestamptz IS NULL OR created_at >= $3)
+    AND ($4::timestamptz IS NULL OR created_at < $4)
+`
+
+type CountManyUserParams struct {
+	Role          NullUserRole
+	EmailPattern  string
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+}
+
+func (q *Queries) CountManyUser(ctx context.Context, arg *CountManyUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countManyUser,
+		arg.Role,
+		arg.EmailPattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTokenRevocation = `-- name: CountTokenRevocation :one
+SELECT COUNT(*) FROM token_revocations
+WHERE jti = $1
+`
+
+func (q *Queries) CountTokenRevocation(ctx context.Context, jti uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countTokenRevocation, jti)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createOneEmailVerificationToken = `-- name: CreateOneEmailVerificationToken :one
+INSERT INTO email_verification_tokens
+(id, user_id, email, token_hash, expires_at)
+VALUES
+($1, $2, $3, $4, $5)
+RETURNING id
+`
+
+type CreateOneEmailVerificationTokenParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Email     string
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateOneEmailVerificationToken(ctx context.Context, arg *CreateOneEmailVerificationTokenParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createOneEmailVerificationToken,
+		arg.ID,
+		arg.UserID,
+		arg.Email,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createOneMfaRecoveryCode = `-- name: CreateOneMfaRecoveryCode :exec
+INSERT INTO mfa_recovery_codes
+(id, user_id, code_hash)
+VALUES
+($1, $2, $3)
+`
+
+type CreateOneMfaRecoveryCodeParams struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	CodeHash string
+}
+
+func (q *Queries) CreateOneMfaRecoveryCode(ctx context.Context, arg *CreateOneMfaRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createOneMfaRecoveryCode, arg.ID, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const createOnePasswordHistory = `-- name: CreateOnePasswordHistory :exec
+INSERT INTO password_histories
+(id, user_id, password_hash)
+VALUES
+($1, $2, $3)
+`
+
+type CreateOnePasswordHistoryParams struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	PasswordHash string
+}
+
+func (q *Queries) CreateOnePasswordHistory(ctx context.Context, arg *CreateOnePasswordHistoryParams) error {
+	_, err := q.db.Exec(ctx, createOnePasswordHistory, arg.ID, arg.UserID, arg.PasswordHash)
+	return err
+}
+
+const createOnePasswordResetToken = `-- name: CreateOnePasswordResetToken :one
+INSERT INTO password_reset_tokens
+(id, user_id, token_hash, expires_at)
+VALUES
+($1, $2, $3, $4)
+RETURNING id
+`
+
+type CreateOnePasswordResetTokenParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateOnePasswordResetToken(ctx context.Context, arg *CreateOnePasswordResetTokenParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createOnePasswordResetToken,
+		arg.ID,
+		arg.UserID,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createOneSession = `-- name: CreateOneSession :one
+INSERT INTO sessions
+(id, family_id, user_id, expires_at)
+VALUES
+($1, $2, $3, $4)
+RETURNING id
+`
+
+type CreateOneSessionParams struct {
+	ID        uuid.UUID
+	FamilyID  uuid.UUID
+	UserID    uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateOneSession(ctx context.Context, arg *CreateOneSessionParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createOneSession,
+		arg.ID,
+		arg.FamilyID,
+		arg.UserID,
+		arg.ExpiresAt,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createOneTokenRevocation = `-- name: CreateOneTokenRevocation :exec
+INSERT INTO token_revocations
+(jti, expires_at)
+VALUES
+($1, $2)
+ON CONFLICT (jti) DO NOTHING
+`
+
+type CreateOneTokenRevocationParams struct {
+	Jti       uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateOneTokenRevocation(ctx context.Context, arg *CreateOneTokenRevocationParams) error {
+	_, err := q.db.Exec(ctx, createOneTokenRevocation, arg.Jti, arg.ExpiresAt)
+	return err
+}
+
 const createOneUser = `-- name: CreateOneUser :one
 INSERT INTO users
 (id, email, role, password_hash)
@@ -63,6 +255,19 @@ func (q *Queries) CreateOneUser(ctx context.Context, arg *CreateOneUserParams) (
 	return id, err
 }
 
+const deleteExpiredTokenRevocation = `-- name: DeleteExpiredTokenRevocation :execrows
+DELETE FROM token_revocations
+WHERE expires_at < CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredTokenRevocation(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredTokenRevocation)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteHardOneUser = `-- name: DeleteHardOneUser :one
 DELETE FROM users
 WHERE id = $1
@@ -75,6 +280,67 @@ func (q *Queries) DeleteHardOneUser(ctx context.Context, id uuid.UUID) (uuid.UUI
 	return id, err
 }
 
+const deleteManyMfaRecoveryCodeByUser = `-- name: DeleteManyMfaRecoveryCodeByUser :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteManyMfaRecoveryCodeByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteManyMfaRecoveryCodeByUser, userID)
+	return err
+}
+
+const deleteManyOldPasswordHistoryByUser = `-- name: DeleteManyOldPasswordHistoryByUser :execrows
+DELETE FROM password_histories
+WHERE
+    user_id = $1
+    AND id NOT IN (
+        SELECT id FROM password_histories
+        WHERE user_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2
+    )
+`
+
+type DeleteManyOldPasswordHistoryByUserParams struct {
+	UserID uuid.UUID
+	Keep   int32
+}
+
+func (q *Queries) DeleteManyOldPasswordHistoryByUser(ctx context.Context, arg *DeleteManyOldPasswordHistoryByUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteManyOldPasswordHistoryByUser, arg.UserID, arg.Keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOneLoginThrottle = `-- name: DeleteOneLoginThrottle :execrows
+DELETE FROM login_throttles
+WHERE key = $1
+`
+
+func (q *Queries) DeleteOneLoginThrottle(ctx context.Context, key string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOneLoginThrottle, key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOneUserMfa = `-- name: DeleteOneUserMfa :execrows
+DELETE FROM user_mfa
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteOneUserMfa(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOneUserMfa, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteSoftOneUser = `-- name: DeleteSoftOneUser :one
 UPDATE users
 SET deleted_at = CURRENT_TIMESTAMP
@@ -88,30 +354,158 @@ func (q *Queries) DeleteSoftOneUser(ctx context.Context, id uuid.UUID) (uuid.UUI
 	return id, err
 }
 
+const getManyMfaRecoveryCodeByUser = `-- name: GetManyMfaRecoveryCodeByUser :many
+SELECT
+    id,
+    code_hash
+FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+type GetManyMfaRecoveryCodeByUserRow struct {
+	ID       uuid.UUID
+	CodeHash string
+}
+
+func (q *Queries) GetManyMfaRecoveryCodeByUser(ctx context.Context, userID uuid.UUID) ([]*GetManyMfaRecoveryCodeByUserRow, error) {
+	rows, err := q.db.Query(ctx, getManyMfaRecoveryCodeByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetManyMfaRecoveryCodeByUserRow{}
+	for rows.Next() {
+		var i GetManyMfaRecoveryCodeByUserRow
+		if err := rows.Scan(&i.ID, &i.CodeHash); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManyPasswordHistoryByUser = `-- name: GetManyPasswordHistoryByUser :many
+SELECT password_hash FROM password_histories
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type GetManyPasswordHistoryByUserParams struct {
+	UserID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetManyPasswordHistoryByUser(ctx context.Context, arg *GetManyPasswordHistoryByUserParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getManyPasswordHistoryByUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var password_hash string
+		if err := rows.Scan(&password_hash); err != nil {
+			return nil, err
+		}
+		items = append(items, password_hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManySessionByUser = `-- name: GetManySessionByUser :many
+SELECT
+    family_id,
+    created_at,
+    expires_at
+FROM sessions
+WHERE
+    user_id = $1
+    AND rotated_at IS NULL
+    AND revoked_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at DESC
+`
+
+type GetManySessionByUserRow struct {
+	FamilyID  uuid.UUID
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetManySessionByUser(ctx context.Context, userID uuid.UUID) ([]*GetManySessionByUserRow, error) {
+	rows, err := q.db.Query(ctx, getManySessionByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetManySessionByUserRow{}
+	for rows.Next() {
+		var i GetManySessionByUserRow
+		if err := rows.Scan(&i.FamilyID, &i.CreatedAt, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getManyUser = `-- name: GetManyUser :many
 SELECT
     id,
     email,
-    role
+    role,
+    created_at
 FROM users
 WHERE
     deleted_at IS NULL
-LIMIT $1 OFFSET $2
+    AND ($1::user_role IS NULL OR role = $1)
+    AND ($2::text = '' OR email LIKE $2)
+    AND ($3::timestamptz IS NULL OR created_at >= $3)
+    AND ($4::timestamptz IS NULL OR created_at < $4)
+    AND (NOT $5::boolean OR id > $6)
+ORDER BY id
+LIMIT $7
 `
 
 type GetManyUserParams struct {
-	Limit  int32
-	Offset int32
+	Role          NullUserRole
+	EmailPattern  string
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	HasCursor     bool
+	AfterID       uuid.UUID
+	PageSize      int32
 }
 
 type GetManyUserRow struct {
-	ID    uuid.UUID
-	Email string
-	Role  UserRole
+	ID        uuid.UUID
+	Email     string
+	Role      UserRole
+	CreatedAt pgtype.Timestamptz
 }
 
+// Keyset pagination, one query per sort order so index can be used.
+// Id is UUIDv7 so its order follow creation time.
 func (q *Queries) GetManyUser(ctx context.Context, arg *GetManyUserParams) ([]*GetManyUserRow, error) {
-	rows, err := q.db.Query(ctx, getManyUser, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getManyUser,
+		arg.Role,
+		arg.EmailPattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.HasCursor,
+		arg.AfterID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +513,220 @@ func (q *Queries) GetManyUser(ctx context.Context, arg *GetManyUserParams) ([]*G
 	items := []*GetManyUserRow{}
 	for rows.Next() {
 		var i GetManyUserRow
-		if err := rows.Scan(&i.ID, &i.Email, &i.Role); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManyUserByEmail = `-- name: GetManyUserByEmail :many
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND ($1::user_role IS NULL OR role = $1)
+    AND ($2::text = '' OR email LIKE $2)
+    AND ($3::timestamptz IS NULL OR created_at >= $3)
+    AND ($4::timestamptz IS NULL OR created_at < $4)
+    AND (NOT $5::boolean OR (email, id) > ($6::text, $7::uuid))
+ORDER BY email, id
+LIMIT $8
+`
+
+type GetManyUserByEmailParams struct {
+	Role          NullUserRole
+	EmailPattern  string
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	HasCursor     bool
+	AfterEmail    string
+	AfterID       uuid.UUID
+	PageSize      int32
+}
+
+type GetManyUserByEmailRow struct {
+	ID        uuid.UUID
+	Email     string
+	Role      UserRole
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetManyUserByEmail(ctx context.Context, arg *GetManyUserByEmailParams) ([]*GetManyUserByEmailRow, error) {
+	rows, err := q.db.Query(ctx, getManyUserByEmail,
+		arg.Role,
+		arg.EmailPattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.HasCursor,
+		arg.AfterEmail,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetManyUserByEmailRow{}
+	for rows.Next() {
+		var i GetManyUserByEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManyUserByEmailDesc = `-- name: GetManyUserByEmailDesc :many
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND ($1::user_role IS NULL OR role = $1)
+    AND ($2::text = '' OR email LIKE $2)
+    AND ($3::timestamptz IS NULL OR created_at >= $3)
+    AND ($4::timestamptz IS NULL OR created_at < $4)
+    AND (NOT $5::boolean OR (email, id) < ($6::text, $7::uuid))
+ORDER BY email DESC, id DESC
+LIMIT $8
+`
+
+type GetManyUserByEmailDescParams struct {
+	Role          NullUserRole
+	EmailPattern  string
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	HasCursor     bool
+	AfterEmail    string
+	AfterID       uuid.UUID
+	PageSize      int32
+}
+
+type GetManyUserByEmailDescRow struct {
+	ID        uuid.UUID
+	Email     string
+	Role      UserRole
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetManyUserByEmailDesc(ctx context.Context, arg *GetManyUserByEmailDescParams) ([]*GetManyUserByEmailDescRow, error) {
+	rows, err := q.db.Query(ctx, getManyUserByEmailDesc,
+		arg.Role,
+		arg.EmailPattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.HasCursor,
+		arg.AfterEmail,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetManyUserByEmailDescRow{}
+	for rows.Next() {
+		var i GetManyUserByEmailDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManyUserDesc = `-- name: GetManyUserDesc :many
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND ($1::user_role IS NULL OR role = $1)
+    AND ($2::text = '' OR email LIKE $2)
+    AND ($3::timestamptz IS NULL OR created_at >= $3)
+    AND ($4::timestamptz IS NULL OR created_at < $4)
+    AND (NOT $5::boolean OR id < $6)
+ORDER BY id DESC
+LIMIT $7
+`
+
+type GetManyUserDescParams struct {
+	Role          NullUserRole
+	EmailPattern  string
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	HasCursor     bool
+	AfterID       uuid.UUID
+	PageSize      int32
+}
+
+type GetManyUserDescRow struct {
+	ID        uuid.UUID
+	Email     string
+	Role      UserRole
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetManyUserDesc(ctx context.Context, arg *GetManyUserDescParams) ([]*GetManyUserDescRow, error) {
+	rows, err := q.db.Query(ctx, getManyUserDesc,
+		arg.Role,
+		arg.EmailPattern,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.HasCursor,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*GetManyUserDescRow{}
+	for rows.Next() {
+		var i GetManyUserDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -133,21 +740,114 @@ func (q *Queries) GetManyUser(ctx context.Context, arg *GetManyUserParams) ([]*G
 const getOneCredentialUserByEmail = `-- name: GetOneCredentialUserByEmail :one
 SELECT
     id,
-    password_hash
+    password_hash,
+    role,
+    email_verified_at
 FROM users
 WHERE
     email = $1 AND deleted_at IS NULL
 `
 
 type GetOneCredentialUserByEmailRow struct {
-	ID           uuid.UUID
-	PasswordHash string
+	ID              uuid.UUID
+	PasswordHash    string
+	Role            UserRole
+	EmailVerifiedAt pgtype.Timestamptz
 }
 
 func (q *Queries) GetOneCredentialUserByEmail(ctx context.Context, email string) (*GetOneCredentialUserByEmailRow, error) {
 	row := q.db.QueryRow(ctx, getOneCredentialUserByEmail, email)
 	var i GetOneCredentialUserByEmailRow
-	err := row.Scan(&i.ID, &i.PasswordHash)
+	err := row.Scan(
+		&i.ID,
+		&i.PasswordHash,
+		&i.Role,
+		&i.EmailVerifiedAt,
+	)
+	return &i, err
+}
+
+const getOneLoginThrottle = `-- name: GetOneLoginThrottle :one
+SELECT
+    failures,
+    last_failed_at,
+    locked_until
+FROM login_throttles
+WHERE key = $1
+`
+
+type GetOneLoginThrottleRow struct {
+	Failures     int32
+	LastFailedAt pgtype.Timestamptz
+	LockedUntil  pgtype.Timestamptz
+}
+
+func (q *Queries) GetOneLoginThrottle(ctx context.Context, key string) (*GetOneLoginThrottleRow, error) {
+	row := q.db.QueryRow(ctx, getOneLoginThrottle, key)
+	var i GetOneLoginThrottleRow
+	err := row.Scan(&i.Failures, &i.LastFailedAt, &i.LockedUntil)
+	return &i, err
+}
+
+const getOneMfaRolePolicy = `-- name: GetOneMfaRolePolicy :one
+SELECT required FROM mfa_role_policies
+WHERE role = $1
+`
+
+func (q *Queries) GetOneMfaRolePolicy(ctx context.Context, role UserRole) (bool, error) {
+	row := q.db.QueryRow(ctx, getOneMfaRolePolicy, role)
+	var required bool
+	err := row.Scan(&required)
+	return required, err
+}
+
+const getOnePasswordResetToken = `-- name: GetOnePasswordResetToken :one
+SELECT user_id FROM password_reset_tokens
+WHERE
+    token_hash = $1
+    AND used_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+`
+
+func (q *Queries) GetOnePasswordResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getOnePasswordResetToken, tokenHash)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getOneSession = `-- name: GetOneSession :one
+SELECT
+    id,
+    family_id,
+    user_id,
+    expires_at,
+    rotated_at,
+    revoked_at
+FROM sessions
+WHERE id = $1
+`
+
+type GetOneSessionRow struct {
+	ID        uuid.UUID
+	FamilyID  uuid.UUID
+	UserID    uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+	RotatedAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetOneSession(ctx context.Context, id uuid.UUID) (*GetOneSessionRow, error) {
+	row := q.db.QueryRow(ctx, getOneSession, id)
+	var i GetOneSessionRow
+	err := row.Scan(
+		&i.ID,
+		&i.FamilyID,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+	)
 	return &i, err
 }
 
@@ -156,6 +856,7 @@ SELECT
     id,
     email,
     role,
+    email_verified_at,
     created_at,
     updated_at
 FROM users
@@ -163,11 +864,12 @@ WHERE id = $1 AND deleted_at IS NULL
 `
 
 type GetOneUserRow struct {
-	ID        uuid.UUID
-	Email     string
-	Role      UserRole
-	CreatedAt pgtype.Timestamptz
-	UpdatedAt pgtype.Timestamptz
+	ID              uuid.UUID
+	Email           string
+	Role            UserRole
+	EmailVerifiedAt pgtype.Timestamptz
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
 }
 
 func (q *Queries) GetOneUser(ctx context.Context, id uuid.UUID) (*GetOneUserRow, error) {
@@ -177,15 +879,234 @@ func (q *Queries) GetOneUser(ctx context.Context, id uuid.UUID) (*GetOneUserRow,
 		&i.ID,
 		&i.Email,
 		&i.Role,
+		&i.EmailVerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
+const getOneUserMfa = `-- name: GetOneUserMfa :one
+SELECT
+    secret,
+    last_used_step,
+    confirmed_at
+FROM user_mfa
+WHERE user_id = $1
+`
+
+type GetOneUserMfaRow struct {
+	Secret       string
+	LastUsedStep int64
+	ConfirmedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) GetOneUserMfa(ctx context.Context, userID uuid.UUID) (*GetOneUserMfaRow, error) {
+	row := q.db.QueryRow(ctx, getOneUserMfa, userID)
+	var i GetOneUserMfaRow
+	err := row.Scan(&i.Secret, &i.LastUsedStep, &i.ConfirmedAt)
+	return &i, err
+}
+
+const getOneUserTokenRevocation = `-- name: GetOneUserTokenRevocation :one
+SELECT issued_before FROM user_token_revocations
+WHERE user_id = $1
+`
+
+func (q *Queries) GetOneUserTokenRevocation(ctx context.Context, userID uuid.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getOneUserTokenRevocation, userID)
+	var issued_before pgtype.Timestamptz
+	err := row.Scan(&issued_before)
+	return issued_before, err
+}
+
+const invalidateManyEmailVerificationTokenByUser = `-- name: InvalidateManyEmailVerificationTokenByUser :exec
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) InvalidateManyEmailVerificationTokenByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, invalidateManyEmailVerificationTokenByUser, userID)
+	return err
+}
+
+const invalidateManyPasswordResetTokenByUser = `-- name: InvalidateManyPasswordResetTokenByUser :exec
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) InvalidateManyPasswordResetTokenByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, invalidateManyPasswordResetTokenByUser, userID)
+	return err
+}
+
+const lockOneLoginThrottle = `-- name: LockOneLoginThrottle :exec
+UPDATE login_throttles
+SET locked_until = $2
+WHERE key = $1
+`
+
+type LockOneLoginThrottleParams struct {
+	Key         string
+	LockedUntil pgtype.Timestamptz
+}
+
+func (q *Queries) LockOneLoginThrottle(ctx context.Context, arg *LockOneLoginThrottleParams) error {
+	_, err := q.db.Exec(ctx, lockOneLoginThrottle, arg.Key, arg.LockedUntil)
+	return err
+}
+
+const rehashOnePasswordUser = `-- name: RehashOnePasswordUser :execrows
+UPDATE users
+SET password_hash = $1
+WHERE
+    id = $2
+    AND password_hash = $3
+    AND deleted_at IS NULL
+`
+
+type RehashOnePasswordUserParams struct {
+	NewPasswordHash string
+	ID              uuid.UUID
+	OldPasswordHash string
+}
+
+func (q *Queries) RehashOnePasswordUser(ctx context.Context, arg *RehashOnePasswordUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rehashOnePasswordUser, arg.NewPasswordHash, arg.ID, arg.OldPasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeAllSessionByUser = `-- name: RevokeAllSessionByUser :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeAllSessionByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAllSessionByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeFamilySession = `-- name: RevokeFamilySession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeFamilySessionParams struct {
+	FamilyID uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) RevokeFamilySession(ctx context.Context, arg *RevokeFamilySessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeFamilySession, arg.FamilyID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rotateOneSession = `-- name: RotateOneSession :one
+UPDATE sessions
+SET rotated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1
+    AND rotated_at IS NULL
+    AND revoked_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+RETURNING family_id
+`
+
+func (q *Queries) RotateOneSession(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, rotateOneSession, id)
+	var family_id uuid.UUID
+	err := row.Scan(&family_id)
+	return family_id, err
+}
+
+const searchUser = `-- name: SearchUser :many
+SELECT
+    id,
+    email,
+    role,
+    created_at,
+    (
+        ts_rank(to_tsvector('simple', translate(email, '@._-+', '     ')), to_tsquery('simple', $1::text))
+        + word_similarity($2::text, email)
+    )::real AS rank
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND (cardinality($3::text[]) = 0 OR role::text = ANY($3::text[]))
+    AND (
+        ($1::text <> '' AND to_tsvector('simple', translate(email, '@._-+', '     ')) @@ to_tsquery('simple', $1::text))
+        OR email ILIKE $4::text
+        OR $2::text <% email
+    )
+ORDER BY rank DESC, id
+LIMIT $5
+`
+
+type SearchUserParams struct {
+	WordQuery       string
+	Query           string
+	Roles           []string
+	ContainsPattern string
+	PageSize        int32
+}
+
+type SearchUserRow struct {
+	ID        uuid.UUID
+	Email     string
+	Role      UserRole
+	CreatedAt pgtype.Timestamptz
+	Rank      float32
+}
+
+// Rank combine word prefix match and trigram similarity of whole query
+func (q *Queries) SearchUser(ctx context.Context, arg *SearchUserParams) ([]*SearchUserRow, error) {
+	rows, err := q.db.Query(ctx, searchUser,
+		arg.WordQuery,
+		arg.Query,
+		arg.Roles,
+		arg.ContainsPattern,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SearchUserRow{}
+	for rows.Next() {
+		var i SearchUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateOneEmailUser = `-- name: UpdateOneEmailUser :one
 UPDATE users
-SET email = $2
+SET email = $2, email_verified_at = NULL, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING id
 `
@@ -204,7 +1125,7 @@ func (q *Queries) UpdateOneEmailUser(ctx context.Context, arg *UpdateOneEmailUse
 
 const updateOnePasswordUser = `-- name: UpdateOnePasswordUser :one
 UPDATE users
-SET password_hash = $2
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING id
 `
@@ -223,7 +1144,7 @@ func (q *Queries) UpdateOnePasswordUser(ctx context.Context, arg *UpdateOnePassw
 
 const updateOneRoleUser = `-- name: UpdateOneRoleUser :one
 UPDATE users
-SET role = $2
+SET role = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING id
 `
@@ -239,3 +1160,182 @@ func (q *Queries) UpdateOneRoleUser(ctx context.Context, arg *UpdateOneRoleUserP
 	err := row.Scan(&id)
 	return id, err
 }
+
+const upsertOneFailureLoginThrottle = `-- name: UpsertOneFailureLoginThrottle :one
+INSERT INTO login_throttles
+(key, failures, last_failed_at)
+VALUES
+($1, 1, CURRENT_TIMESTAMP)
+ON CONFLICT (key) DO UPDATE
+SET
+    failures = CASE
+        WHEN login_throttles.last_failed_at < $2 THEN 1
+        ELSE login_throttles.failures + 1
+    END,
+    last_failed_at = CURRENT_TIMESTAMP
+RETURNING failures
+`
+
+type UpsertOneFailureLoginThrottleParams struct {
+	Key          string
+	LastFailedAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertOneFailureLoginThrottle(ctx context.Context, arg *UpsertOneFailureLoginThrottleParams) (int32, error) {
+	row := q.db.QueryRow(ctx, upsertOneFailureLoginThrottle, arg.Key, arg.LastFailedAt)
+	var failures int32
+	err := row.Scan(&failures)
+	return failures, err
+}
+
+const upsertOneMfaRolePolicy = `-- name: UpsertOneMfaRolePolicy :exec
+INSERT INTO mfa_role_policies
+(role, required)
+VALUES
+($1, $2)
+ON CONFLICT (role) DO UPDATE
+SET required = EXCLUDED.required
+`
+
+type UpsertOneMfaRolePolicyParams struct {
+	Role     UserRole
+	Required bool
+}
+
+func (q *Queries) UpsertOneMfaRolePolicy(ctx context.Context, arg *UpsertOneMfaRolePolicyParams) error {
+	_, err := q.db.Exec(ctx, upsertOneMfaRolePolicy, arg.Role, arg.Required)
+	return err
+}
+
+const upsertOnePendingUserMfa = `-- name: UpsertOnePendingUserMfa :execrows
+INSERT INTO user_mfa
+(user_id, secret)
+VALUES
+($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+WHERE user_mfa.confirmed_at IS NULL
+`
+
+type UpsertOnePendingUserMfaParams struct {
+	UserID uuid.UUID
+	Secret string
+}
+
+func (q *Queries) UpsertOnePendingUserMfa(ctx context.Context, arg *UpsertOnePendingUserMfaParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertOnePendingUserMfa, arg.UserID, arg.Secret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertOneUserTokenRevocation = `-- name: UpsertOneUserTokenRevocation :exec
+INSERT INTO user_token_revocations
+(user_id, issued_before)
+VALUES
+($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET issued_before = GREATEST(user_token_revocations.issued_before, EXCLUDED.issued_before)
+`
+
+type UpsertOneUserTokenRevocationParams struct {
+	UserID       uuid.UUID
+	IssuedBefore pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertOneUserTokenRevocation(ctx context.Context, arg *UpsertOneUserTokenRevocationParams) error {
+	_, err := q.db.Exec(ctx, upsertOneUserTokenRevocation, arg.UserID, arg.IssuedBefore)
+	return err
+}
+
+const useOneEmailVerificationToken = `-- name: UseOneEmailVerificationToken :one
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE
+    token_hash = $1
+    AND used_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id, email
+`
+
+type UseOneEmailVerificationTokenRow struct {
+	UserID uuid.UUID
+	Email  string
+}
+
+func (q *Queries) UseOneEmailVerificationToken(ctx context.Context, tokenHash string) (*UseOneEmailVerificationTokenRow, error) {
+	row := q.db.QueryRow(ctx, useOneEmailVerificationToken, tokenHash)
+	var i UseOneEmailVerificationTokenRow
+	err := row.Scan(&i.UserID, &i.Email)
+	return &i, err
+}
+
+const useOneMfaRecoveryCode = `-- name: UseOneMfaRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) UseOneMfaRecoveryCode(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, useOneMfaRecoveryCode, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useOnePasswordResetToken = `-- name: UseOnePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE
+    token_hash = $1
+    AND used_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id
+`
+
+func (q *Queries) UseOnePasswordResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, useOnePasswordResetToken, tokenHash)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const useStepUserMfa = `-- name: UseStepUserMfa :execrows
+UPDATE user_mfa
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2
+`
+
+type UseStepUserMfaParams struct {
+	UserID       uuid.UUID
+	LastUsedStep int64
+}
+
+func (q *Queries) UseStepUserMfa(ctx context.Context, arg *UseStepUserMfaParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useStepUserMfa, arg.UserID, arg.LastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const verifyOneEmailUser = `-- name: VerifyOneEmailUser :one
+UPDATE users
+SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND email = $2 AND deleted_at IS NULL
+RETURNING id
+`
+
+type VerifyOneEmailUserParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) VerifyOneEmailUser(ctx context.Context, arg *VerifyOneEmailUserParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, verifyOneEmailUser, arg.ID, arg.Email)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_users_id_deleted_at;
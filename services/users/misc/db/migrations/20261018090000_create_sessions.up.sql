@@ -0,0 +1,15 @@
+CREATE TABLE sessions (
+    -- PK, equal to refresh token jti
+    id uuid PRIMARY KEY,
+    -- Rotated refresh tokens share family, equal to token sid
+    family_id uuid NOT NULL,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- Timestamp
+    created_at timestamptz NOT NULL DEFAULT current_timestamp,
+    expires_at timestamptz NOT NULL,
+    rotated_at timestamptz,
+    revoked_at timestamptz
+);
+
+CREATE INDEX idx_sessions_family_id ON sessions (family_id);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
@@ -0,0 +1,15 @@
+CREATE TABLE token_revocations (
+    -- PK, revoked token jti
+    jti uuid PRIMARY KEY,
+    -- Entry can be removed once token expired
+    expires_at timestamptz NOT NULL
+);
+
+CREATE TABLE user_token_revocations (
+    -- PK
+    user_id uuid PRIMARY KEY,
+    -- Every token of user issued before this time is revoked
+    issued_before timestamptz NOT NULL
+);
+
+CREATE INDEX idx_token_revocations_expires_at ON token_revocations (expires_at);
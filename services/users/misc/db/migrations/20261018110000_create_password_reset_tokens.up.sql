@@ -0,0 +1,13 @@
+CREATE TABLE password_reset_tokens (
+    -- PK
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- SHA-256 hex digest of token sent to user
+    token_hash varchar(64) NOT NULL UNIQUE,
+    -- Timestamp
+    created_at timestamptz NOT NULL DEFAULT current_timestamp,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
@@ -0,0 +1,20 @@
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+
+-- Existing account are considered verified
+UPDATE users SET email_verified_at = created_at;
+
+CREATE TABLE email_verification_tokens (
+    -- PK
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- Email address the token was sent to
+    email varchar(255) NOT NULL,
+    -- SHA-256 hex digest of token sent to user
+    token_hash varchar(64) NOT NULL UNIQUE,
+    -- Timestamp
+    created_at timestamptz NOT NULL DEFAULT current_timestamp,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens (user_id);
@@ -0,0 +1,9 @@
+CREATE TABLE login_throttles (
+    -- PK, throttled subject prefixed by scope e.g. user:<id> or ip:<addr>
+    key varchar(128) PRIMARY KEY,
+    -- Consecutive failed login attempt
+    failures integer NOT NULL DEFAULT 0,
+    -- Timestamp
+    last_failed_at timestamptz NOT NULL,
+    locked_until timestamptz
+);
@@ -0,0 +1,31 @@
+CREATE TABLE user_mfa (
+    -- PK
+    user_id uuid PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    -- Base32 encoded TOTP secret
+    secret varchar(64) NOT NULL,
+    -- Latest accepted TOTP time step, used to prevent code replay
+    last_used_step bigint NOT NULL DEFAULT 0,
+    -- Timestamp
+    created_at timestamptz NOT NULL DEFAULT current_timestamp,
+    confirmed_at timestamptz
+);
+
+CREATE TABLE mfa_recovery_codes (
+    -- PK
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- Argon2id hash of recovery code
+    code_hash text NOT NULL,
+    -- Timestamp
+    created_at timestamptz NOT NULL DEFAULT current_timestamp,
+    used_at timestamptz
+);
+
+CREATE TABLE mfa_role_policies (
+    -- PK
+    role user_role PRIMARY KEY,
+    -- Whether user with role must use two-factor authentication
+    required boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes (user_id);
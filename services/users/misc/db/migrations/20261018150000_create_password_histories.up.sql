@@ -0,0 +1,15 @@
+CREATE TABLE password_histories (
+    -- PK
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- Argon2id hash of previously used password
+    password_hash text NOT NULL,
+    -- Timestamp
+    created_at timestamptz NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX idx_password_histories_user_id_created_at ON password_histories (user_id, created_at DESC);
+
+-- Seed history with current password of existing users
+INSERT INTO password_histories (id, user_id, password_hash, created_at)
+SELECT gen_random_uuid(), id, password_hash, updated_at FROM users;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_active_created_at;
+DROP INDEX IF EXISTS idx_users_active_email_pattern;
+DROP INDEX IF EXISTS idx_users_active_email_id;
+DROP INDEX IF EXISTS idx_users_active_id;
@@ -0,0 +1,5 @@
+-- Keyset pagination and filters of GetManyUser only scan active users
+CREATE INDEX idx_users_active_id ON users (id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_active_email_id ON users (email, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_active_email_pattern ON users (email text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_active_created_at ON users (created_at) WHERE deleted_at IS NULL;
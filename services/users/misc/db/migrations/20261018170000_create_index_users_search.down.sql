@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_active_email_trgm;
+DROP INDEX IF EXISTS idx_users_active_email_words;
+-- pg_trgm extension is kept since other objects may depend on it
@@ -0,0 +1,11 @@
+-- Email is split into words so local part can be matched by word prefix.
+-- Expression must match SearchUser query for index to be used.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_active_email_words ON users
+USING gin (to_tsvector('simple', translate(email, '@._-+', '     ')))
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_users_active_email_trgm ON users
+USING gin (email gin_trgm_ops)
+WHERE deleted_at IS NULL;
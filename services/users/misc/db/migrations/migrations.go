@@ -0,0 +1,8 @@
+// Package migrations embed SQL migrations of users database. File is
+// named <version>_<name>.up.sql and <version>_<name>.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
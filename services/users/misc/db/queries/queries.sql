@@ -10,6 +10,7 @@ SELECT
     id,
     email,
     role,
+    email_verified_at,
     created_at,
     updated_at
 FROM users
@@ -18,36 +19,130 @@ WHERE id = $1 AND deleted_at IS NULL;
 -- name: GetOneCredentialUserByEmail :one
 SELECT
     id,
-    password_hash
+    password_hash,
+    role,
+    email_verified_at
 FROM users
 WHERE
     email = $1 AND deleted_at IS NULL;
 
 -- name: GetManyUser :many
+-- Keyset pagination, one query per sort order so index can be used.
+-- Id is UUIDv7 so its order follow creation time.
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+    AND (@email_pattern::text = '' OR email LIKE @email_pattern)
+    AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+    AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+    AND (NOT @has_cursor::boolean OR id > @after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_size);
+
+-- name: GetManyUserDesc :many
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+    AND (@email_pattern::text = '' OR email LIKE @email_pattern)
+    AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+    AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+    AND (NOT @has_cursor::boolean OR id < @after_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetManyUserByEmail :many
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+    AND (@email_pattern::text = '' OR email LIKE @email_pattern)
+    AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+    AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+    AND (NOT @has_cursor::boolean OR (email, id) > (@after_email::text, @after_id::uuid))
+ORDER BY email, id
+LIMIT sqlc.arg(page_size);
+
+-- name: GetManyUserByEmailDesc :many
+SELECT
+    id,
+    email,
+    role,
+    created_at
+FROM users
+WHERE
+    deleted_at IS NULL
+    AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+    AND (@email_pattern::text = '' OR email LIKE @email_pattern)
+    AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+    AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+    AND (NOT @has_cursor::boolean OR (email, id) < (@after_email::text, @after_id::uuid))
+ORDER BY email DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: CountManyUser :one
+SELECT COUNT(*) FROM users
+WHERE
+    deleted_at IS NULL
+    AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+    AND (@email_pattern::text = '' OR email LIKE @email_pattern)
+    AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after))
+    AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before));
+
+-- name: SearchUser :many
+-- Rank combine word prefix match and trigram similarity of whole query
 SELECT
     id,
     email,
-    role
+    role,
+    created_at,
+    (
+        ts_rank(to_tsvector('simple', translate(email, '@._-+', '     ')), to_tsquery('simple', @word_query::text))
+        + word_similarity(@query::text, email)
+    )::real AS rank
 FROM users
 WHERE
     deleted_at IS NULL
-LIMIT $1 OFFSET $2;
+    AND (cardinality(@roles::text[]) = 0 OR role::text = ANY(@roles::text[]))
+    AND (
+        (@word_query::text <> '' AND to_tsvector('simple', translate(email, '@._-+', '     ')) @@ to_tsquery('simple', @word_query::text))
+        OR email ILIKE @contains_pattern::text
+        OR @query::text <% email
+    )
+ORDER BY rank DESC, id
+LIMIT sqlc.arg(page_size);
 
 -- name: UpdateOnePasswordUser :one
 UPDATE users
-SET password_hash = $2
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING id;
 
 -- name: UpdateOneEmailUser :one
 UPDATE users
-SET email = $2
+SET email = $2, email_verified_at = NULL, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING id;
 
 -- name: UpdateOneRoleUser :one
 UPDATE users
-SET role = $2
+SET role = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING id;
 
@@ -64,8 +159,268 @@ RETURNING id;
 
 -- name: CountEmailUser :one
 SELECT COUNT(*) FROM users
-WHERE email = $1;
+WHERE email = $1 AND deleted_at IS NULL;
 
 -- name: CountIDUser :one
 SELECT COUNT(*) FROM users
 WHERE id = $1;
+
+-- name: CreateOneSession :one
+INSERT INTO sessions
+(id, family_id, user_id, expires_at)
+VALUES
+($1, $2, $3, $4)
+RETURNING id;
+
+-- name: GetOneSession :one
+SELECT
+    id,
+    family_id,
+    user_id,
+    expires_at,
+    rotated_at,
+    revoked_at
+FROM sessions
+WHERE id = $1;
+
+-- name: GetManySessionByUser :many
+SELECT
+    family_id,
+    created_at,
+    expires_at
+FROM sessions
+WHERE
+    user_id = $1
+    AND rotated_at IS NULL
+    AND revoked_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at DESC;
+
+-- name: RotateOneSession :one
+UPDATE sessions
+SET rotated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1
+    AND rotated_at IS NULL
+    AND revoked_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+RETURNING family_id;
+
+-- name: RevokeFamilySession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeAllSessionByUser :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: CreateOneTokenRevocation :exec
+INSERT INTO token_revocations
+(jti, expires_at)
+VALUES
+($1, $2)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: CountTokenRevocation :one
+SELECT COUNT(*) FROM token_revocations
+WHERE jti = $1;
+
+-- name: DeleteExpiredTokenRevocation :execrows
+DELETE FROM token_revocations
+WHERE expires_at < CURRENT_TIMESTAMP;
+
+-- name: UpsertOneUserTokenRevocation :exec
+INSERT INTO user_token_revocations
+(user_id, issued_before)
+VALUES
+($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET issued_before = GREATEST(user_token_revocations.issued_before, EXCLUDED.issued_before);
+
+-- name: GetOneUserTokenRevocation :one
+SELECT issued_before FROM user_token_revocations
+WHERE user_id = $1;
+
+-- name: CreateOnePasswordResetToken :one
+INSERT INTO password_reset_tokens
+(id, user_id, token_hash, expires_at)
+VALUES
+($1, $2, $3, $4)
+RETURNING id;
+
+-- name: UseOnePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE
+    token_hash = $1
+    AND used_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id;
+
+-- name: InvalidateManyPasswordResetTokenByUser :exec
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CreateOneEmailVerificationToken :one
+INSERT INTO email_verification_tokens
+(id, user_id, email, token_hash, expires_at)
+VALUES
+($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: UseOneEmailVerificationToken :one
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE
+    token_hash = $1
+    AND used_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id, email;
+
+-- name: InvalidateManyEmailVerificationTokenByUser :exec
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: VerifyOneEmailUser :one
+UPDATE users
+SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND email = $2 AND deleted_at IS NULL
+RETURNING id;
+
+-- name: GetOneLoginThrottle :one
+SELECT
+    failures,
+    last_failed_at,
+    locked_until
+FROM login_throttles
+WHERE key = $1;
+
+-- name: UpsertOneFailureLoginThrottle :one
+INSERT INTO login_throttles
+(key, failures, last_failed_at)
+VALUES
+($1, 1, CURRENT_TIMESTAMP)
+ON CONFLICT (key) DO UPDATE
+SET
+    failures = CASE
+        WHEN login_throttles.last_failed_at < $2 THEN 1
+        ELSE login_throttles.failures + 1
+    END,
+    last_failed_at = CURRENT_TIMESTAMP
+RETURNING failures;
+
+-- name: LockOneLoginThrottle :exec
+UPDATE login_throttles
+SET locked_until = $2
+WHERE key = $1;
+
+-- name: DeleteOneLoginThrottle :execrows
+DELETE FROM login_throttles
+WHERE key = $1;
+
+-- name: GetOneUserMfa :one
+SELECT
+    secret,
+    last_used_step,
+    confirmed_at
+FROM user_mfa
+WHERE user_id = $1;
+
+-- name: UpsertOnePendingUserMfa :execrows
+INSERT INTO user_mfa
+(user_id, secret)
+VALUES
+($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+WHERE user_mfa.confirmed_at IS NULL;
+
+-- name: ConfirmOneUserMfa :execrows
+UPDATE user_mfa
+SET confirmed_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- name: UseStepUserMfa :execrows
+UPDATE user_mfa
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteOneUserMfa :execrows
+DELETE FROM user_mfa
+WHERE user_id = $1;
+
+-- name: CreateOneMfaRecoveryCode :exec
+INSERT INTO mfa_recovery_codes
+(id, user_id, code_hash)
+VALUES
+($1, $2, $3);
+
+-- name: GetManyMfaRecoveryCodeByUser :many
+SELECT
+    id,
+    code_hash
+FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: UseOneMfaRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: DeleteManyMfaRecoveryCodeByUser :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: GetOneMfaRolePolicy :one
+SELECT required FROM mfa_role_policies
+WHERE role = $1;
+
+-- name: UpsertOneMfaRolePolicy :exec
+INSERT INTO mfa_role_policies
+(role, required)
+VALUES
+($1, $2)
+ON CONFLICT (role) DO UPDATE
+SET required = EXCLUDED.required;
+
+-- name: RehashOnePasswordUser :execrows
+UPDATE users
+SET password_hash = sqlc.arg(new_password_hash)
+WHERE
+    id = sqlc.arg(id)
+    AND password_hash = sqlc.arg(old_password_hash)
+    AND deleted_at IS NULL;
+
+-- name: GetOnePasswordResetToken :one
+SELECT user_id FROM password_reset_tokens
+WHERE
+    token_hash = $1
+    AND used_at IS NULL
+    AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: CreateOnePasswordHistory :exec
+INSERT INTO password_histories
+(id, user_id, password_hash)
+VALUES
+($1, $2, $3);
+
+-- name: GetManyPasswordHistoryByUser :many
+SELECT password_hash FROM password_histories
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: DeleteManyOldPasswordHistoryByUser :execrows
+DELETE FROM password_histories
+WHERE
+    user_id = sqlc.arg(user_id)
+    AND id NOT IN (
+        SELECT id FROM password_histories
+        WHERE user_id = sqlc.arg(user_id)
+        ORDER BY created_at DESC
+        LIMIT sqlc.arg(keep)
+    );
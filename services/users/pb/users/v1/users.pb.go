@@ -78,6 +78,59 @@ func (UserRole) EnumDescriptor() ([]byte, []int) {
 	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{0}
 }
 
+// User list sort order
+type UserSort int32
+
+const (
+	UserSort_CreatedAsc  UserSort = 0
+	UserSort_CreatedDesc UserSort = 1
+	UserSort_EmailAsc    UserSort = 2
+	UserSort_EmailDesc   UserSort = 3
+)
+
+// Enum value maps for UserSort.
+var (
+	UserSort_name = map[int32]string{
+		0: "CreatedAsc",
+		1: "CreatedDesc",
+		2: "EmailAsc",
+		3: "EmailDesc",
+	}
+	UserSort_value = map[string]int32{
+		"CreatedAsc":  0,
+		"CreatedDesc": 1,
+		"EmailAsc":    2,
+		"EmailDesc":   3,
+	}
+)
+
+func (x UserSort) Enum() *UserSort {
+	p := new(UserSort)
+	*p = x
+	return p
+}
+
+func (x UserSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_users_v1_users_proto_enumTypes[1].Descriptor()
+}
+
+func (UserSort) Type() protoreflect.EnumType {
+	return &file_pb_users_v1_users_proto_enumTypes[1]
+}
+
+func (x UserSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserSort.Descriptor instead.
+func (UserSort) EnumDescriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{1}
+}
+
 // Create user message
 type CreateOneUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -189,6 +242,7 @@ type UserSummary struct {
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	Role          UserRole               `protobuf:"varint,3,opt,name=role,proto3,enum=pb.users.pbuser.UserRole" json:"role,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -244,10 +298,26 @@ func (x *UserSummary) GetRole() UserRole {
 	return UserRole_Unspecified
 }
 
+func (x *UserSummary) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type GetManyUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         uint64                 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        uint64                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum users returned, default to 20 and capped at 100
+	PageSize uint32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque cursor returned as next_page_token by previous call. Sort
+	// and filters must not be changed while paging.
+	PageToken string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Sort      UserSort `protobuf:"varint,5,opt,name=sort,proto3,enum=pb.users.pbuser.UserSort" json:"sort,omitempty"`
+	// Optional filters
+	Role          UserRole               `protobuf:"varint,6,opt,name=role,proto3,enum=pb.users.pbuser.UserRole" json:"role,omitempty"`
+	EmailPrefix   string                 `protobuf:"bytes,7,opt,name=email_prefix,json=emailPrefix,proto3" json:"email_prefix,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -282,23 +352,62 @@ func (*GetManyUserRequest) Descriptor() ([]byte, []int) {
 	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetManyUserRequest) GetLimit() uint64 {
+func (x *GetManyUserRequest) GetPageSize() uint32 {
 	if x != nil {
-		return x.Limit
+		return x.PageSize
 	}
 	return 0
 }
 
-func (x *GetManyUserRequest) GetOffset() uint64 {
+func (x *GetManyUserRequest) GetPageToken() string {
 	if x != nil {
-		return x.Offset
+		return x.PageToken
 	}
-	return 0
+	return ""
+}
+
+func (x *GetManyUserRequest) GetSort() UserSort {
+	if x != nil {
+		return x.Sort
+	}
+	return UserSort_CreatedAsc
+}
+
+func (x *GetManyUserRequest) GetRole() UserRole {
+	if x != nil {
+		return x.Role
+	}
+	return UserRole_Unspecified
+}
+
+func (x *GetManyUserRequest) GetEmailPrefix() string {
+	if x != nil {
+		return x.EmailPrefix
+	}
+	return ""
+}
+
+func (x *GetManyUserRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *GetManyUserRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
 }
 
 type GetManyUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*UserSummary         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*UserSummary         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// Empty when there is no more page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of users matching filters across every page
+	TotalCount    uint64 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -340,6 +449,191 @@ func (x *GetManyUserResponse) GetUsers() []*UserSummary {
 	return nil
 }
 
+func (x *GetManyUserResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetManyUserResponse) GetTotalCount() uint64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// Search user message
+type SearchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Partial email matched by word prefix, substring and similarity.
+	// User has no name column so email is the only searched field.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Optional role filter, empty match every role
+	Roles []UserRole `protobuf:"varint,2,rep,packed,name=roles,proto3,enum=pb.users.pbuser.UserRole" json:"roles,omitempty"`
+	// Maximum results returned, default to 20 and capped at 100
+	PageSize      uint32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetRoles() []UserRole {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *SearchUsersRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type UserSearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *UserSummary           `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Relevance of user to query, higher is better
+	Rank float32 `protobuf:"fixed32,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	// HTML escaped email with matched parts wrapped in <mark> tag
+	Highlight     string `protobuf:"bytes,3,opt,name=highlight,proto3" json:"highlight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserSearchResult) Reset() {
+	*x = UserSearchResult{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSearchResult) ProtoMessage() {}
+
+func (x *UserSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSearchResult.ProtoReflect.Descriptor instead.
+func (*UserSearchResult) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UserSearchResult) GetUser() *UserSummary {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserSearchResult) GetRank() float32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *UserSearchResult) GetHighlight() string {
+	if x != nil {
+		return x.Highlight
+	}
+	return ""
+}
+
+type SearchUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*UserSearchResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersResponse) ProtoMessage() {}
+
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchUsersResponse) GetResults() []*UserSearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
 // Get Detail user
 type GetOneUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -350,7 +644,7 @@ type GetOneUserRequest struct {
 
 func (x *GetOneUserRequest) Reset() {
 	*x = GetOneUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[5]
+	mi := &file_pb_users_v1_users_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -362,7 +656,7 @@ func (x *GetOneUserRequest) String() string {
 func (*GetOneUserRequest) ProtoMessage() {}
 
 func (x *GetOneUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[5]
+	mi := &file_pb_users_v1_users_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,7 +669,7 @@ func (x *GetOneUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOneUserRequest.ProtoReflect.Descriptor instead.
 func (*GetOneUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{5}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetOneUserRequest) GetId() string {
@@ -386,19 +680,20 @@ func (x *GetOneUserRequest) GetId() string {
 }
 
 type GetOneUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email           string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Role            string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	CreatedAt       string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	EmailVerifiedAt string                 `protobuf:"bytes,6,opt,name=email_verified_at,json=emailVerifiedAt,proto3" json:"email_verified_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetOneUserResponse) Reset() {
 	*x = GetOneUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[6]
+	mi := &file_pb_users_v1_users_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -410,7 +705,7 @@ func (x *GetOneUserResponse) String() string {
 func (*GetOneUserResponse) ProtoMessage() {}
 
 func (x *GetOneUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[6]
+	mi := &file_pb_users_v1_users_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -423,7 +718,7 @@ func (x *GetOneUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOneUserResponse.ProtoReflect.Descriptor instead.
 func (*GetOneUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{6}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetOneUserResponse) GetId() string {
@@ -461,6 +756,13 @@ func (x *GetOneUserResponse) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *GetOneUserResponse) GetEmailVerifiedAt() string {
+	if x != nil {
+		return x.EmailVerifiedAt
+	}
+	return ""
+}
+
 // Get credential user by email
 type GetOneCredentialUserByEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -471,7 +773,7 @@ type GetOneCredentialUserByEmailRequest struct {
 
 func (x *GetOneCredentialUserByEmailRequest) Reset() {
 	*x = GetOneCredentialUserByEmailRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[7]
+	mi := &file_pb_users_v1_users_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -483,7 +785,7 @@ func (x *GetOneCredentialUserByEmailRequest) String() string {
 func (*GetOneCredentialUserByEmailRequest) ProtoMessage() {}
 
 func (x *GetOneCredentialUserByEmailRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[7]
+	mi := &file_pb_users_v1_users_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,7 +798,7 @@ func (x *GetOneCredentialUserByEmailRequest) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use GetOneCredentialUserByEmailRequest.ProtoReflect.Descriptor instead.
 func (*GetOneCredentialUserByEmailRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{7}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetOneCredentialUserByEmailRequest) GetEmail() string {
@@ -516,7 +818,7 @@ type GetOneCredentialUserByEmailResponse struct {
 
 func (x *GetOneCredentialUserByEmailResponse) Reset() {
 	*x = GetOneCredentialUserByEmailResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[8]
+	mi := &file_pb_users_v1_users_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -528,7 +830,7 @@ func (x *GetOneCredentialUserByEmailResponse) String() string {
 func (*GetOneCredentialUserByEmailResponse) ProtoMessage() {}
 
 func (x *GetOneCredentialUserByEmailResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[8]
+	mi := &file_pb_users_v1_users_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -541,7 +843,7 @@ func (x *GetOneCredentialUserByEmailResponse) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use GetOneCredentialUserByEmailResponse.ProtoReflect.Descriptor instead.
 func (*GetOneCredentialUserByEmailResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{8}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetOneCredentialUserByEmailResponse) GetId() string {
@@ -560,16 +862,18 @@ func (x *GetOneCredentialUserByEmailResponse) GetPasswordHash() string {
 
 // Update password
 type UpdateOnePasswordUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Password string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// Required unless caller is staff
+	CurrentPassword string `protobuf:"bytes,3,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateOnePasswordUserRequest) Reset() {
 	*x = UpdateOnePasswordUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[9]
+	mi := &file_pb_users_v1_users_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -581,7 +885,7 @@ func (x *UpdateOnePasswordUserRequest) String() string {
 func (*UpdateOnePasswordUserRequest) ProtoMessage() {}
 
 func (x *UpdateOnePasswordUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[9]
+	mi := &file_pb_users_v1_users_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -594,7 +898,7 @@ func (x *UpdateOnePasswordUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOnePasswordUserRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOnePasswordUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{9}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *UpdateOnePasswordUserRequest) GetId() string {
@@ -611,6 +915,13 @@ func (x *UpdateOnePasswordUserRequest) GetPassword() string {
 	return ""
 }
 
+func (x *UpdateOnePasswordUserRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
 type UpdateOnePasswordUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -620,7 +931,7 @@ type UpdateOnePasswordUserResponse struct {
 
 func (x *UpdateOnePasswordUserResponse) Reset() {
 	*x = UpdateOnePasswordUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[10]
+	mi := &file_pb_users_v1_users_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -632,7 +943,7 @@ func (x *UpdateOnePasswordUserResponse) String() string {
 func (*UpdateOnePasswordUserResponse) ProtoMessage() {}
 
 func (x *UpdateOnePasswordUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[10]
+	mi := &file_pb_users_v1_users_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -645,7 +956,7 @@ func (x *UpdateOnePasswordUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOnePasswordUserResponse.ProtoReflect.Descriptor instead.
 func (*UpdateOnePasswordUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{10}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UpdateOnePasswordUserResponse) GetId() string {
@@ -666,7 +977,7 @@ type UpdateOneEmailUserRequest struct {
 
 func (x *UpdateOneEmailUserRequest) Reset() {
 	*x = UpdateOneEmailUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[11]
+	mi := &file_pb_users_v1_users_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -678,7 +989,7 @@ func (x *UpdateOneEmailUserRequest) String() string {
 func (*UpdateOneEmailUserRequest) ProtoMessage() {}
 
 func (x *UpdateOneEmailUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[11]
+	mi := &file_pb_users_v1_users_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -691,7 +1002,7 @@ func (x *UpdateOneEmailUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOneEmailUserRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOneEmailUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{11}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UpdateOneEmailUserRequest) GetId() string {
@@ -717,7 +1028,7 @@ type UpdateOneEmailUserResponse struct {
 
 func (x *UpdateOneEmailUserResponse) Reset() {
 	*x = UpdateOneEmailUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[12]
+	mi := &file_pb_users_v1_users_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -729,7 +1040,7 @@ func (x *UpdateOneEmailUserResponse) String() string {
 func (*UpdateOneEmailUserResponse) ProtoMessage() {}
 
 func (x *UpdateOneEmailUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[12]
+	mi := &file_pb_users_v1_users_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -742,7 +1053,7 @@ func (x *UpdateOneEmailUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOneEmailUserResponse.ProtoReflect.Descriptor instead.
 func (*UpdateOneEmailUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{12}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UpdateOneEmailUserResponse) GetId() string {
@@ -763,7 +1074,7 @@ type UpdateOneRoleUserRequest struct {
 
 func (x *UpdateOneRoleUserRequest) Reset() {
 	*x = UpdateOneRoleUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[13]
+	mi := &file_pb_users_v1_users_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -775,7 +1086,7 @@ func (x *UpdateOneRoleUserRequest) String() string {
 func (*UpdateOneRoleUserRequest) ProtoMessage() {}
 
 func (x *UpdateOneRoleUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[13]
+	mi := &file_pb_users_v1_users_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -788,7 +1099,7 @@ func (x *UpdateOneRoleUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOneRoleUserRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOneRoleUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{13}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *UpdateOneRoleUserRequest) GetId() string {
@@ -814,7 +1125,7 @@ type UpdateOneRoleUserResponse struct {
 
 func (x *UpdateOneRoleUserResponse) Reset() {
 	*x = UpdateOneRoleUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[14]
+	mi := &file_pb_users_v1_users_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -826,7 +1137,7 @@ func (x *UpdateOneRoleUserResponse) String() string {
 func (*UpdateOneRoleUserResponse) ProtoMessage() {}
 
 func (x *UpdateOneRoleUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[14]
+	mi := &file_pb_users_v1_users_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -839,7 +1150,7 @@ func (x *UpdateOneRoleUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOneRoleUserResponse.ProtoReflect.Descriptor instead.
 func (*UpdateOneRoleUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{14}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *UpdateOneRoleUserResponse) GetId() string {
@@ -859,7 +1170,7 @@ type DeleteSoftOneUserRequest struct {
 
 func (x *DeleteSoftOneUserRequest) Reset() {
 	*x = DeleteSoftOneUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[15]
+	mi := &file_pb_users_v1_users_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -871,7 +1182,7 @@ func (x *DeleteSoftOneUserRequest) String() string {
 func (*DeleteSoftOneUserRequest) ProtoMessage() {}
 
 func (x *DeleteSoftOneUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[15]
+	mi := &file_pb_users_v1_users_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -884,7 +1195,7 @@ func (x *DeleteSoftOneUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSoftOneUserRequest.ProtoReflect.Descriptor instead.
 func (*DeleteSoftOneUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{15}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *DeleteSoftOneUserRequest) GetId() string {
@@ -903,7 +1214,7 @@ type DeleteSoftOneUserResponse struct {
 
 func (x *DeleteSoftOneUserResponse) Reset() {
 	*x = DeleteSoftOneUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[16]
+	mi := &file_pb_users_v1_users_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -915,7 +1226,7 @@ func (x *DeleteSoftOneUserResponse) String() string {
 func (*DeleteSoftOneUserResponse) ProtoMessage() {}
 
 func (x *DeleteSoftOneUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[16]
+	mi := &file_pb_users_v1_users_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -928,7 +1239,7 @@ func (x *DeleteSoftOneUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSoftOneUserResponse.ProtoReflect.Descriptor instead.
 func (*DeleteSoftOneUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{16}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *DeleteSoftOneUserResponse) GetId() string {
@@ -948,7 +1259,7 @@ type DeleteHardOneUserRequest struct {
 
 func (x *DeleteHardOneUserRequest) Reset() {
 	*x = DeleteHardOneUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[17]
+	mi := &file_pb_users_v1_users_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -960,7 +1271,7 @@ func (x *DeleteHardOneUserRequest) String() string {
 func (*DeleteHardOneUserRequest) ProtoMessage() {}
 
 func (x *DeleteHardOneUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[17]
+	mi := &file_pb_users_v1_users_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -973,7 +1284,7 @@ func (x *DeleteHardOneUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteHardOneUserRequest.ProtoReflect.Descriptor instead.
 func (*DeleteHardOneUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{17}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *DeleteHardOneUserRequest) GetId() string {
@@ -992,7 +1303,7 @@ type DeleteHardOneUserResponse struct {
 
 func (x *DeleteHardOneUserResponse) Reset() {
 	*x = DeleteHardOneUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[18]
+	mi := &file_pb_users_v1_users_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1004,7 +1315,7 @@ func (x *DeleteHardOneUserResponse) String() string {
 func (*DeleteHardOneUserResponse) ProtoMessage() {}
 
 func (x *DeleteHardOneUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[18]
+	mi := &file_pb_users_v1_users_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1017,7 +1328,7 @@ func (x *DeleteHardOneUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteHardOneUserResponse.ProtoReflect.Descriptor instead.
 func (*DeleteHardOneUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{18}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *DeleteHardOneUserResponse) GetId() string {
@@ -1029,16 +1340,18 @@ func (x *DeleteHardOneUserResponse) GetId() string {
 
 // Login
 type LoginUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Email    string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// Client audience token issued for, default to first configured audience
+	ClientId      string `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoginUserRequest) Reset() {
 	*x = LoginUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[19]
+	mi := &file_pb_users_v1_users_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1050,7 +1363,7 @@ func (x *LoginUserRequest) String() string {
 func (*LoginUserRequest) ProtoMessage() {}
 
 func (x *LoginUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[19]
+	mi := &file_pb_users_v1_users_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1063,7 +1376,7 @@ func (x *LoginUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginUserRequest.ProtoReflect.Descriptor instead.
 func (*LoginUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{19}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *LoginUserRequest) GetEmail() string {
@@ -1080,17 +1393,28 @@ func (x *LoginUserRequest) GetPassword() string {
 	return ""
 }
 
+func (x *LoginUserRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
 type LoginUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken  string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// When set tokens are not issued and mfa_token must be exchanged
+	// using VerifyMfaUser
+	MfaRequired   bool   `protobuf:"varint,3,opt,name=mfa_required,json=mfaRequired,proto3" json:"mfa_required,omitempty"`
+	MfaToken      string `protobuf:"bytes,4,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoginUserResponse) Reset() {
 	*x = LoginUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[20]
+	mi := &file_pb_users_v1_users_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1102,7 +1426,7 @@ func (x *LoginUserResponse) String() string {
 func (*LoginUserResponse) ProtoMessage() {}
 
 func (x *LoginUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[20]
+	mi := &file_pb_users_v1_users_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1115,7 +1439,7 @@ func (x *LoginUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginUserResponse.ProtoReflect.Descriptor instead.
 func (*LoginUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{20}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *LoginUserResponse) GetAccessToken() string {
@@ -1132,17 +1456,33 @@ func (x *LoginUserResponse) GetRefreshToken() string {
 	return ""
 }
 
+func (x *LoginUserResponse) GetMfaRequired() bool {
+	if x != nil {
+		return x.MfaRequired
+	}
+	return false
+}
+
+func (x *LoginUserResponse) GetMfaToken() string {
+	if x != nil {
+		return x.MfaToken
+	}
+	return ""
+}
+
 // VerifyToken
 type VerifyTokenUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// Optional audience token must be issued for
+	Audience      string `protobuf:"bytes,2,opt,name=audience,proto3" json:"audience,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *VerifyTokenUserRequest) Reset() {
 	*x = VerifyTokenUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[21]
+	mi := &file_pb_users_v1_users_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1154,7 +1494,7 @@ func (x *VerifyTokenUserRequest) String() string {
 func (*VerifyTokenUserRequest) ProtoMessage() {}
 
 func (x *VerifyTokenUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[21]
+	mi := &file_pb_users_v1_users_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1167,7 +1507,7 @@ func (x *VerifyTokenUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyTokenUserRequest.ProtoReflect.Descriptor instead.
 func (*VerifyTokenUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{21}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *VerifyTokenUserRequest) GetAccessToken() string {
@@ -1177,6 +1517,13 @@ func (x *VerifyTokenUserRequest) GetAccessToken() string {
 	return ""
 }
 
+func (x *VerifyTokenUserRequest) GetAudience() string {
+	if x != nil {
+		return x.Audience
+	}
+	return ""
+}
+
 type VerifyTokenUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Exp           *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=exp,proto3" json:"exp,omitempty"`
@@ -1185,13 +1532,16 @@ type VerifyTokenUserResponse struct {
 	Iss           string                 `protobuf:"bytes,4,opt,name=iss,proto3" json:"iss,omitempty"`
 	Sub           string                 `protobuf:"bytes,5,opt,name=sub,proto3" json:"sub,omitempty"`
 	Aud           []string               `protobuf:"bytes,6,rep,name=aud,proto3" json:"aud,omitempty"`
+	Role          string                 `protobuf:"bytes,7,opt,name=role,proto3" json:"role,omitempty"`
+	Jti           string                 `protobuf:"bytes,8,opt,name=jti,proto3" json:"jti,omitempty"`
+	Sid           string                 `protobuf:"bytes,9,opt,name=sid,proto3" json:"sid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *VerifyTokenUserResponse) Reset() {
 	*x = VerifyTokenUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[22]
+	mi := &file_pb_users_v1_users_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1203,7 +1553,7 @@ func (x *VerifyTokenUserResponse) String() string {
 func (*VerifyTokenUserResponse) ProtoMessage() {}
 
 func (x *VerifyTokenUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[22]
+	mi := &file_pb_users_v1_users_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1216,7 +1566,7 @@ func (x *VerifyTokenUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyTokenUserResponse.ProtoReflect.Descriptor instead.
 func (*VerifyTokenUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{22}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *VerifyTokenUserResponse) GetExp() *timestamppb.Timestamp {
@@ -1261,6 +1611,27 @@ func (x *VerifyTokenUserResponse) GetAud() []string {
 	return nil
 }
 
+func (x *VerifyTokenUserResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *VerifyTokenUserResponse) GetJti() string {
+	if x != nil {
+		return x.Jti
+	}
+	return ""
+}
+
+func (x *VerifyTokenUserResponse) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
 // Refresh token
 type RefreshTokenUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1271,7 +1642,7 @@ type RefreshTokenUserRequest struct {
 
 func (x *RefreshTokenUserRequest) Reset() {
 	*x = RefreshTokenUserRequest{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[23]
+	mi := &file_pb_users_v1_users_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1283,7 +1654,7 @@ func (x *RefreshTokenUserRequest) String() string {
 func (*RefreshTokenUserRequest) ProtoMessage() {}
 
 func (x *RefreshTokenUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[23]
+	mi := &file_pb_users_v1_users_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1296,7 +1667,7 @@ func (x *RefreshTokenUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RefreshTokenUserRequest.ProtoReflect.Descriptor instead.
 func (*RefreshTokenUserRequest) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{23}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *RefreshTokenUserRequest) GetRefreshToken() string {
@@ -1307,15 +1678,17 @@ func (x *RefreshTokenUserRequest) GetRefreshToken() string {
 }
 
 type RefreshTokenUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// Rotated refresh token, previous one must not be used anymore
+	RefreshToken  string `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RefreshTokenUserResponse) Reset() {
 	*x = RefreshTokenUserResponse{}
-	mi := &file_pb_users_v1_users_proto_msgTypes[24]
+	mi := &file_pb_users_v1_users_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1327,7 +1700,7 @@ func (x *RefreshTokenUserResponse) String() string {
 func (*RefreshTokenUserResponse) ProtoMessage() {}
 
 func (x *RefreshTokenUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_users_v1_users_proto_msgTypes[24]
+	mi := &file_pb_users_v1_users_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1340,7 +1713,7 @@ func (x *RefreshTokenUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RefreshTokenUserResponse.ProtoReflect.Descriptor instead.
 func (*RefreshTokenUserResponse) Descriptor() ([]byte, []int) {
-	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{24}
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *RefreshTokenUserResponse) GetAccessToken() string {
@@ -1350,105 +1723,1713 @@ func (x *RefreshTokenUserResponse) GetAccessToken() string {
 	return ""
 }
 
-var File_pb_users_v1_users_proto protoreflect.FileDescriptor
+func (x *RefreshTokenUserResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
 
-const file_pb_users_v1_users_proto_rawDesc = "" +
-	"\n" +
-	"\x17pb/users/v1/users.proto\x12\x0fpb.users.pbuser\x1a\x1fgoogle/protobuf/timestamp.proto\"w\n" +
-	"\x14CreateOneUserRequest\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpassword\x12-\n" +
-	"\x04role\x18\x04 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\"'\n" +
-	"\x15CreateOneUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"b\n" +
-	"\vUserSummary\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12-\n" +
-	"\x04role\x18\x03 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\"B\n" +
-	"\x12GetManyUserRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x04R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x04R\x06offset\"I\n" +
-	"\x13GetManyUserResponse\x122\n" +
-	"\x05users\x18\x01 \x03(\v2\x1c.pb.users.pbuser.UserSummaryR\x05users\"#\n" +
-	"\x11GetOneUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x8c\x01\n" +
-	"\x12GetOneUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
-	"\x04role\x18\x03 \x01(\tR\x04role\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\x05 \x01(\tR\tupdatedAt\":\n" +
-	"\"GetOneCredentialUserByEmailRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\"Z\n" +
-	"#GetOneCredentialUserByEmailResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
-	"\rpassword_hash\x18\x02 \x01(\tR\fpasswordHash\"J\n" +
-	"\x1cUpdateOnePasswordUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"/\n" +
-	"\x1dUpdateOnePasswordUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
-	"\x19UpdateOneEmailUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\",\n" +
-	"\x1aUpdateOneEmailUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"Y\n" +
-	"\x18UpdateOneRoleUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
-	"\x04role\x18\x02 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\"+\n" +
-	"\x19UpdateOneRoleUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
-	"\x18DeleteSoftOneUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
-	"\x19DeleteSoftOneUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
-	"\x18DeleteHardOneUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
-	"\x19DeleteHardOneUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
-	"\x10LoginUserRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"[\n" +
-	"\x11LoginUserResponse\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
-	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\";\n" +
-	"\x16VerifyTokenUserRequest\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\xd9\x01\n" +
-	"\x17VerifyTokenUserResponse\x12,\n" +
-	"\x03exp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x03exp\x12,\n" +
-	"\x03iat\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03iat\x12,\n" +
-	"\x03nbf\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03nbf\x12\x10\n" +
-	"\x03iss\x18\x04 \x01(\tR\x03iss\x12\x10\n" +
-	"\x03sub\x18\x05 \x01(\tR\x03sub\x12\x10\n" +
-	"\x03aud\x18\x06 \x03(\tR\x03aud\">\n" +
-	"\x17RefreshTokenUserRequest\x12#\n" +
-	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"=\n" +
-	"\x18RefreshTokenUserResponse\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken*L\n" +
-	"\bUserRole\x12\x0f\n" +
-	"\vUnspecified\x10\x00\x12\v\n" +
-	"\aTeacher\x10\x01\x12\t\n" +
-	"\x05Staff\x10\x02\x12\v\n" +
-	"\aStudent\x10\x03\x12\n" +
-	"\n" +
-	"\x06Parent\x10\x042\x8f\n" +
-	"\n" +
-	"\vUserService\x12`\n" +
-	"\rCreateOneUser\x12%.pb.users.pbuser.CreateOneUserRequest\x1a&.pb.users.pbuser.CreateOneUserResponse\"\x00\x12W\n" +
-	"\n" +
-	"GetOneUser\x12\".pb.users.pbuser.GetOneUserRequest\x1a#.pb.users.pbuser.GetOneUserResponse\"\x00\x12\x8a\x01\n" +
-	"\x1bGetOneCredentialUserByEmail\x123.pb.users.pbuser.GetOneCredentialUserByEmailRequest\x1a4.pb.users.pbuser.GetOneCredentialUserByEmailResponse\"\x00\x12Z\n" +
-	"\vGetManyUser\x12#.pb.users.pbuser.GetManyUserRequest\x1a$.pb.users.pbuser.GetManyUserResponse\"\x00\x12x\n" +
-	"\x15UpdateOnePasswordUser\x12-.pb.users.pbuser.UpdateOnePasswordUserRequest\x1a..pb.users.pbuser.UpdateOnePasswordUserResponse\"\x00\x12o\n" +
-	"\x12UpdateOneEmailUser\x12*.pb.users.pbuser.UpdateOneEmailUserRequest\x1a+.pb.users.pbuser.UpdateOneEmailUserResponse\"\x00\x12l\n" +
-	"\x11UpdateOneRoleUser\x12).pb.users.pbuser.UpdateOneRoleUserRequest\x1a*.pb.users.pbuser.UpdateOneRoleUserResponse\"\x00\x12l\n" +
-	"\x11DeleteSoftOneUser\x12).pb.users.pbuser.DeleteSoftOneUserRequest\x1a*.pb.users.pbuser.DeleteSoftOneUserResponse\"\x00\x12l\n" +
-	"\x11DeleteHardOneUser\x12).pb.users.pbuser.DeleteHardOneUserRequest\x1a*.pb.users.pbuser.DeleteHardOneUserResponse\"\x00\x12T\n" +
-	"\tLoginUser\x12!.pb.users.pbuser.LoginUserRequest\x1a\".pb.users.pbuser.LoginUserResponse\"\x00\x12f\n" +
-	"\x0fVerifyTokenUser\x12'.pb.users.pbuser.VerifyTokenUserRequest\x1a(.pb.users.pbuser.VerifyTokenUserResponse\"\x00\x12i\n" +
-	"\x10RefreshTokenUser\x12(.pb.users.pbuser.RefreshTokenUserRequest\x1a).pb.users.pbuser.RefreshTokenUserResponse\"\x00BQZOgithub.com/nurfianqodar/school-microservices/services/users/pb/users/v1;pbusersb\x06proto3"
+// JSON Web Key Set
+type Jwk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kty           string                 `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"`
+	Kid           string                 `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
+	Alg           string                 `protobuf:"bytes,3,opt,name=alg,proto3" json:"alg,omitempty"`
+	Use           string                 `protobuf:"bytes,4,opt,name=use,proto3" json:"use,omitempty"`
+	N             string                 `protobuf:"bytes,5,opt,name=n,proto3" json:"n,omitempty"`
+	E             string                 `protobuf:"bytes,6,opt,name=e,proto3" json:"e,omitempty"`
+	Crv           string                 `protobuf:"bytes,7,opt,name=crv,proto3" json:"crv,omitempty"`
+	X             string                 `protobuf:"bytes,8,opt,name=x,proto3" json:"x,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Jwk) Reset() {
+	*x = Jwk{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Jwk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Jwk) ProtoMessage() {}
+
+func (x *Jwk) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Jwk.ProtoReflect.Descriptor instead.
+func (*Jwk) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Jwk) GetKty() string {
+	if x != nil {
+		return x.Kty
+	}
+	return ""
+}
+
+func (x *Jwk) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *Jwk) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+func (x *Jwk) GetUse() string {
+	if x != nil {
+		return x.Use
+	}
+	return ""
+}
+
+func (x *Jwk) GetN() string {
+	if x != nil {
+		return x.N
+	}
+	return ""
+}
+
+func (x *Jwk) GetE() string {
+	if x != nil {
+		return x.E
+	}
+	return ""
+}
+
+func (x *Jwk) GetCrv() string {
+	if x != nil {
+		return x.Crv
+	}
+	return ""
+}
+
+func (x *Jwk) GetX() string {
+	if x != nil {
+		return x.X
+	}
+	return ""
+}
+
+type GetJwksUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJwksUserRequest) Reset() {
+	*x = GetJwksUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJwksUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJwksUserRequest) ProtoMessage() {}
+
+func (x *GetJwksUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJwksUserRequest.ProtoReflect.Descriptor instead.
+func (*GetJwksUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{29}
+}
+
+type GetJwksUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*Jwk                 `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJwksUserResponse) Reset() {
+	*x = GetJwksUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJwksUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJwksUserResponse) ProtoMessage() {}
+
+func (x *GetJwksUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJwksUserResponse.ProtoReflect.Descriptor instead.
+func (*GetJwksUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetJwksUserResponse) GetKeys() []*Jwk {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// Logout
+type LogoutUserRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Revoke every session of user
+	All bool `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	// Optional access token revoked along with session
+	AccessToken   string `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutUserRequest) Reset() {
+	*x = LogoutUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutUserRequest) ProtoMessage() {}
+
+func (x *LogoutUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutUserRequest.ProtoReflect.Descriptor instead.
+func (*LogoutUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LogoutUserRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LogoutUserRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *LogoutUserRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+type LogoutUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutUserResponse) Reset() {
+	*x = LogoutUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutUserResponse) ProtoMessage() {}
+
+func (x *LogoutUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutUserResponse.ProtoReflect.Descriptor instead.
+func (*LogoutUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LogoutUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// List sessions
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Session) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type ListSessionsUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsUserRequest) Reset() {
+	*x = ListSessionsUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsUserRequest) ProtoMessage() {}
+
+func (x *ListSessionsUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsUserRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListSessionsUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListSessionsUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsUserResponse) Reset() {
+	*x = ListSessionsUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsUserResponse) ProtoMessage() {}
+
+func (x *ListSessionsUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsUserResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListSessionsUserResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// Revoke session
+type RevokeSessionUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionUserRequest) Reset() {
+	*x = RevokeSessionUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionUserRequest) ProtoMessage() {}
+
+func (x *RevokeSessionUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionUserRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RevokeSessionUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevokeSessionUserRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeSessionUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionUserResponse) Reset() {
+	*x = RevokeSessionUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionUserResponse) ProtoMessage() {}
+
+func (x *RevokeSessionUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionUserResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RevokeSessionUserResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// Request password reset
+type RequestPasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetRequest) ProtoMessage() {}
+
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RequestPasswordResetRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type RequestPasswordResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetResponse) Reset() {
+	*x = RequestPasswordResetResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetResponse) ProtoMessage() {}
+
+func (x *RequestPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{39}
+}
+
+// Confirm password reset
+type ConfirmPasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetRequest) Reset() {
+	*x = ConfirmPasswordResetRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetRequest) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ConfirmPasswordResetRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ConfirmPasswordResetRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type ConfirmPasswordResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetResponse) Reset() {
+	*x = ConfirmPasswordResetResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetResponse) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ConfirmPasswordResetResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Verify email
+type VerifyEmailUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailUserRequest) Reset() {
+	*x = VerifyEmailUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailUserRequest) ProtoMessage() {}
+
+func (x *VerifyEmailUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailUserRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *VerifyEmailUserRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyEmailUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailUserResponse) Reset() {
+	*x = VerifyEmailUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailUserResponse) ProtoMessage() {}
+
+func (x *VerifyEmailUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailUserResponse.ProtoReflect.Descriptor instead.
+func (*VerifyEmailUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *VerifyEmailUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Unlock user locked by failed login attempts
+type UnlockUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserRequest) Reset() {
+	*x = UnlockUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserRequest) ProtoMessage() {}
+
+func (x *UnlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserRequest.ProtoReflect.Descriptor instead.
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *UnlockUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UnlockUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserResponse) Reset() {
+	*x = UnlockUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserResponse) ProtoMessage() {}
+
+func (x *UnlockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserResponse.ProtoReflect.Descriptor instead.
+func (*UnlockUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UnlockUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Exchange MFA challenge token with TOTP or recovery code
+type VerifyMfaUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MfaToken      string                 `protobuf:"bytes,1,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMfaUserRequest) Reset() {
+	*x = VerifyMfaUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMfaUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMfaUserRequest) ProtoMessage() {}
+
+func (x *VerifyMfaUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMfaUserRequest.ProtoReflect.Descriptor instead.
+func (*VerifyMfaUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *VerifyMfaUserRequest) GetMfaToken() string {
+	if x != nil {
+		return x.MfaToken
+	}
+	return ""
+}
+
+func (x *VerifyMfaUserRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifyMfaUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMfaUserResponse) Reset() {
+	*x = VerifyMfaUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMfaUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMfaUserResponse) ProtoMessage() {}
+
+func (x *VerifyMfaUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMfaUserResponse.ProtoReflect.Descriptor instead.
+func (*VerifyMfaUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *VerifyMfaUserResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *VerifyMfaUserResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+// Enroll TOTP
+type EnrollTotpUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTotpUserRequest) Reset() {
+	*x = EnrollTotpUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTotpUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTotpUserRequest) ProtoMessage() {}
+
+func (x *EnrollTotpUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTotpUserRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTotpUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *EnrollTotpUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type EnrollTotpUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secret        string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	Uri           string                 `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTotpUserResponse) Reset() {
+	*x = EnrollTotpUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTotpUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTotpUserResponse) ProtoMessage() {}
+
+func (x *EnrollTotpUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTotpUserResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTotpUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *EnrollTotpUserResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrollTotpUserResponse) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+// Confirm TOTP enrollment
+type ConfirmTotpUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTotpUserRequest) Reset() {
+	*x = ConfirmTotpUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTotpUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTotpUserRequest) ProtoMessage() {}
+
+func (x *ConfirmTotpUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTotpUserRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTotpUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ConfirmTotpUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ConfirmTotpUserRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTotpUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecoveryCodes []string               `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTotpUserResponse) Reset() {
+	*x = ConfirmTotpUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTotpUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTotpUserResponse) ProtoMessage() {}
+
+func (x *ConfirmTotpUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTotpUserResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTotpUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ConfirmTotpUserResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+// Send TOTP enrollment token to user out of band. The token authenticate
+// EnrollTotpUser and ConfirmTotpUser for user which can not login yet
+// because its role require MFA.
+type IssueMfaEnrollmentUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueMfaEnrollmentUserRequest) Reset() {
+	*x = IssueMfaEnrollmentUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueMfaEnrollmentUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueMfaEnrollmentUserRequest) ProtoMessage() {}
+
+func (x *IssueMfaEnrollmentUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueMfaEnrollmentUserRequest.ProtoReflect.Descriptor instead.
+func (*IssueMfaEnrollmentUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *IssueMfaEnrollmentUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type IssueMfaEnrollmentUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueMfaEnrollmentUserResponse) Reset() {
+	*x = IssueMfaEnrollmentUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueMfaEnrollmentUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueMfaEnrollmentUserResponse) ProtoMessage() {}
+
+func (x *IssueMfaEnrollmentUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueMfaEnrollmentUserResponse.ProtoReflect.Descriptor instead.
+func (*IssueMfaEnrollmentUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *IssueMfaEnrollmentUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Disable TOTP
+type DisableTotpUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Current TOTP or recovery code, required when user disable their
+	// own two-factor authentication
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTotpUserRequest) Reset() {
+	*x = DisableTotpUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTotpUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTotpUserRequest) ProtoMessage() {}
+
+func (x *DisableTotpUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTotpUserRequest.ProtoReflect.Descriptor instead.
+func (*DisableTotpUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DisableTotpUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DisableTotpUserRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DisableTotpUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTotpUserResponse) Reset() {
+	*x = DisableTotpUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTotpUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTotpUserResponse) ProtoMessage() {}
+
+func (x *DisableTotpUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTotpUserResponse.ProtoReflect.Descriptor instead.
+func (*DisableTotpUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *DisableTotpUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Require two-factor authentication for role
+type UpdateMfaRoleUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          UserRole               `protobuf:"varint,1,opt,name=role,proto3,enum=pb.users.pbuser.UserRole" json:"role,omitempty"`
+	Required      bool                   `protobuf:"varint,2,opt,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMfaRoleUserRequest) Reset() {
+	*x = UpdateMfaRoleUserRequest{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMfaRoleUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMfaRoleUserRequest) ProtoMessage() {}
+
+func (x *UpdateMfaRoleUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMfaRoleUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMfaRoleUserRequest) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *UpdateMfaRoleUserRequest) GetRole() UserRole {
+	if x != nil {
+		return x.Role
+	}
+	return UserRole_Unspecified
+}
+
+func (x *UpdateMfaRoleUserRequest) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type UpdateMfaRoleUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMfaRoleUserResponse) Reset() {
+	*x = UpdateMfaRoleUserResponse{}
+	mi := &file_pb_users_v1_users_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMfaRoleUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMfaRoleUserResponse) ProtoMessage() {}
+
+func (x *UpdateMfaRoleUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_users_v1_users_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMfaRoleUserResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMfaRoleUserResponse) Descriptor() ([]byte, []int) {
+	return file_pb_users_v1_users_proto_rawDescGZIP(), []int{57}
+}
+
+var File_pb_users_v1_users_proto protoreflect.FileDescriptor
+
+const file_pb_users_v1_users_proto_rawDesc = "" +
+	"\n" +
+	"\x17pb/users/v1/users.proto\x12\x0fpb.users.pbuser\x1a\x1fgoogle/protobuf/timestamp.proto\"w\n" +
+	"\x14CreateOneUserRequest\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12-\n" +
+	"\x04role\x18\x04 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\"'\n" +
+	"\x15CreateOneUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x9d\x01\n" +
+	"\vUserSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12-\n" +
+	"\x04role\x18\x03 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xf0\x02\n" +
+	"\x12GetManyUserRequest\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12-\n" +
+	"\x04sort\x18\x05 \x01(\x0e2\x19.pb.users.pbuser.UserSortR\x04sort\x12-\n" +
+	"\x04role\x18\x06 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\x12!\n" +
+	"\femail_prefix\x18\a \x01(\tR\vemailPrefix\x12?\n" +
+	"\rcreated_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBeforeJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03R\x05limitR\x06offset\"\x92\x01\n" +
+	"\x13GetManyUserResponse\x122\n" +
+	"\x05users\x18\x01 \x03(\v2\x1c.pb.users.pbuser.UserSummaryR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x04R\n" +
+	"totalCount\"x\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12/\n" +
+	"\x05roles\x18\x02 \x03(\x0e2\x19.pb.users.pbuser.UserRoleR\x05roles\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\"v\n" +
+	"\x10UserSearchResult\x120\n" +
+	"\x04user\x18\x01 \x01(\v2\x1c.pb.users.pbuser.UserSummaryR\x04user\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x02R\x04rank\x12\x1c\n" +
+	"\thighlight\x18\x03 \x01(\tR\thighlight\"R\n" +
+	"\x13SearchUsersResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.pb.users.pbuser.UserSearchResultR\aresults\"#\n" +
+	"\x11GetOneUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xb8\x01\n" +
+	"\x12GetOneUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x12*\n" +
+	"\x11email_verified_at\x18\x06 \x01(\tR\x0femailVerifiedAt\":\n" +
+	"\"GetOneCredentialUserByEmailRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"Z\n" +
+	"#GetOneCredentialUserByEmailResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rpassword_hash\x18\x02 \x01(\tR\fpasswordHash\"u\n" +
+	"\x1cUpdateOnePasswordUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12)\n" +
+	"\x10current_password\x18\x03 \x01(\tR\x0fcurrentPassword\"/\n" +
+	"\x1dUpdateOnePasswordUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
+	"\x19UpdateOneEmailUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\",\n" +
+	"\x1aUpdateOneEmailUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Y\n" +
+	"\x18UpdateOneRoleUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\"+\n" +
+	"\x19UpdateOneRoleUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x18DeleteSoftOneUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
+	"\x19DeleteSoftOneUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x18DeleteHardOneUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
+	"\x19DeleteHardOneUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"a\n" +
+	"\x10LoginUserRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\"\xbe\x01\n" +
+	"\x11LoginUserResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12!\n" +
+	"\fmfa_required\x18\x03 \x01(\bR\vmfaRequired\x12\x1b\n" +
+	"\tmfa_token\x18\x04 \x01(\tR\bmfaTokenJ\x04\b\x05\x10\x06J\x04\b\x06\x10\aR\vtotp_secretR\btotp_uri\"W\n" +
+	"\x16VerifyTokenUserRequest\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12\x1a\n" +
+	"\baudience\x18\x02 \x01(\tR\baudience\"\x91\x02\n" +
+	"\x17VerifyTokenUserResponse\x12,\n" +
+	"\x03exp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x03exp\x12,\n" +
+	"\x03iat\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03iat\x12,\n" +
+	"\x03nbf\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03nbf\x12\x10\n" +
+	"\x03iss\x18\x04 \x01(\tR\x03iss\x12\x10\n" +
+	"\x03sub\x18\x05 \x01(\tR\x03sub\x12\x10\n" +
+	"\x03aud\x18\x06 \x03(\tR\x03aud\x12\x12\n" +
+	"\x04role\x18\a \x01(\tR\x04role\x12\x10\n" +
+	"\x03jti\x18\b \x01(\tR\x03jti\x12\x10\n" +
+	"\x03sid\x18\t \x01(\tR\x03sid\">\n" +
+	"\x17RefreshTokenUserRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"b\n" +
+	"\x18RefreshTokenUserResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\"\x89\x01\n" +
+	"\x03Jwk\x12\x10\n" +
+	"\x03kty\x18\x01 \x01(\tR\x03kty\x12\x10\n" +
+	"\x03kid\x18\x02 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03alg\x18\x03 \x01(\tR\x03alg\x12\x10\n" +
+	"\x03use\x18\x04 \x01(\tR\x03use\x12\f\n" +
+	"\x01n\x18\x05 \x01(\tR\x01n\x12\f\n" +
+	"\x01e\x18\x06 \x01(\tR\x01e\x12\x10\n" +
+	"\x03crv\x18\a \x01(\tR\x03crv\x12\f\n" +
+	"\x01x\x18\b \x01(\tR\x01x\"\x14\n" +
+	"\x12GetJwksUserRequest\"?\n" +
+	"\x13GetJwksUserResponse\x12(\n" +
+	"\x04keys\x18\x01 \x03(\v2\x14.pb.users.pbuser.JwkR\x04keys\"m\n" +
+	"\x11LogoutUserRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\x12!\n" +
+	"\faccess_token\x18\x03 \x01(\tR\vaccessToken\"$\n" +
+	"\x12LogoutUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8f\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\")\n" +
+	"\x17ListSessionsUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"P\n" +
+	"\x18ListSessionsUserResponse\x124\n" +
+	"\bsessions\x18\x01 \x03(\v2\x18.pb.users.pbuser.SessionR\bsessions\"I\n" +
+	"\x18RevokeSessionUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\":\n" +
+	"\x19RevokeSessionUserResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"3\n" +
+	"\x1bRequestPasswordResetRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"\x1e\n" +
+	"\x1cRequestPasswordResetResponse\"O\n" +
+	"\x1bConfirmPasswordResetRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\".\n" +
+	"\x1cConfirmPasswordResetResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
+	"\x16VerifyEmailUserRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\")\n" +
+	"\x17VerifyEmailUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"#\n" +
+	"\x11UnlockUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x12UnlockUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"G\n" +
+	"\x14VerifyMfaUserRequest\x12\x1b\n" +
+	"\tmfa_token\x18\x01 \x01(\tR\bmfaToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"u\n" +
+	"\x15VerifyMfaUserResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshTokenJ\x04\b\x03\x10\x04R\x0erecovery_codes\"'\n" +
+	"\x15EnrollTotpUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"B\n" +
+	"\x16EnrollTotpUserResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x10\n" +
+	"\x03uri\x18\x02 \x01(\tR\x03uri\"<\n" +
+	"\x16ConfirmTotpUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"@\n" +
+	"\x17ConfirmTotpUserResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"/\n" +
+	"\x1dIssueMfaEnrollmentUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"0\n" +
+	"\x1eIssueMfaEnrollmentUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"<\n" +
+	"\x16DisableTotpUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\")\n" +
+	"\x17DisableTotpUserResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"e\n" +
+	"\x18UpdateMfaRoleUserRequest\x12-\n" +
+	"\x04role\x18\x01 \x01(\x0e2\x19.pb.users.pbuser.UserRoleR\x04role\x12\x1a\n" +
+	"\brequired\x18\x02 \x01(\bR\brequired\"\x1b\n" +
+	"\x19UpdateMfaRoleUserResponse*L\n" +
+	"\bUserRole\x12\x0f\n" +
+	"\vUnspecified\x10\x00\x12\v\n" +
+	"\aTeacher\x10\x01\x12\t\n" +
+	"\x05Staff\x10\x02\x12\v\n" +
+	"\aStudent\x10\x03\x12\n" +
+	"\n" +
+	"\x06Parent\x10\x04*H\n" +
+	"\bUserSort\x12\x0e\n" +
+	"\n" +
+	"CreatedAsc\x10\x00\x12\x0f\n" +
+	"\vCreatedDesc\x10\x01\x12\f\n" +
+	"\bEmailAsc\x10\x02\x12\r\n" +
+	"\tEmailDesc\x10\x032\xaa\x16\n" +
+	"\vUserService\x12`\n" +
+	"\rCreateOneUser\x12%.pb.users.pbuser.CreateOneUserRequest\x1a&.pb.users.pbuser.CreateOneUserResponse\"\x00\x12W\n" +
+	"\n" +
+	"GetOneUser\x12\".pb.users.pbuser.GetOneUserRequest\x1a#.pb.users.pbuser.GetOneUserResponse\"\x00\x12\x8a\x01\n" +
+	"\x1bGetOneCredentialUserByEmail\x123.pb.users.pbuser.GetOneCredentialUserByEmailRequest\x1a4.pb.users.pbuser.GetOneCredentialUserByEmailResponse\"\x00\x12Z\n" +
+	"\vGetManyUser\x12#.pb.users.pbuser.GetManyUserRequest\x1a$.pb.users.pbuser.GetManyUserResponse\"\x00\x12Z\n" +
+	"\vSearchUsers\x12#.pb.users.pbuser.SearchUsersRequest\x1a$.pb.users.pbuser.SearchUsersResponse\"\x00\x12x\n" +
+	"\x15UpdateOnePasswordUser\x12-.pb.users.pbuser.UpdateOnePasswordUserRequest\x1a..pb.users.pbuser.UpdateOnePasswordUserResponse\"\x00\x12o\n" +
+	"\x12UpdateOneEmailUser\x12*.pb.users.pbuser.UpdateOneEmailUserRequest\x1a+.pb.users.pbuser.UpdateOneEmailUserResponse\"\x00\x12l\n" +
+	"\x11UpdateOneRoleUser\x12).pb.users.pbuser.UpdateOneRoleUserRequest\x1a*.pb.users.pbuser.UpdateOneRoleUserResponse\"\x00\x12l\n" +
+	"\x11DeleteSoftOneUser\x12).pb.users.pbuser.DeleteSoftOneUserRequest\x1a*.pb.users.pbuser.DeleteSoftOneUserResponse\"\x00\x12l\n" +
+	"\x11DeleteHardOneUser\x12).pb.users.pbuser.DeleteHardOneUserRequest\x1a*.pb.users.pbuser.DeleteHardOneUserResponse\"\x00\x12T\n" +
+	"\tLoginUser\x12!.pb.users.pbuser.LoginUserRequest\x1a\".pb.users.pbuser.LoginUserResponse\"\x00\x12f\n" +
+	"\x0fVerifyTokenUser\x12'.pb.users.pbuser.VerifyTokenUserRequest\x1a(.pb.users.pbuser.VerifyTokenUserResponse\"\x00\x12i\n" +
+	"\x10RefreshTokenUser\x12(.pb.users.pbuser.RefreshTokenUserRequest\x1a).pb.users.pbuser.RefreshTokenUserResponse\"\x00\x12Z\n" +
+	"\vGetJwksUser\x12#.pb.users.pbuser.GetJwksUserRequest\x1a$.pb.users.pbuser.GetJwksUserResponse\"\x00\x12W\n" +
+	"\n" +
+	"UnlockUser\x12\".pb.users.pbuser.UnlockUserRequest\x1a#.pb.users.pbuser.UnlockUserResponse\"\x00\x12W\n" +
+	"\n" +
+	"LogoutUser\x12\".pb.users.pbuser.LogoutUserRequest\x1a#.pb.users.pbuser.LogoutUserResponse\"\x00\x12i\n" +
+	"\x10ListSessionsUser\x12(.pb.users.pbuser.ListSessionsUserRequest\x1a).pb.users.pbuser.ListSessionsUserResponse\"\x00\x12l\n" +
+	"\x11RevokeSessionUser\x12).pb.users.pbuser.RevokeSessionUserRequest\x1a*.pb.users.pbuser.RevokeSessionUserResponse\"\x00\x12u\n" +
+	"\x14RequestPasswordReset\x12,.pb.users.pbuser.RequestPasswordResetRequest\x1a-.pb.users.pbuser.RequestPasswordResetResponse\"\x00\x12u\n" +
+	"\x14ConfirmPasswordReset\x12,.pb.users.pbuser.ConfirmPasswordResetRequest\x1a-.pb.users.pbuser.ConfirmPasswordResetResponse\"\x00\x12f\n" +
+	"\x0fVerifyEmailUser\x12'.pb.users.pbuser.VerifyEmailUserRequest\x1a(.pb.users.pbuser.VerifyEmailUserResponse\"\x00\x12`\n" +
+	"\rVerifyMfaUser\x12%.pb.users.pbuser.VerifyMfaUserRequest\x1a&.pb.users.pbuser.VerifyMfaUserResponse\"\x00\x12c\n" +
+	"\x0eEnrollTotpUser\x12&.pb.users.pbuser.EnrollTotpUserRequest\x1a'.pb.users.pbuser.EnrollTotpUserResponse\"\x00\x12f\n" +
+	"\x0fConfirmTotpUser\x12'.pb.users.pbuser.ConfirmTotpUserRequest\x1a(.pb.users.pbuser.ConfirmTotpUserResponse\"\x00\x12{\n" +
+	"\x16IssueMfaEnrollmentUser\x12..pb.users.pbuser.IssueMfaEnrollmentUserRequest\x1a/.pb.users.pbuser.IssueMfaEnrollmentUserResponse\"\x00\x12f\n" +
+	"\x0fDisableTotpUser\x12'.pb.users.pbuser.DisableTotpUserRequest\x1a(.pb.users.pbuser.DisableTotpUserResponse\"\x00\x12l\n" +
+	"\x11UpdateMfaRoleUser\x12).pb.users.pbuser.UpdateMfaRoleUserRequest\x1a*.pb.users.pbuser.UpdateMfaRoleUserResponse\"\x00BQZOgithub.com/nurfianqodar/school-microservices/services/users/pb/users/v1;pbusersb\x06proto3"
 
 var (
 	file_pb_users_v1_users_proto_rawDescOnce sync.Once
@@ -1462,74 +3443,151 @@ func file_pb_users_v1_users_proto_rawDescGZIP() []byte {
 	return file_pb_users_v1_users_proto_rawDescData
 }
 
-var file_pb_users_v1_users_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pb_users_v1_users_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_pb_users_v1_users_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pb_users_v1_users_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
 var file_pb_users_v1_users_proto_goTypes = []any{
 	(UserRole)(0),                               // 0: pb.users.pbuser.UserRole
-	(*CreateOneUserRequest)(nil),                // 1: pb.users.pbuser.CreateOneUserRequest
-	(*CreateOneUserResponse)(nil),               // 2: pb.users.pbuser.CreateOneUserResponse
-	(*UserSummary)(nil),                         // 3: pb.users.pbuser.UserSummary
-	(*GetManyUserRequest)(nil),                  // 4: pb.users.pbuser.GetManyUserRequest
-	(*GetManyUserResponse)(nil),                 // 5: pb.users.pbuser.GetManyUserResponse
-	(*GetOneUserRequest)(nil),                   // 6: pb.users.pbuser.GetOneUserRequest
-	(*GetOneUserResponse)(nil),                  // 7: pb.users.pbuser.GetOneUserResponse
-	(*GetOneCredentialUserByEmailRequest)(nil),  // 8: pb.users.pbuser.GetOneCredentialUserByEmailRequest
-	(*GetOneCredentialUserByEmailResponse)(nil), // 9: pb.users.pbuser.GetOneCredentialUserByEmailResponse
-	(*UpdateOnePasswordUserRequest)(nil),        // 10: pb.users.pbuser.UpdateOnePasswordUserRequest
-	(*UpdateOnePasswordUserResponse)(nil),       // 11: pb.users.pbuser.UpdateOnePasswordUserResponse
-	(*UpdateOneEmailUserRequest)(nil),           // 12: pb.users.pbuser.UpdateOneEmailUserRequest
-	(*UpdateOneEmailUserResponse)(nil),          // 13: pb.users.pbuser.UpdateOneEmailUserResponse
-	(*UpdateOneRoleUserRequest)(nil),            // 14: pb.users.pbuser.UpdateOneRoleUserRequest
-	(*UpdateOneRoleUserResponse)(nil),           // 15: pb.users.pbuser.UpdateOneRoleUserResponse
-	(*DeleteSoftOneUserRequest)(nil),            // 16: pb.users.pbuser.DeleteSoftOneUserRequest
-	(*DeleteSoftOneUserResponse)(nil),           // 17: pb.users.pbuser.DeleteSoftOneUserResponse
-	(*DeleteHardOneUserRequest)(nil),            // 18: pb.users.pbuser.DeleteHardOneUserRequest
-	(*DeleteHardOneUserResponse)(nil),           // 19: pb.users.pbuser.DeleteHardOneUserResponse
-	(*LoginUserRequest)(nil),                    // 20: pb.users.pbuser.LoginUserRequest
-	(*LoginUserResponse)(nil),                   // 21: pb.users.pbuser.LoginUserResponse
-	(*VerifyTokenUserRequest)(nil),              // 22: pb.users.pbuser.VerifyTokenUserRequest
-	(*VerifyTokenUserResponse)(nil),             // 23: pb.users.pbuser.VerifyTokenUserResponse
-	(*RefreshTokenUserRequest)(nil),             // 24: pb.users.pbuser.RefreshTokenUserRequest
-	(*RefreshTokenUserResponse)(nil),            // 25: pb.users.pbuser.RefreshTokenUserResponse
-	(*timestamppb.Timestamp)(nil),               // 26: google.protobuf.Timestamp
+	(UserSort)(0),                               // 1: pb.users.pbuser.UserSort
+	(*CreateOneUserRequest)(nil),                // 2: pb.users.pbuser.CreateOneUserRequest
+	(*CreateOneUserResponse)(nil),               // 3: pb.users.pbuser.CreateOneUserResponse
+	(*UserSummary)(nil),                         // 4: pb.users.pbuser.UserSummary
+	(*GetManyUserRequest)(nil),                  // 5: pb.users.pbuser.GetManyUserRequest
+	(*GetManyUserResponse)(nil),                 // 6: pb.users.pbuser.GetManyUserResponse
+	(*SearchUsersRequest)(nil),                  // 7: pb.users.pbuser.SearchUsersRequest
+	(*UserSearchResult)(nil),                    // 8: pb.users.pbuser.UserSearchResult
+	(*SearchUsersResponse)(nil),                 // 9: pb.users.pbuser.SearchUsersResponse
+	(*GetOneUserRequest)(nil),                   // 10: pb.users.pbuser.GetOneUserRequest
+	(*GetOneUserResponse)(nil),                  // 11: pb.users.pbuser.GetOneUserResponse
+	(*GetOneCredentialUserByEmailRequest)(nil),  // 12: pb.users.pbuser.GetOneCredentialUserByEmailRequest
+	(*GetOneCredentialUserByEmailResponse)(nil), // 13: pb.users.pbuser.GetOneCredentialUserByEmailResponse
+	(*UpdateOnePasswordUserRequest)(nil),        // 14: pb.users.pbuser.UpdateOnePasswordUserRequest
+	(*UpdateOnePasswordUserResponse)(nil),       // 15: pb.users.pbuser.UpdateOnePasswordUserResponse
+	(*UpdateOneEmailUserRequest)(nil),           // 16: pb.users.pbuser.UpdateOneEmailUserRequest
+	(*UpdateOneEmailUserResponse)(nil),          // 17: pb.users.pbuser.UpdateOneEmailUserResponse
+	(*UpdateOneRoleUserRequest)(nil),            // 18: pb.users.pbuser.UpdateOneRoleUserRequest
+	(*UpdateOneRoleUserResponse)(nil),           // 19: pb.users.pbuser.UpdateOneRoleUserResponse
+	(*DeleteSoftOneUserRequest)(nil),            // 20: pb.users.pbuser.DeleteSoftOneUserRequest
+	(*DeleteSoftOneUserResponse)(nil),           // 21: pb.users.pbuser.DeleteSoftOneUserResponse
+	(*DeleteHardOneUserRequest)(nil),            // 22: pb.users.pbuser.DeleteHardOneUserRequest
+	(*DeleteHardOneUserResponse)(nil),           // 23: pb.users.pbuser.DeleteHardOneUserResponse
+	(*LoginUserRequest)(nil),                    // 24: pb.users.pbuser.LoginUserRequest
+	(*LoginUserResponse)(nil),                   // 25: pb.users.pbuser.LoginUserResponse
+	(*VerifyTokenUserRequest)(nil),              // 26: pb.users.pbuser.VerifyTokenUserRequest
+	(*VerifyTokenUserResponse)(nil),             // 27: pb.users.pbuser.VerifyTokenUserResponse
+	(*RefreshTokenUserRequest)(nil),             // 28: pb.users.pbuser.RefreshTokenUserRequest
+	(*RefreshTokenUserResponse)(nil),            // 29: pb.users.pbuser.RefreshTokenUserResponse
+	(*Jwk)(nil),                                 // 30: pb.users.pbuser.Jwk
+	(*GetJwksUserRequest)(nil),                  // 31: pb.users.pbuser.GetJwksUserRequest
+	(*GetJwksUserResponse)(nil),                 // 32: pb.users.pbuser.GetJwksUserResponse
+	(*LogoutUserRequest)(nil),                   // 33: pb.users.pbuser.LogoutUserRequest
+	(*LogoutUserResponse)(nil),                  // 34: pb.users.pbuser.LogoutUserResponse
+	(*Session)(nil),                             // 35: pb.users.pbuser.Session
+	(*ListSessionsUserRequest)(nil),             // 36: pb.users.pbuser.ListSessionsUserRequest
+	(*ListSessionsUserResponse)(nil),            // 37: pb.users.pbuser.ListSessionsUserResponse
+	(*RevokeSessionUserRequest)(nil),            // 38: pb.users.pbuser.RevokeSessionUserRequest
+	(*RevokeSessionUserResponse)(nil),           // 39: pb.users.pbuser.RevokeSessionUserResponse
+	(*RequestPasswordResetRequest)(nil),         // 40: pb.users.pbuser.RequestPasswordResetRequest
+	(*RequestPasswordResetResponse)(nil),        // 41: pb.users.pbuser.RequestPasswordResetResponse
+	(*ConfirmPasswordResetRequest)(nil),         // 42: pb.users.pbuser.ConfirmPasswordResetRequest
+	(*ConfirmPasswordResetResponse)(nil),        // 43: pb.users.pbuser.ConfirmPasswordResetResponse
+	(*VerifyEmailUserRequest)(nil),              // 44: pb.users.pbuser.VerifyEmailUserRequest
+	(*VerifyEmailUserResponse)(nil),             // 45: pb.users.pbuser.VerifyEmailUserResponse
+	(*UnlockUserRequest)(nil),                   // 46: pb.users.pbuser.UnlockUserRequest
+	(*UnlockUserResponse)(nil),                  // 47: pb.users.pbuser.UnlockUserResponse
+	(*VerifyMfaUserRequest)(nil),                // 48: pb.users.pbuser.VerifyMfaUserRequest
+	(*VerifyMfaUserResponse)(nil),               // 49: pb.users.pbuser.VerifyMfaUserResponse
+	(*EnrollTotpUserRequest)(nil),               // 50: pb.users.pbuser.EnrollTotpUserRequest
+	(*EnrollTotpUserResponse)(nil),              // 51: pb.users.pbuser.EnrollTotpUserResponse
+	(*ConfirmTotpUserRequest)(nil),              // 52: pb.users.pbuser.ConfirmTotpUserRequest
+	(*ConfirmTotpUserResponse)(nil),             // 53: pb.users.pbuser.ConfirmTotpUserResponse
+	(*IssueMfaEnrollmentUserRequest)(nil),       // 54: pb.users.pbuser.IssueMfaEnrollmentUserRequest
+	(*IssueMfaEnrollmentUserResponse)(nil),      // 55: pb.users.pbuser.IssueMfaEnrollmentUserResponse
+	(*DisableTotpUserRequest)(nil),              // 56: pb.users.pbuser.DisableTotpUserRequest
+	(*DisableTotpUserResponse)(nil),             // 57: pb.users.pbuser.DisableTotpUserResponse
+	(*UpdateMfaRoleUserRequest)(nil),            // 58: pb.users.pbuser.UpdateMfaRoleUserRequest
+	(*UpdateMfaRoleUserResponse)(nil),           // 59: pb.users.pbuser.UpdateMfaRoleUserResponse
+	(*timestamppb.Timestamp)(nil),               // 60: google.protobuf.Timestamp
 }
 var file_pb_users_v1_users_proto_depIdxs = []int32{
 	0,  // 0: pb.users.pbuser.CreateOneUserRequest.role:type_name -> pb.users.pbuser.UserRole
 	0,  // 1: pb.users.pbuser.UserSummary.role:type_name -> pb.users.pbuser.UserRole
-	3,  // 2: pb.users.pbuser.GetManyUserResponse.users:type_name -> pb.users.pbuser.UserSummary
-	0,  // 3: pb.users.pbuser.UpdateOneRoleUserRequest.role:type_name -> pb.users.pbuser.UserRole
-	26, // 4: pb.users.pbuser.VerifyTokenUserResponse.exp:type_name -> google.protobuf.Timestamp
-	26, // 5: pb.users.pbuser.VerifyTokenUserResponse.iat:type_name -> google.protobuf.Timestamp
-	26, // 6: pb.users.pbuser.VerifyTokenUserResponse.nbf:type_name -> google.protobuf.Timestamp
-	1,  // 7: pb.users.pbuser.UserService.CreateOneUser:input_type -> pb.users.pbuser.CreateOneUserRequest
-	6,  // 8: pb.users.pbuser.UserService.GetOneUser:input_type -> pb.users.pbuser.GetOneUserRequest
-	8,  // 9: pb.users.pbuser.UserService.GetOneCredentialUserByEmail:input_type -> pb.users.pbuser.GetOneCredentialUserByEmailRequest
-	4,  // 10: pb.users.pbuser.UserService.GetManyUser:input_type -> pb.users.pbuser.GetManyUserRequest
-	10, // 11: pb.users.pbuser.UserService.UpdateOnePasswordUser:input_type -> pb.users.pbuser.UpdateOnePasswordUserRequest
-	12, // 12: pb.users.pbuser.UserService.UpdateOneEmailUser:input_type -> pb.users.pbuser.UpdateOneEmailUserRequest
-	14, // 13: pb.users.pbuser.UserService.UpdateOneRoleUser:input_type -> pb.users.pbuser.UpdateOneRoleUserRequest
-	16, // 14: pb.users.pbuser.UserService.DeleteSoftOneUser:input_type -> pb.users.pbuser.DeleteSoftOneUserRequest
-	18, // 15: pb.users.pbuser.UserService.DeleteHardOneUser:input_type -> pb.users.pbuser.DeleteHardOneUserRequest
-	20, // 16: pb.users.pbuser.UserService.LoginUser:input_type -> pb.users.pbuser.LoginUserRequest
-	22, // 17: pb.users.pbuser.UserService.VerifyTokenUser:input_type -> pb.users.pbuser.VerifyTokenUserRequest
-	24, // 18: pb.users.pbuser.UserService.RefreshTokenUser:input_type -> pb.users.pbuser.RefreshTokenUserRequest
-	2,  // 19: pb.users.pbuser.UserService.CreateOneUser:output_type -> pb.users.pbuser.CreateOneUserResponse
-	7,  // 20: pb.users.pbuser.UserService.GetOneUser:output_type -> pb.users.pbuser.GetOneUserResponse
-	9,  // 21: pb.users.pbuser.UserService.GetOneCredentialUserByEmail:output_type -> pb.users.pbuser.GetOneCredentialUserByEmailResponse
-	5,  // 22: pb.users.pbuser.UserService.GetManyUser:output_type -> pb.users.pbuser.GetManyUserResponse
-	11, // 23: pb.users.pbuser.UserService.UpdateOnePasswordUser:output_type -> pb.users.pbuser.UpdateOnePasswordUserResponse
-	13, // 24: pb.users.pbuser.UserService.UpdateOneEmailUser:output_type -> pb.users.pbuser.UpdateOneEmailUserResponse
-	15, // 25: pb.users.pbuser.UserService.UpdateOneRoleUser:output_type -> pb.users.pbuser.UpdateOneRoleUserResponse
-	17, // 26: pb.users.pbuser.UserService.DeleteSoftOneUser:output_type -> pb.users.pbuser.DeleteSoftOneUserResponse
-	19, // 27: pb.users.pbuser.UserService.DeleteHardOneUser:output_type -> pb.users.pbuser.DeleteHardOneUserResponse
-	21, // 28: pb.users.pbuser.UserService.LoginUser:output_type -> pb.users.pbuser.LoginUserResponse
-	23, // 29: pb.users.pbuser.UserService.VerifyTokenUser:output_type -> pb.users.pbuser.VerifyTokenUserResponse
-	25, // 30: pb.users.pbuser.UserService.RefreshTokenUser:output_type -> pb.users.pbuser.RefreshTokenUserResponse
-	19, // [19:31] is the sub-list for method output_type
-	7,  // [7:19] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	60, // 2: pb.users.pbuser.UserSummary.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: pb.users.pbuser.GetManyUserRequest.sort:type_name -> pb.users.pbuser.UserSort
+	0,  // 4: pb.users.pbuser.GetManyUserRequest.role:type_name -> pb.users.pbuser.UserRole
+	60, // 5: pb.users.pbuser.GetManyUserRequest.created_after:type_name -> google.protobuf.Timestamp
+	60, // 6: pb.users.pbuser.GetManyUserRequest.created_before:type_name -> google.protobuf.Timestamp
+	4,  // 7: pb.users.pbuser.GetManyUserResponse.users:type_name -> pb.users.pbuser.UserSummary
+	0,  // 8: pb.users.pbuser.SearchUsersRequest.roles:type_name -> pb.users.pbuser.UserRole
+	4,  // 9: pb.users.pbuser.UserSearchResult.user:type_name -> pb.users.pbuser.UserSummary
+	8,  // 10: pb.users.pbuser.SearchUsersResponse.results:type_name -> pb.users.pbuser.UserSearchResult
+	0,  // 11: pb.users.pbuser.UpdateOneRoleUserRequest.role:type_name -> pb.users.pbuser.UserRole
+	60, // 12: pb.users.pbuser.VerifyTokenUserResponse.exp:type_name -> google.protobuf.Timestamp
+	60, // 13: pb.users.pbuser.VerifyTokenUserResponse.iat:type_name -> google.protobuf.Timestamp
+	60, // 14: pb.users.pbuser.VerifyTokenUserResponse.nbf:type_name -> google.protobuf.Timestamp
+	30, // 15: pb.users.pbuser.GetJwksUserResponse.keys:type_name -> pb.users.pbuser.Jwk
+	60, // 16: pb.users.pbuser.Session.created_at:type_name -> google.protobuf.Timestamp
+	60, // 17: pb.users.pbuser.Session.expires_at:type_name -> google.protobuf.Timestamp
+	35, // 18: pb.users.pbuser.ListSessionsUserResponse.sessions:type_name -> pb.users.pbuser.Session
+	0,  // 19: pb.users.pbuser.UpdateMfaRoleUserRequest.role:type_name -> pb.users.pbuser.UserRole
+	2,  // 20: pb.users.pbuser.UserService.CreateOneUser:input_type -> pb.users.pbuser.CreateOneUserRequest
+	10, // 21: pb.users.pbuser.UserService.GetOneUser:input_type -> pb.users.pbuser.GetOneUserRequest
+	12, // 22: pb.users.pbuser.UserService.GetOneCredentialUserByEmail:input_type -> pb.users.pbuser.GetOneCredentialUserByEmailRequest
+	5,  // 23: pb.users.pbuser.UserService.GetManyUser:input_type -> pb.users.pbuser.GetManyUserRequest
+	7,  // 24: pb.users.pbuser.UserService.SearchUsers:input_type -> pb.users.pbuser.SearchUsersRequest
+	14, // 25: pb.users.pbuser.UserService.UpdateOnePasswordUser:input_type -> pb.users.pbuser.UpdateOnePasswordUserRequest
+	16, // 26: pb.users.pbuser.UserService.UpdateOneEmailUser:input_type -> pb.users.pbuser.UpdateOneEmailUserRequest
+	18, // 27: pb.users.pbuser.UserService.UpdateOneRoleUser:input_type -> pb.users.pbuser.UpdateOneRoleUserRequest
+	20, // 28: pb.users.pbuser.UserService.DeleteSoftOneUser:input_type -> pb.users.pbuser.DeleteSoftOneUserRequest
+	22, // 29: pb.users.pbuser.UserService.DeleteHardOneUser:input_type -> pb.users.pbuser.DeleteHardOneUserRequest
+	24, // 30: pb.users.pbuser.UserService.LoginUser:input_type -> pb.users.pbuser.LoginUserRequest
+	26, // 31: pb.users.pbuser.UserService.VerifyTokenUser:input_type -> pb.users.pbuser.VerifyTokenUserRequest
+	28, // 32: pb.users.pbuser.UserService.RefreshTokenUser:input_type -> pb.users.pbuser.RefreshTokenUserRequest
+	31, // 33: pb.users.pbuser.UserService.GetJwksUser:input_type -> pb.users.pbuser.GetJwksUserRequest
+	46, // 34: pb.users.pbuser.UserService.UnlockUser:input_type -> pb.users.pbuser.UnlockUserRequest
+	33, // 35: pb.users.pbuser.UserService.LogoutUser:input_type -> pb.users.pbuser.LogoutUserRequest
+	36, // 36: pb.users.pbuser.UserService.ListSessionsUser:input_type -> pb.users.pbuser.ListSessionsUserRequest
+	38, // 37: pb.users.pbuser.UserService.RevokeSessionUser:input_type -> pb.users.pbuser.RevokeSessionUserRequest
+	40, // 38: pb.users.pbuser.UserService.RequestPasswordReset:input_type -> pb.users.pbuser.RequestPasswordResetRequest
+	42, // 39: pb.users.pbuser.UserService.ConfirmPasswordReset:input_type -> pb.users.pbuser.ConfirmPasswordResetRequest
+	44, // 40: pb.users.pbuser.UserService.VerifyEmailUser:input_type -> pb.users.pbuser.VerifyEmailUserRequest
+	48, // 41: pb.users.pbuser.UserService.VerifyMfaUser:input_type -> pb.users.pbuser.VerifyMfaUserRequest
+	50, // 42: pb.users.pbuser.UserService.EnrollTotpUser:input_type -> pb.users.pbuser.EnrollTotpUserRequest
+	52, // 43: pb.users.pbuser.UserService.ConfirmTotpUser:input_type -> pb.users.pbuser.ConfirmTotpUserRequest
+	54, // 44: pb.users.pbuser.UserService.IssueMfaEnrollmentUser:input_type -> pb.users.pbuser.IssueMfaEnrollmentUserRequest
+	56, // 45: pb.users.pbuser.UserService.DisableTotpUser:input_type -> pb.users.pbuser.DisableTotpUserRequest
+	58, // 46: pb.users.pbuser.UserService.UpdateMfaRoleUser:input_type -> pb.users.pbuser.UpdateMfaRoleUserRequest
+	3,  // 47: pb.users.pbuser.UserService.CreateOneUser:output_type -> pb.users.pbuser.CreateOneUserResponse
+	11, // 48: pb.users.pbuser.UserService.GetOneUser:output_type -> pb.users.pbuser.GetOneUserResponse
+	13, // 49: pb.users.pbuser.UserService.GetOneCredentialUserByEmail:output_type -> pb.users.pbuser.GetOneCredentialUserByEmailResponse
+	6,  // 50: pb.users.pbuser.UserService.GetManyUser:output_type -> pb.users.pbuser.GetManyUserResponse
+	9,  // 51: pb.users.pbuser.UserService.SearchUsers:output_type -> pb.users.pbuser.SearchUsersResponse
+	15, // 52: pb.users.pbuser.UserService.UpdateOnePasswordUser:output_type -> pb.users.pbuser.UpdateOnePasswordUserResponse
+	17, // 53: pb.users.pbuser.UserService.UpdateOneEmailUser:output_type -> pb.users.pbuser.UpdateOneEmailUserResponse
+	19, // 54: pb.users.pbuser.UserService.UpdateOneRoleUser:output_type -> pb.users.pbuser.UpdateOneRoleUserResponse
+	21, // 55: pb.users.pbuser.UserService.DeleteSoftOneUser:output_type -> pb.users.pbuser.DeleteSoftOneUserResponse
+	23, // 56: pb.users.pbuser.UserService.DeleteHardOneUser:output_type -> pb.users.pbuser.DeleteHardOneUserResponse
+	25, // 57: pb.users.pbuser.UserService.LoginUser:output_type -> pb.users.pbuser.LoginUserResponse
+	27, // 58: pb.users.pbuser.UserService.VerifyTokenUser:output_type -> pb.users.pbuser.VerifyTokenUserResponse
+	29, // 59: pb.users.pbuser.UserService.RefreshTokenUser:output_type -> pb.users.pbuser.RefreshTokenUserResponse
+	32, // 60: pb.users.pbuser.UserService.GetJwksUser:output_type -> pb.users.pbuser.GetJwksUserResponse
+	47, // 61: pb.users.pbuser.UserService.UnlockUser:output_type -> pb.users.pbuser.UnlockUserResponse
+	34, // 62: pb.users.pbuser.UserService.LogoutUser:output_type -> pb.users.pbuser.LogoutUserResponse
+	37, // 63: pb.users.pbuser.UserService.ListSessionsUser:output_type -> pb.users.pbuser.ListSessionsUserResponse
+	39, // 64: pb.users.pbuser.UserService.RevokeSessionUser:output_type -> pb.users.pbuser.RevokeSessionUserResponse
+	41, // 65: pb.users.pbuser.UserService.RequestPasswordReset:output_type -> pb.users.pbuser.RequestPasswordResetResponse
+	43, // 66: pb.users.pbuser.UserService.ConfirmPasswordReset:output_type -> pb.users.pbuser.ConfirmPasswordResetResponse
+	45, // 67: pb.users.pbuser.UserService.VerifyEmailUser:output_type -> pb.users.pbuser.VerifyEmailUserResponse
+	49, // 68: pb.users.pbuser.UserService.VerifyMfaUser:output_type -> pb.users.pbuser.VerifyMfaUserResponse
+	51, // 69: pb.users.pbuser.UserService.EnrollTotpUser:output_type -> pb.users.pbuser.EnrollTotpUserResponse
+	53, // 70: pb.users.pbuser.UserService.ConfirmTotpUser:output_type -> pb.users.pbuser.ConfirmTotpUserResponse
+	55, // 71: pb.users.pbuser.UserService.IssueMfaEnrollmentUser:output_type -> pb.users.pbuser.IssueMfaEnrollmentUserResponse
+	57, // 72: pb.users.pbuser.UserService.DisableTotpUser:output_type -> pb.users.pbuser.DisableTotpUserResponse
+	59, // 73: pb.users.pbuser.UserService.UpdateMfaRoleUser:output_type -> pb.users.pbuser.UpdateMfaRoleUserResponse
+	47, // [47:74] is the sub-list for method output_type
+	20, // [20:47] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_pb_users_v1_users_proto_init() }
@@ -1542,8 +3600,8 @@ func file_pb_users_v1_users_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pb_users_v1_users_proto_rawDesc), len(file_pb_users_v1_users_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   25,
+			NumEnums:      2,
+			NumMessages:   58,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,7 @@ const (
 	UserService_GetOneUser_FullMethodName                  = "/pb.users.pbuser.UserService/GetOneUser"
 	UserService_GetOneCredentialUserByEmail_FullMethodName = "/pb.users.pbuser.UserService/GetOneCredentialUserByEmail"
 	UserService_GetManyUser_FullMethodName                 = "/pb.users.pbuser.UserService/GetManyUser"
+	UserService_SearchUsers_FullMethodName                 = "/pb.users.pbuser.UserService/SearchUsers"
 	UserService_UpdateOnePasswordUser_FullMethodName       = "/pb.users.pbuser.UserService/UpdateOnePasswordUser"
 	UserService_UpdateOneEmailUser_FullMethodName          = "/pb.users.pbuser.UserService/UpdateOneEmailUser"
 	UserService_UpdateOneRoleUser_FullMethodName           = "/pb.users.pbuser.UserService/UpdateOneRoleUser"
@@ -31,6 +32,20 @@ const (
 	UserService_LoginUser_FullMethodName                   = "/pb.users.pbuser.UserService/LoginUser"
 	UserService_VerifyTokenUser_FullMethodName             = "/pb.users.pbuser.UserService/VerifyTokenUser"
 	UserService_RefreshTokenUser_FullMethodName            = "/pb.users.pbuser.UserService/RefreshTokenUser"
+	UserService_GetJwksUser_FullMethodName                 = "/pb.users.pbuser.UserService/GetJwksUser"
+	UserService_UnlockUser_FullMethodName                  = "/pb.users.pbuser.UserService/UnlockUser"
+	UserService_LogoutUser_FullMethodName                  = "/pb.users.pbuser.UserService/LogoutUser"
+	UserService_ListSessionsUser_FullMethodName            = "/pb.users.pbuser.UserService/ListSessionsUser"
+	UserService_RevokeSessionUser_FullMethodName           = "/pb.users.pbuser.UserService/RevokeSessionUser"
+	UserService_RequestPasswordReset_FullMethodName        = "/pb.users.pbuser.UserService/RequestPasswordReset"
+	UserService_ConfirmPasswordReset_FullMethodName        = "/pb.users.pbuser.UserService/ConfirmPasswordReset"
+	UserService_VerifyEmailUser_FullMethodName             = "/pb.users.pbuser.UserService/VerifyEmailUser"
+	UserService_VerifyMfaUser_FullMethodName               = "/pb.users.pbuser.UserService/VerifyMfaUser"
+	UserService_EnrollTotpUser_FullMethodName              = "/pb.users.pbuser.UserService/EnrollTotpUser"
+	UserService_ConfirmTotpUser_FullMethodName             = "/pb.users.pbuser.UserService/ConfirmTotpUser"
+	UserService_IssueMfaEnrollmentUser_FullMethodName      = "/pb.users.pbuser.UserService/IssueMfaEnrollmentUser"
+	UserService_DisableTotpUser_FullMethodName             = "/pb.users.pbuser.UserService/DisableTotpUser"
+	UserService_UpdateMfaRoleUser_FullMethodName           = "/pb.users.pbuser.UserService/UpdateMfaRoleUser"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -41,6 +56,7 @@ type UserServiceClient interface {
 	GetOneUser(ctx context.Context, in *GetOneUserRequest, opts ...grpc.CallOption) (*GetOneUserResponse, error)
 	GetOneCredentialUserByEmail(ctx context.Context, in *GetOneCredentialUserByEmailRequest, opts ...grpc.CallOption) (*GetOneCredentialUserByEmailResponse, error)
 	GetManyUser(ctx context.Context, in *GetManyUserRequest, opts ...grpc.CallOption) (*GetManyUserResponse, error)
+	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*SearchUsersResponse, error)
 	UpdateOnePasswordUser(ctx context.Context, in *UpdateOnePasswordUserRequest, opts ...grpc.CallOption) (*UpdateOnePasswordUserResponse, error)
 	UpdateOneEmailUser(ctx context.Context, in *UpdateOneEmailUserRequest, opts ...grpc.CallOption) (*UpdateOneEmailUserResponse, error)
 	UpdateOneRoleUser(ctx context.Context, in *UpdateOneRoleUserRequest, opts ...grpc.CallOption) (*UpdateOneRoleUserResponse, error)
@@ -50,6 +66,24 @@ type UserServiceClient interface {
 	LoginUser(ctx context.Context, in *LoginUserRequest, opts ...grpc.CallOption) (*LoginUserResponse, error)
 	VerifyTokenUser(ctx context.Context, in *VerifyTokenUserRequest, opts ...grpc.CallOption) (*VerifyTokenUserResponse, error)
 	RefreshTokenUser(ctx context.Context, in *RefreshTokenUserRequest, opts ...grpc.CallOption) (*RefreshTokenUserResponse, error)
+	GetJwksUser(ctx context.Context, in *GetJwksUserRequest, opts ...grpc.CallOption) (*GetJwksUserResponse, error)
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error)
+	// Session services
+	LogoutUser(ctx context.Context, in *LogoutUserRequest, opts ...grpc.CallOption) (*LogoutUserResponse, error)
+	ListSessionsUser(ctx context.Context, in *ListSessionsUserRequest, opts ...grpc.CallOption) (*ListSessionsUserResponse, error)
+	RevokeSessionUser(ctx context.Context, in *RevokeSessionUserRequest, opts ...grpc.CallOption) (*RevokeSessionUserResponse, error)
+	// Password reset services
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error)
+	ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmPasswordResetResponse, error)
+	// Email verification services
+	VerifyEmailUser(ctx context.Context, in *VerifyEmailUserRequest, opts ...grpc.CallOption) (*VerifyEmailUserResponse, error)
+	// Two-factor authentication services
+	VerifyMfaUser(ctx context.Context, in *VerifyMfaUserRequest, opts ...grpc.CallOption) (*VerifyMfaUserResponse, error)
+	EnrollTotpUser(ctx context.Context, in *EnrollTotpUserRequest, opts ...grpc.CallOption) (*EnrollTotpUserResponse, error)
+	ConfirmTotpUser(ctx context.Context, in *ConfirmTotpUserRequest, opts ...grpc.CallOption) (*ConfirmTotpUserResponse, error)
+	IssueMfaEnrollmentUser(ctx context.Context, in *IssueMfaEnrollmentUserRequest, opts ...grpc.CallOption) (*IssueMfaEnrollmentUserResponse, error)
+	DisableTotpUser(ctx context.Context, in *DisableTotpUserRequest, opts ...grpc.CallOption) (*DisableTotpUserResponse, error)
+	UpdateMfaRoleUser(ctx context.Context, in *UpdateMfaRoleUserRequest, opts ...grpc.CallOption) (*UpdateMfaRoleUserResponse, error)
 }
 
 type userServiceClient struct {
@@ -100,6 +134,16 @@ func (c *userServiceClient) GetManyUser(ctx context.Context, in *GetManyUserRequ
 	return out, nil
 }
 
+func (c *userServiceClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*SearchUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_SearchUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) UpdateOnePasswordUser(ctx context.Context, in *UpdateOnePasswordUserRequest, opts ...grpc.CallOption) (*UpdateOnePasswordUserResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateOnePasswordUserResponse)
@@ -180,6 +224,146 @@ func (c *userServiceClient) RefreshTokenUser(ctx context.Context, in *RefreshTok
 	return out, nil
 }
 
+func (c *userServiceClient) GetJwksUser(ctx context.Context, in *GetJwksUserRequest, opts ...grpc.CallOption) (*GetJwksUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJwksUserResponse)
+	err := c.cc.Invoke(ctx, UserService_GetJwksUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockUserResponse)
+	err := c.cc.Invoke(ctx, UserService_UnlockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) LogoutUser(ctx context.Context, in *LogoutUserRequest, opts ...grpc.CallOption) (*LogoutUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutUserResponse)
+	err := c.cc.Invoke(ctx, UserService_LogoutUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListSessionsUser(ctx context.Context, in *ListSessionsUserRequest, opts ...grpc.CallOption) (*ListSessionsUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsUserResponse)
+	err := c.cc.Invoke(ctx, UserService_ListSessionsUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeSessionUser(ctx context.Context, in *RevokeSessionUserRequest, opts ...grpc.CallOption) (*RevokeSessionUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionUserResponse)
+	err := c.cc.Invoke(ctx, UserService_RevokeSessionUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPasswordResetResponse)
+	err := c.cc.Invoke(ctx, UserService_RequestPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest, opts ...grpc.CallOption) (*ConfirmPasswordResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmPasswordResetResponse)
+	err := c.cc.Invoke(ctx, UserService_ConfirmPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) VerifyEmailUser(ctx context.Context, in *VerifyEmailUserRequest, opts ...grpc.CallOption) (*VerifyEmailUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyEmailUserResponse)
+	err := c.cc.Invoke(ctx, UserService_VerifyEmailUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) VerifyMfaUser(ctx context.Context, in *VerifyMfaUserRequest, opts ...grpc.CallOption) (*VerifyMfaUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyMfaUserResponse)
+	err := c.cc.Invoke(ctx, UserService_VerifyMfaUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) EnrollTotpUser(ctx context.Context, in *EnrollTotpUserRequest, opts ...grpc.CallOption) (*EnrollTotpUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollTotpUserResponse)
+	err := c.cc.Invoke(ctx, UserService_EnrollTotpUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmTotpUser(ctx context.Context, in *ConfirmTotpUserRequest, opts ...grpc.CallOption) (*ConfirmTotpUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmTotpUserResponse)
+	err := c.cc.Invoke(ctx, UserService_ConfirmTotpUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) IssueMfaEnrollmentUser(ctx context.Context, in *IssueMfaEnrollmentUserRequest, opts ...grpc.CallOption) (*IssueMfaEnrollmentUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IssueMfaEnrollmentUserResponse)
+	err := c.cc.Invoke(ctx, UserService_IssueMfaEnrollmentUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DisableTotpUser(ctx context.Context, in *DisableTotpUserRequest, opts ...grpc.CallOption) (*DisableTotpUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableTotpUserResponse)
+	err := c.cc.Invoke(ctx, UserService_DisableTotpUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateMfaRoleUser(ctx context.Context, in *UpdateMfaRoleUserRequest, opts ...grpc.CallOption) (*UpdateMfaRoleUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateMfaRoleUserResponse)
+	err := c.cc.Invoke(ctx, UserService_UpdateMfaRoleUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -188,6 +372,7 @@ type UserServiceServer interface {
 	GetOneUser(context.Context, *GetOneUserRequest) (*GetOneUserResponse, error)
 	GetOneCredentialUserByEmail(context.Context, *GetOneCredentialUserByEmailRequest) (*GetOneCredentialUserByEmailResponse, error)
 	GetManyUser(context.Context, *GetManyUserRequest) (*GetManyUserResponse, error)
+	SearchUsers(context.Context, *SearchUsersRequest) (*SearchUsersResponse, error)
 	UpdateOnePasswordUser(context.Context, *UpdateOnePasswordUserRequest) (*UpdateOnePasswordUserResponse, error)
 	UpdateOneEmailUser(context.Context, *UpdateOneEmailUserRequest) (*UpdateOneEmailUserResponse, error)
 	UpdateOneRoleUser(context.Context, *UpdateOneRoleUserRequest) (*UpdateOneRoleUserResponse, error)
@@ -197,6 +382,24 @@ type UserServiceServer interface {
 	LoginUser(context.Context, *LoginUserRequest) (*LoginUserResponse, error)
 	VerifyTokenUser(context.Context, *VerifyTokenUserRequest) (*VerifyTokenUserResponse, error)
 	RefreshTokenUser(context.Context, *RefreshTokenUserRequest) (*RefreshTokenUserResponse, error)
+	GetJwksUser(context.Context, *GetJwksUserRequest) (*GetJwksUserResponse, error)
+	UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error)
+	// Session services
+	LogoutUser(context.Context, *LogoutUserRequest) (*LogoutUserResponse, error)
+	ListSessionsUser(context.Context, *ListSessionsUserRequest) (*ListSessionsUserResponse, error)
+	RevokeSessionUser(context.Context, *RevokeSessionUserRequest) (*RevokeSessionUserResponse, error)
+	// Password reset services
+	RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error)
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error)
+	// Email verification services
+	VerifyEmailUser(context.Context, *VerifyEmailUserRequest) (*VerifyEmailUserResponse, error)
+	// Two-factor authentication services
+	VerifyMfaUser(context.Context, *VerifyMfaUserRequest) (*VerifyMfaUserResponse, error)
+	EnrollTotpUser(context.Context, *EnrollTotpUserRequest) (*EnrollTotpUserResponse, error)
+	ConfirmTotpUser(context.Context, *ConfirmTotpUserRequest) (*ConfirmTotpUserResponse, error)
+	IssueMfaEnrollmentUser(context.Context, *IssueMfaEnrollmentUserRequest) (*IssueMfaEnrollmentUserResponse, error)
+	DisableTotpUser(context.Context, *DisableTotpUserRequest) (*DisableTotpUserResponse, error)
+	UpdateMfaRoleUser(context.Context, *UpdateMfaRoleUserRequest) (*UpdateMfaRoleUserResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -219,6 +422,9 @@ func (UnimplementedUserServiceServer) GetOneCredentialUserByEmail(context.Contex
 func (UnimplementedUserServiceServer) GetManyUser(context.Context, *GetManyUserRequest) (*GetManyUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetManyUser not implemented")
 }
+func (UnimplementedUserServiceServer) SearchUsers(context.Context, *SearchUsersRequest) (*SearchUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUsers not implemented")
+}
 func (UnimplementedUserServiceServer) UpdateOnePasswordUser(context.Context, *UpdateOnePasswordUserRequest) (*UpdateOnePasswordUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateOnePasswordUser not implemented")
 }
@@ -243,6 +449,48 @@ func (UnimplementedUserServiceServer) VerifyTokenUser(context.Context, *VerifyTo
 func (UnimplementedUserServiceServer) RefreshTokenUser(context.Context, *RefreshTokenUserRequest) (*RefreshTokenUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RefreshTokenUser not implemented")
 }
+func (UnimplementedUserServiceServer) GetJwksUser(context.Context, *GetJwksUserRequest) (*GetJwksUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJwksUser not implemented")
+}
+func (UnimplementedUserServiceServer) UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockUser not implemented")
+}
+func (UnimplementedUserServiceServer) LogoutUser(context.Context, *LogoutUserRequest) (*LogoutUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LogoutUser not implemented")
+}
+func (UnimplementedUserServiceServer) ListSessionsUser(context.Context, *ListSessionsUserRequest) (*ListSessionsUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessionsUser not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeSessionUser(context.Context, *RevokeSessionUserRequest) (*RevokeSessionUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSessionUser not implemented")
+}
+func (UnimplementedUserServiceServer) RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPasswordReset not implemented")
+}
+func (UnimplementedUserServiceServer) VerifyEmailUser(context.Context, *VerifyEmailUserRequest) (*VerifyEmailUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmailUser not implemented")
+}
+func (UnimplementedUserServiceServer) VerifyMfaUser(context.Context, *VerifyMfaUserRequest) (*VerifyMfaUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMfaUser not implemented")
+}
+func (UnimplementedUserServiceServer) EnrollTotpUser(context.Context, *EnrollTotpUserRequest) (*EnrollTotpUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTotpUser not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmTotpUser(context.Context, *ConfirmTotpUserRequest) (*ConfirmTotpUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTotpUser not implemented")
+}
+func (UnimplementedUserServiceServer) IssueMfaEnrollmentUser(context.Context, *IssueMfaEnrollmentUserRequest) (*IssueMfaEnrollmentUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueMfaEnrollmentUser not implemented")
+}
+func (UnimplementedUserServiceServer) DisableTotpUser(context.Context, *DisableTotpUserRequest) (*DisableTotpUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTotpUser not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateMfaRoleUser(context.Context, *UpdateMfaRoleUserRequest) (*UpdateMfaRoleUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMfaRoleUser not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -336,6 +584,24 @@ func _UserService_GetManyUser_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SearchUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SearchUsers(ctx, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_UpdateOnePasswordUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateOnePasswordUserRequest)
 	if err := dec(in); err != nil {
@@ -480,6 +746,258 @@ func _UserService_RefreshTokenUser_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetJwksUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJwksUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetJwksUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetJwksUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetJwksUser(ctx, req.(*GetJwksUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UnlockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UnlockUser(ctx, req.(*UnlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_LogoutUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).LogoutUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_LogoutUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).LogoutUser(ctx, req.(*LogoutUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListSessionsUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListSessionsUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListSessionsUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListSessionsUser(ctx, req.(*ListSessionsUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeSessionUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeSessionUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RevokeSessionUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeSessionUser(ctx, req.(*RevokeSessionUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RequestPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ConfirmPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmPasswordReset(ctx, req.(*ConfirmPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_VerifyEmailUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).VerifyEmailUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_VerifyEmailUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).VerifyEmailUser(ctx, req.(*VerifyEmailUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_VerifyMfaUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMfaUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).VerifyMfaUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_VerifyMfaUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).VerifyMfaUser(ctx, req.(*VerifyMfaUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_EnrollTotpUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTotpUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EnrollTotpUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_EnrollTotpUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EnrollTotpUser(ctx, req.(*EnrollTotpUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmTotpUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTotpUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmTotpUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ConfirmTotpUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmTotpUser(ctx, req.(*ConfirmTotpUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_IssueMfaEnrollmentUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueMfaEnrollmentUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).IssueMfaEnrollmentUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_IssueMfaEnrollmentUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).IssueMfaEnrollmentUser(ctx, req.(*IssueMfaEnrollmentUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DisableTotpUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTotpUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DisableTotpUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DisableTotpUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DisableTotpUser(ctx, req.(*DisableTotpUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateMfaRoleUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMfaRoleUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateMfaRoleUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateMfaRoleUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateMfaRoleUser(ctx, req.(*UpdateMfaRoleUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -503,6 +1021,10 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetManyUser",
 			Handler:    _UserService_GetManyUser_Handler,
 		},
+		{
+			MethodName: "SearchUsers",
+			Handler:    _UserService_SearchUsers_Handler,
+		},
 		{
 			MethodName: "UpdateOnePasswordUser",
 			Handler:    _UserService_UpdateOnePasswordUser_Handler,
@@ -535,6 +1057,62 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RefreshTokenUser",
 			Handler:    _UserService_RefreshTokenUser_Handler,
 		},
+		{
+			MethodName: "GetJwksUser",
+			Handler:    _UserService_GetJwksUser_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _UserService_UnlockUser_Handler,
+		},
+		{
+			MethodName: "LogoutUser",
+			Handler:    _UserService_LogoutUser_Handler,
+		},
+		{
+			MethodName: "ListSessionsUser",
+			Handler:    _UserService_ListSessionsUser_Handler,
+		},
+		{
+			MethodName: "RevokeSessionUser",
+			Handler:    _UserService_RevokeSessionUser_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _UserService_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ConfirmPasswordReset",
+			Handler:    _UserService_ConfirmPasswordReset_Handler,
+		},
+		{
+			MethodName: "VerifyEmailUser",
+			Handler:    _UserService_VerifyEmailUser_Handler,
+		},
+		{
+			MethodName: "VerifyMfaUser",
+			Handler:    _UserService_VerifyMfaUser_Handler,
+		},
+		{
+			MethodName: "EnrollTotpUser",
+			Handler:    _UserService_EnrollTotpUser_Handler,
+		},
+		{
+			MethodName: "ConfirmTotpUser",
+			Handler:    _UserService_ConfirmTotpUser_Handler,
+		},
+		{
+			MethodName: "IssueMfaEnrollmentUser",
+			Handler:    _UserService_IssueMfaEnrollmentUser_Handler,
+		},
+		{
+			MethodName: "DisableTotpUser",
+			Handler:    _UserService_DisableTotpUser_Handler,
+		},
+		{
+			MethodName: "UpdateMfaRoleUser",
+			Handler:    _UserService_UpdateMfaRoleUser_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pb/users/v1/users.proto",
@@ -0,0 +1,125 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const emailVerificationTokenTTL = time.Hour * 24
+
+var (
+	errInvalidVerificationToken = status.Error(codes.InvalidArgument, "invalid or expired verification token")
+	errEmailNotVerified         = status.Error(codes.FailedPrecondition, "email not verified")
+)
+
+// requireVerifiedEmail report whether login is refused for account with
+// unverified email. It read REQUIRE_VERIFIED_EMAIL environment variable
+// and default to false.
+func requireVerifiedEmail() bool {
+	env, ok := os.LookupEnv("REQUIRE_VERIFIED_EMAIL")
+	if !ok || env == "" {
+		return false
+	}
+	required, err := strconv.ParseBool(env)
+	if err != nil {
+		log.Printf("error: invalid REQUIRE_VERIFIED_EMAIL value %s\n", env)
+		return false
+	}
+	return required
+}
+
+// createEmailVerification replace pending verification token of user
+// with new one for email using q and return the plain token. It is meant
+// to run in same transaction as the change of email, token is sent by
+// sendEmailVerification once committed.
+func createEmailVerification(ctx context.Context, q db.Querier, userID uuid.UUID, email string) (string, error) {
+	if err := q.InvalidateManyEmailVerificationTokenByUser(ctx, userID); err != nil {
+		return "", err
+	}
+
+	secret, secretHash, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	_, err = q.CreateOneEmailVerificationToken(ctx, &db.CreateOneEmailVerificationTokenParams{
+		ID:        id,
+		UserID:    userID,
+		Email:     email,
+		TokenHash: secretHash,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(emailVerificationTokenTTL), Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// sendEmailVerification send verification token to email. Failure is
+// only logged since token is already stored and can be requested again.
+func (s *service) sendEmailVerification(ctx context.Context, email, secret string) {
+	body := fmt.Sprintf("Use this token to verify your email: %s", secret)
+	if url, ok := os.LookupEnv("EMAIL_VERIFICATION_URL"); ok {
+		body = fmt.Sprintf("Open this link to verify your email: %s?token=%s", url, secret)
+	}
+	err := s.n.Notify(ctx, &notifier.Message{
+		To:      email,
+		Subject: "Email verification",
+		Body:    body,
+	})
+	if err != nil {
+		log.Printf("error: failed to send verification token. %s\n", err.Error())
+	}
+}
+
+func (s *service) VerifyEmailUser(
+	ctx context.Context,
+	req *pbusers.VerifyEmailUserRequest,
+) (*pbusers.VerifyEmailUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Consume verification token and verify email atomically. Email must
+	// not be changed after token was issued.
+	var verifiedID uuid.UUID
+	err := s.tx.RunTx(ctx, func(q db.Querier) error {
+		verification, err := q.UseOneEmailVerificationToken(ctx, hashSecretToken(req.Token))
+		if err != nil {
+			return err
+		}
+		verifiedID, err = q.VerifyOneEmailUser(ctx, &db.VerifyOneEmailUserParams{
+			ID:    verification.UserID,
+			Email: verification.Email,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errInvalidVerificationToken
+		}
+		return nil, dbError(err, "verify user email")
+	}
+
+	return &pbusers.VerifyEmailUserResponse{
+		Id: verifiedID.String(),
+	}, nil
+}
@@ -1,8 +1,21 @@
 package svc
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/nurfianqodar/school-microservices/services/users/db"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
+	v "github.com/nurfianqodar/school-microservices/services/users/utils/validation"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -23,3 +36,74 @@ func convertRole(r pbusers.UserRole) (db.UserRole, error) {
 		return "", status.Error(codes.InvalidArgument, "invalid user role")
 	}
 }
+
+func convertDBRole(r db.UserRole) pbusers.UserRole {
+	switch r {
+	case db.UserRoleParent:
+		return pbusers.UserRole_Parent
+	case db.UserRoleStaff:
+		return pbusers.UserRole_Staff
+	case db.UserRoleStudent:
+		return pbusers.UserRole_Student
+	case db.UserRoleTeacher:
+		return pbusers.UserRole_Teacher
+	default:
+		return pbusers.UserRole_Unspecified
+	}
+}
+
+// validateRequest validate gRPC request using registered rules and
+// convert validation errors to InvalidArgument status
+func validateRequest(req any) error {
+	if err := v.Validate.Struct(req); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			return errs.ConvertValidationError(validationErrs, v.Trans)
+		}
+		log.Printf("error: failed to validate data. %s", err.Error())
+		return errs.ErrInternalServer
+	}
+	return nil
+}
+
+// dbError convert error returned by query or transaction to status.
+// Status error returned by transaction function is kept as is.
+func dbError(err error, action string) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if database.IsUniqueViolation(err) {
+		return status.Error(codes.AlreadyExists, "resource already exist")
+	}
+	log.Printf("error: failed to %s. %s\n", action, err.Error())
+	return errs.ErrInternalServer
+}
+
+// isSelf report whether authenticated caller is user id
+func isSelf(ctx context.Context, id uuid.UUID) bool {
+	claims, ok := policy.Claims(ctx)
+	return ok && claims.Sub == id.String()
+}
+
+func parseID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+	return parsed, nil
+}
+
+// newSecretToken generate random url safe token along with its SHA-256
+// digest which is stored in database
+func newSecretToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	return secret, hashSecretToken(secret), nil
+}
+
+func hashSecretToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
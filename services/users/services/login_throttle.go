@@ -0,0 +1,273 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// Failed attempt older than this window is forgotten
+	loginFailureWindow = time.Hour
+	// Lockout start at base duration and double on every following failure
+	loginLockoutBase = time.Second * 30
+	loginLockoutMax  = time.Hour
+	// Failed attempt allowed before lockout
+	loginMaxFailuresUser = 5
+	loginMaxFailuresIP   = 20
+	// Password reset request allowed from single client before lockout,
+	// every request is counted as attempt
+	resetMaxRequestsIP = 10
+	// Password reset mail sent to single address within failure window,
+	// extra request is silently dropped instead of locked out
+	resetMaxMailsEmail = 3
+)
+
+// loginThrottle identify subject whose failed login attempts, or password
+// reset requests, are tracked
+type loginThrottle struct {
+	key         string
+	maxFailures int32
+	// reason reported to client while locked
+	reason string
+}
+
+func userLoginThrottle(id string) loginThrottle {
+	return loginThrottle{key: "user:" + id, maxFailures: loginMaxFailuresUser, reason: "too many failed login attempts"}
+}
+
+func ipLoginThrottle(ip string) loginThrottle {
+	return loginThrottle{key: "ip:" + ip, maxFailures: loginMaxFailuresIP, reason: "too many failed login attempts"}
+}
+
+// emailResetThrottle count reset mail sent to email. It is never locked,
+// see allowThrottled.
+func emailResetThrottle(email string) loginThrottle {
+	return loginThrottle{key: "reset-email:" + strings.ToLower(email), maxFailures: resetMaxMailsEmail}
+}
+
+func ipResetThrottle(ip string) loginThrottle {
+	return loginThrottle{key: "reset-ip:" + ip, maxFailures: resetMaxRequestsIP, reason: "too many password reset requests"}
+}
+
+// trustedProxies return gateway addresses allowed to forward client
+// address. It read comma separated IP or CIDR from TRUSTED_PROXIES
+// environment variable once when service is created, invalid entry is
+// ignored.
+func trustedProxies() []netip.Prefix {
+	env, ok := os.LookupEnv("TRUSTED_PROXIES")
+	if !ok {
+		return nil
+	}
+	prefixes := make([]netip.Prefix, 0)
+	for entry := range strings.SplitSeq(env, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			log.Printf("error: invalid TRUSTED_PROXIES entry %s\n", entry)
+			continue
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes
+}
+
+// clientIP return address of client calling the service. Gateway forward
+// address of original client through x-real-ip metadata which is only
+// trusted when gRPC peer is listed in TRUSTED_PROXIES, otherwise address
+// of gRPC peer is used so direct client can not choose its throttle key.
+func (s *service) clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	peerAddr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	peerAddr = peerAddr.Unmap()
+	trusted := slices.ContainsFunc(s.proxies, func(prefix netip.Prefix) bool {
+		return prefix.Contains(peerAddr)
+	})
+	if !trusted {
+		return peerAddr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("x-real-ip"); len(values) > 0 {
+			if addr, err := netip.ParseAddr(values[0]); err == nil {
+				return addr.Unmap().String()
+			}
+		}
+	}
+	return peerAddr.String()
+}
+
+// lockoutDuration return lockout duration after failures consecutive
+// failed attempt or zero when failures is still allowed
+func lockoutDuration(failures, maxFailures int32) time.Duration {
+	if failures < maxFailures {
+		return 0
+	}
+	exp := float64(failures - maxFailures)
+	d := time.Duration(float64(loginLockoutBase) * math.Pow(2, exp))
+	if d <= 0 || d > loginLockoutMax {
+		return loginLockoutMax
+	}
+	return d
+}
+
+// errLoginLocked create ResourceExhausted status carrying retry delay
+func errLoginLocked(reason string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, reason)
+	ds, err := st.WithDetails(&epb.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter.Round(time.Second)),
+	})
+	if err != nil {
+		log.Printf("error: failed to create error detail. %s", err.Error())
+		return st.Err()
+	}
+	return ds.Err()
+}
+
+// checkLoginThrottle return lockout error when t is currently locked
+func (s *service) checkLoginThrottle(ctx context.Context, t loginThrottle) error {
+	throttle, err := s.q.GetOneLoginThrottle(ctx, t.key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		log.Printf("error: failed to get login throttle. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	if throttle.LockedUntil.Valid {
+		if retryAfter := time.Until(throttle.LockedUntil.Time); retryAfter > 0 {
+			return errLoginLocked(t.reason, retryAfter)
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure count failed attempt of t and lock it once allowed
+// failures is exceeded
+func (s *service) recordLoginFailure(ctx context.Context, t loginThrottle) error {
+	failures, err := s.q.UpsertOneFailureLoginThrottle(ctx, &db.UpsertOneFailureLoginThrottleParams{
+		Key:          t.key,
+		LastFailedAt: pgtype.Timestamptz{Time: time.Now().Add(-loginFailureWindow), Valid: true},
+	})
+	if err != nil {
+		log.Printf("error: failed to record login failure. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+
+	d := lockoutDuration(failures, t.maxFailures)
+	if d == 0 {
+		return nil
+	}
+	err = s.q.LockOneLoginThrottle(ctx, &db.LockOneLoginThrottleParams{
+		Key:         t.key,
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(d), Valid: true},
+	})
+	if err != nil {
+		log.Printf("error: failed to lock login throttle. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	return nil
+}
+
+// allowThrottled report whether t is still below its maximum within
+// failure window and count the attempt when it is. Unlike
+// recordLoginFailure it never lock t, refused attempt is not counted so
+// the window is only extended by allowed attempts.
+func (s *service) allowThrottled(ctx context.Context, t loginThrottle) (bool, error) {
+	throttle, err := s.q.GetOneLoginThrottle(ctx, t.key)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("error: failed to get login throttle. %s\n", err.Error())
+		return false, errs.ErrInternalServer
+	}
+	if err == nil && throttle.Failures >= t.maxFailures &&
+		throttle.LastFailedAt.Valid && time.Since(throttle.LastFailedAt.Time) < loginFailureWindow {
+		return false, nil
+	}
+
+	_, err = s.q.UpsertOneFailureLoginThrottle(ctx, &db.UpsertOneFailureLoginThrottleParams{
+		Key:          t.key,
+		LastFailedAt: pgtype.Timestamptz{Time: time.Now().Add(-loginFailureWindow), Valid: true},
+	})
+	if err != nil {
+		log.Printf("error: failed to record login throttle. %s\n", err.Error())
+		return false, errs.ErrInternalServer
+	}
+	return true, nil
+}
+
+// resetLoginThrottle forget failed attempt of t
+func (s *service) resetLoginThrottle(ctx context.Context, t loginThrottle) error {
+	if _, err := s.q.DeleteOneLoginThrottle(ctx, t.key); err != nil {
+		log.Printf("error: failed to reset login throttle. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	return nil
+}
+
+func (s *service) UnlockUser(
+	ctx context.Context,
+	req *pbusers.UnlockUserRequest,
+) (*pbusers.UnlockUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure user exists
+	count, err := s.q.CountIDUser(ctx, reqUUID)
+	if err != nil {
+		log.Printf("error: failed to count user by id. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	if count == 0 {
+		return nil, errUserNotFound
+	}
+
+	if err := s.resetLoginThrottle(ctx, userLoginThrottle(reqUUID.String())); err != nil {
+		return nil, err
+	}
+
+	return &pbusers.UnlockUserResponse{
+		Id: reqUUID.String(),
+	}, nil
+}
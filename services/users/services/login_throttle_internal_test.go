@@ -0,0 +1,44 @@
+package svc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.1")
+	s := &service{proxies: trustedProxies()}
+
+	tests := []struct {
+		name     string
+		peer     string
+		realIP   string
+		expected string
+	}{
+		{"untrusted peer spoofing header", "198.51.100.7:5000", "203.0.113.9", "198.51.100.7"},
+		{"trusted proxy by cidr", "10.1.2.3:5000", "203.0.113.9", "203.0.113.9"},
+		{"trusted proxy by address", "192.0.2.1:5000", "203.0.113.9", "203.0.113.9"},
+		{"trusted proxy without header", "10.1.2.3:5000", "", "10.1.2.3"},
+		{"trusted proxy with invalid header", "10.1.2.3:5000", "not-an-ip", "10.1.2.3"},
+		{"ipv4 mapped peer", "[::ffff:10.1.2.3]:5000", "203.0.113.9", "203.0.113.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.realIP != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-real-ip", tt.realIP))
+			}
+			if got := s.clientIP(ctx); got != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
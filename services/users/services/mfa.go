@@ -0,0 +1,566 @@
+package svc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/totp"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	mfaTokenTTL           = time.Minute * 5
+	mfaEnrollmentTokenTTL = time.Hour * 24
+	recoveryCodeCount     = 10
+	// Issuer shown by authenticator app when TOTP_ISSUER environment
+	// variable was not set
+	defaultTotpIssuer = "School"
+)
+
+var (
+	errInvalidMfaCode     = status.Error(codes.Unauthenticated, "invalid two-factor authentication code")
+	errMfaAlreadyEnrolled = status.Error(codes.AlreadyExists, "two-factor authentication already enabled")
+	errMfaNotEnrolled     = status.Error(codes.FailedPrecondition, "two-factor authentication not enrolled")
+	errMfaEnrollRequired  = status.Error(codes.FailedPrecondition, "MFA enrollment required")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func totpIssuer() string {
+	if issuer, ok := os.LookupEnv("TOTP_ISSUER"); ok && issuer != "" {
+		return issuer
+	}
+	return defaultTotpIssuer
+}
+
+// normalizeRecoveryCode make recovery code comparison insensitive to
+// case and separator
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// mfaRequired report whether user with role must use two-factor
+// authentication
+func (s *service) mfaRequired(ctx context.Context, role db.UserRole) (bool, error) {
+	required, err := s.q.GetOneMfaRolePolicy(ctx, role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		log.Printf("error: failed to get mfa role policy. %s\n", err.Error())
+		return false, errs.ErrInternalServer
+	}
+	return required, nil
+}
+
+// loginChallenge return MFA challenge response when user enrolled two-
+// factor authentication. Nil response mean tokens may be issued directly.
+// Enrollment secret is never handed out here since password alone must
+// not be enough to bind authenticator, user whose role require MFA must
+// enroll using enrollment token issued by staff.
+func (s *service) loginChallenge(
+	ctx context.Context,
+	creds *db.GetOneCredentialUserByEmailRow,
+	audience string,
+) (*pbusers.LoginUserResponse, error) {
+	enrolled := false
+	mfa, err := s.q.GetOneUserMfa(ctx, creds.ID)
+	if err == nil {
+		enrolled = mfa.ConfirmedAt.Valid
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("error: failed to get user mfa. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	if !enrolled {
+		required, err := s.mfaRequired(ctx, creds.Role)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return nil, errMfaEnrollRequired
+		}
+		return nil, nil
+	}
+
+	res := &pbusers.LoginUserResponse{MfaRequired: true}
+	sub := token.Subject{ID: creds.ID.String(), Role: string(creds.Role)}
+	res.MfaToken, _, err = token.CreateToken(token.TokenTypeMfa, sub, mfaTokenTTL, []string{audience})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// beginTotpEnrollment store new pending TOTP secret for user and return
+// it along with its otpauth URI
+func (s *service) beginTotpEnrollment(ctx context.Context, userID uuid.UUID, email string) (string, string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Printf("error: failed to generate totp secret. %s\n", err.Error())
+		return "", "", errs.ErrInternalServer
+	}
+	rows, err := s.q.UpsertOnePendingUserMfa(ctx, &db.UpsertOnePendingUserMfaParams{
+		UserID: userID,
+		Secret: secret,
+	})
+	if err != nil {
+		log.Printf("error: failed to save totp secret. %s\n", err.Error())
+		return "", "", errs.ErrInternalServer
+	}
+	if rows == 0 {
+		return "", "", errMfaAlreadyEnrolled
+	}
+	return secret, totp.URI(totpIssuer(), email, secret), nil
+}
+
+// verifyTotp check code against secret of user. Every code is accepted
+// at most once.
+func (s *service) verifyTotp(ctx context.Context, userID uuid.UUID, secret, code string) error {
+	step, ok, err := totp.Validate(secret, code, time.Now())
+	if err != nil {
+		log.Printf("error: failed to validate totp code. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	if !ok {
+		return errInvalidMfaCode
+	}
+
+	rows, err := s.q.UseStepUserMfa(ctx, &db.UseStepUserMfaParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		log.Printf("error: failed to update totp step. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	if rows == 0 {
+		return errInvalidMfaCode
+	}
+	return nil
+}
+
+// verifyMfaCode check TOTP code of user, anything but TOTP code is tried
+// as recovery code
+func (s *service) verifyMfaCode(ctx context.Context, userID uuid.UUID, secret, code string) error {
+	if len(code) != totp.Digits {
+		return s.useRecoveryCode(ctx, userID, code)
+	}
+	return s.verifyTotp(ctx, userID, secret, code)
+}
+
+// throttleMfaCode run verify which check code sent by user. Guessing code
+// is throttled as failed login.
+func (s *service) throttleMfaCode(ctx context.Context, userID uuid.UUID, verify func() error) error {
+	userThrottle := userLoginThrottle(userID.String())
+	if err := s.checkLoginThrottle(ctx, userThrottle); err != nil {
+		return err
+	}
+	if err := verify(); err != nil {
+		if errors.Is(err, errInvalidMfaCode) {
+			if err := s.recordLoginFailure(ctx, userThrottle); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	return s.resetLoginThrottle(ctx, userThrottle)
+}
+
+// useRecoveryCode consume unused recovery code of user matching code
+func (s *service) useRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error {
+	recoveryCodes, err := s.q.GetManyMfaRecoveryCodeByUser(ctx, userID)
+	if err != nil {
+		log.Printf("error: failed to get recovery codes. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+
+	code = normalizeRecoveryCode(code)
+	for _, rc := range recoveryCodes {
+		err := hasher.CompareHashWithPasswordContext(ctx, rc.CodeHash, code)
+		if err != nil {
+			if errors.Is(err, hasher.ErrSaturated) || ctx.Err() != nil {
+				return err
+			}
+			continue
+		}
+		rows, err := s.q.UseOneMfaRecoveryCode(ctx, rc.ID)
+		if err != nil {
+			log.Printf("error: failed to use recovery code. %s\n", err.Error())
+			return errs.ErrInternalServer
+		}
+		if rows == 0 {
+			return errInvalidMfaCode
+		}
+		return nil
+	}
+	return errInvalidMfaCode
+}
+
+// newRecoveryCodes generate recovery codes of user and return the plain
+// codes along with rows to be stored. Codes are only stored as hash.
+func newRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, []*db.CreateOneMfaRecoveryCodeParams, error) {
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	rows := make([]*db.CreateOneMfaRecoveryCodeParams, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			log.Printf("error: failed to generate recovery code. %s\n", err.Error())
+			return nil, nil, errs.ErrInternalServer
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+
+		codeHash, err := hasher.GenerateFromPasswordContext(ctx, code, hasher.DefaultConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		id, err := uuid.NewV7()
+		if err != nil {
+			log.Printf("error: failed to generate new uuid v7. %s\n", err.Error())
+			return nil, nil, errs.ErrInternalServer
+		}
+		rows = append(rows, &db.CreateOneMfaRecoveryCodeParams{
+			ID:       id,
+			UserID:   userID,
+			CodeHash: codeHash,
+		})
+		recoveryCodes = append(recoveryCodes, code[:4]+"-"+code[4:])
+	}
+	return recoveryCodes, rows, nil
+}
+
+// replaceRecoveryCodes replace recovery codes of user with rows using q
+func replaceRecoveryCodes(ctx context.Context, q db.Querier, userID uuid.UUID, rows []*db.CreateOneMfaRecoveryCodeParams) error {
+	if err := q.DeleteManyMfaRecoveryCodeByUser(ctx, userID); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := q.CreateOneMfaRecoveryCode(ctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) VerifyMfaUser(
+	ctx context.Context,
+	req *pbusers.VerifyMfaUserRequest,
+) (*pbusers.VerifyMfaUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	claims, err := token.VerifyToken(ctx, req.MfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.GetTokenType() != token.TokenTypeMfa {
+		return nil, errInvalidTokenType
+	}
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	user, err := s.q.GetOneUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		log.Printf("error: failed to get user by id. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	mfa, err := s.q.GetOneUserMfa(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		log.Printf("error: failed to get user mfa. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	// Pending enrollment can not complete challenge
+	if !mfa.ConfirmedAt.Valid {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	err = s.throttleMfaCode(ctx, userID, func() error {
+		return s.verifyMfaCode(ctx, userID, mfa.Secret, req.Code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Challenge can only be completed once
+	if err := token.RevokeToken(ctx, claims.Jti, claims.Exp.Time); err != nil {
+		log.Printf("error: failed to revoke mfa token. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	res := &pbusers.VerifyMfaUserResponse{}
+	sessionID, err := uuid.NewV7()
+	if err != nil {
+		log.Printf("error: failed to generate new uuid v7. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	sub := token.Subject{
+		ID:        claims.Sub,
+		Role:      string(user.Role),
+		SessionID: sessionID.String(),
+	}
+	res.AccessToken, res.RefreshToken, err = issueTokens(ctx, s.q, sub, claims.Aud)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *service) EnrollTotpUser(
+	ctx context.Context,
+	req *pbusers.EnrollTotpUserRequest,
+) (*pbusers.EnrollTotpUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.q.GetOneUser(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		log.Printf("error: failed to get user by id. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	secret, uri, err := s.beginTotpEnrollment(ctx, reqUUID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pbusers.EnrollTotpUserResponse{
+		Secret: secret,
+		Uri:    uri,
+	}, nil
+}
+
+func (s *service) ConfirmTotpUser(
+	ctx context.Context,
+	req *pbusers.ConfirmTotpUserRequest,
+) (*pbusers.ConfirmTotpUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.q.GetOneUserMfa(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errMfaNotEnrolled
+		}
+		log.Printf("error: failed to get user mfa. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	if mfa.ConfirmedAt.Valid {
+		return nil, errMfaAlreadyEnrolled
+	}
+
+	err = s.throttleMfaCode(ctx, reqUUID, func() error {
+		return s.verifyTotp(ctx, reqUUID, mfa.Secret, req.Code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Enrollment is only confirmed along with its recovery codes
+	recoveryCodes, recoveryRows, err := newRecoveryCodes(ctx, reqUUID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		rows, err := q.ConfirmOneUserMfa(ctx, reqUUID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return errMfaAlreadyEnrolled
+		}
+		return replaceRecoveryCodes(ctx, q, reqUUID, recoveryRows)
+	})
+	if err != nil {
+		return nil, dbError(err, "confirm user mfa")
+	}
+
+	// Enrollment token can only complete one enrollment
+	if claims, ok := policy.Claims(ctx); ok && claims.GetTokenType() == token.TokenTypeMfaEnrollment {
+		if err := token.RevokeToken(ctx, claims.Jti, claims.Exp.Time); err != nil {
+			log.Printf("error: failed to revoke mfa enrollment token. %s\n", err.Error())
+			return nil, errs.ErrInternalServer
+		}
+	}
+
+	return &pbusers.ConfirmTotpUserResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (s *service) IssueMfaEnrollmentUser(
+	ctx context.Context,
+	req *pbusers.IssueMfaEnrollmentUserRequest,
+) (*pbusers.IssueMfaEnrollmentUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.q.GetOneUser(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		log.Printf("error: failed to get user by id. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	mfa, err := s.q.GetOneUserMfa(ctx, reqUUID)
+	if err == nil && mfa.ConfirmedAt.Valid {
+		return nil, errMfaAlreadyEnrolled
+	} else if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("error: failed to get user mfa. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	// Token is sent to user email so staff never hold it
+	sub := token.Subject{ID: reqUUID.String(), Role: string(user.Role)}
+	enrollmentToken, _, err := token.CreateToken(token.TokenTypeMfaEnrollment, sub, mfaEnrollmentTokenTTL, token.Audiences())
+	if err != nil {
+		return nil, err
+	}
+	body := fmt.Sprintf("Use this token to enable two-factor authentication: %s", enrollmentToken)
+	if url, ok := os.LookupEnv("MFA_ENROLLMENT_URL"); ok {
+		body = fmt.Sprintf("Open this link to enable two-factor authentication: %s?token=%s", url, enrollmentToken)
+	}
+	err = s.n.Notify(ctx, &notifier.Message{
+		To:      user.Email,
+		Subject: "Two-factor authentication enrollment",
+		Body:    body,
+	})
+	if err != nil {
+		log.Printf("error: failed to send mfa enrollment token. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	return &pbusers.IssueMfaEnrollmentUserResponse{
+		Id: reqUUID.String(),
+	}, nil
+}
+
+func (s *service) DisableTotpUser(
+	ctx context.Context,
+	req *pbusers.DisableTotpUserRequest,
+) (*pbusers.DisableTotpUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.q.GetOneUserMfa(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errMfaNotEnrolled
+		}
+		log.Printf("error: failed to get user mfa. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	// Access token alone is not enough to remove second factor, staff
+	// may disable it for other user without code
+	if mfa.ConfirmedAt.Valid && isSelf(ctx, reqUUID) {
+		if req.Code == "" {
+			return nil, status.Error(codes.InvalidArgument, "code is required to disable two-factor authentication")
+		}
+		err := s.throttleMfaCode(ctx, reqUUID, func() error {
+			return s.verifyMfaCode(ctx, reqUUID, mfa.Secret, req.Code)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		rows, err := q.DeleteOneUserMfa(ctx, reqUUID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return errMfaNotEnrolled
+		}
+		return q.DeleteManyMfaRecoveryCodeByUser(ctx, reqUUID)
+	})
+	if err != nil {
+		return nil, dbError(err, "disable user mfa")
+	}
+
+	return &pbusers.DisableTotpUserResponse{
+		Id: reqUUID.String(),
+	}, nil
+}
+
+func (s *service) UpdateMfaRoleUser(
+	ctx context.Context,
+	req *pbusers.UpdateMfaRoleUserRequest,
+) (*pbusers.UpdateMfaRoleUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	role, err := convertRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.q.UpsertOneMfaRolePolicy(ctx, &db.UpsertOneMfaRolePolicyParams{
+		Role:     role,
+		Required: req.Required,
+	})
+	if err != nil {
+		log.Printf("error: failed to update mfa role policy. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	return &pbusers.UpdateMfaRoleUserResponse{}, nil
+}
@@ -0,0 +1,262 @@
+package svc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var errInvalidPageToken = status.Error(codes.InvalidArgument, "invalid page token")
+
+// likeEscaper escape LIKE wildcard so email prefix is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// pageCursor is decoded page token. It point to last user of previous
+// page and is bound to sort and filters it was issued for.
+type pageCursor struct {
+	Sort   pbusers.UserSort `json:"s"`
+	Filter string           `json:"f"`
+	ID     uuid.UUID        `json:"i"`
+	Email  string           `json:"e,omitempty"`
+}
+
+func encodePageToken(c *pageCursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageToken(token string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	c := new(pageCursor)
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, errInvalidPageToken
+	}
+	return c, nil
+}
+
+// userListQuery is GetManyUserRequest converted to query arguments
+type userListQuery struct {
+	filter   db.CountManyUserParams
+	sort     pbusers.UserSort
+	pageSize int32
+	cursor   *pageCursor
+}
+
+// filterDigest identify filters so page token can not be reused with
+// different filters
+func (q *userListQuery) filterDigest() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|", q.filter.Role.UserRole, q.filter.EmailPattern)
+	for _, ts := range []pgtype.Timestamptz{q.filter.CreatedAfter, q.filter.CreatedBefore} {
+		if ts.Valid {
+			fmt.Fprint(h, ts.Time.UnixNano())
+		}
+		fmt.Fprint(h, "|")
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// parseUserListQuery validate paging, sort and filters of req
+func parseUserListQuery(req *pbusers.GetManyUserRequest) (*userListQuery, error) {
+	q := &userListQuery{sort: req.Sort}
+	if _, ok := pbusers.UserSort_name[int32(req.Sort)]; !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid sort")
+	}
+
+	q.pageSize = defaultPageSize
+	if req.PageSize > 0 {
+		q.pageSize = int32(min(req.PageSize, maxPageSize))
+	}
+
+	if req.Role != pbusers.UserRole_Unspecified {
+		role, err := convertRole(req.Role)
+		if err != nil {
+			return nil, err
+		}
+		q.filter.Role = db.NullUserRole{UserRole: role, Valid: true}
+	}
+	if req.EmailPrefix != "" {
+		q.filter.EmailPattern = likeEscaper.Replace(req.EmailPrefix) + "%"
+	}
+	if req.CreatedAfter != nil {
+		q.filter.CreatedAfter = pgtype.Timestamptz{Time: req.CreatedAfter.AsTime(), Valid: true}
+	}
+	if req.CreatedBefore != nil {
+		q.filter.CreatedBefore = pgtype.Timestamptz{Time: req.CreatedBefore.AsTime(), Valid: true}
+	}
+	if q.filter.CreatedAfter.Valid && q.filter.CreatedBefore.Valid &&
+		!q.filter.CreatedAfter.Time.Before(q.filter.CreatedBefore.Time) {
+		return nil, status.Error(codes.InvalidArgument, "created_after must be before created_before")
+	}
+
+	if req.PageToken != "" {
+		c, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != q.sort || c.Filter != q.filterDigest() {
+			return nil, errInvalidPageToken
+		}
+		q.cursor = c
+	}
+	return q, nil
+}
+
+// listUsers run keyset query matching sort of q. One extra row is
+// fetched to know whether next page exist.
+func (s *service) listUsers(ctx context.Context, q *userListQuery) ([]*db.GetManyUserRow, error) {
+	var (
+		hasCursor bool
+		afterID   uuid.UUID
+		email     string
+	)
+	if q.cursor != nil {
+		hasCursor, afterID, email = true, q.cursor.ID, q.cursor.Email
+	}
+	f, limit := q.filter, q.pageSize+1
+
+	switch q.sort {
+	case pbusers.UserSort_CreatedDesc:
+		rows, err := s.q.GetManyUserDesc(ctx, &db.GetManyUserDescParams{
+			Role:          f.Role,
+			EmailPattern:  f.EmailPattern,
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			HasCursor:     hasCursor,
+			AfterID:       afterID,
+			PageSize:      limit,
+		})
+		return convertUserRows(rows), err
+	case pbusers.UserSort_EmailAsc:
+		rows, err := s.q.GetManyUserByEmail(ctx, &db.GetManyUserByEmailParams{
+			Role:          f.Role,
+			EmailPattern:  f.EmailPattern,
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			HasCursor:     hasCursor,
+			AfterEmail:    email,
+			AfterID:       afterID,
+			PageSize:      limit,
+		})
+		return convertUserRows(rows), err
+	case pbusers.UserSort_EmailDesc:
+		rows, err := s.q.GetManyUserByEmailDesc(ctx, &db.GetManyUserByEmailDescParams{
+			Role:          f.Role,
+			EmailPattern:  f.EmailPattern,
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			HasCursor:     hasCursor,
+			AfterEmail:    email,
+			AfterID:       afterID,
+			PageSize:      limit,
+		})
+		return convertUserRows(rows), err
+	default:
+		return s.q.GetManyUser(ctx, &db.GetManyUserParams{
+			Role:          f.Role,
+			EmailPattern:  f.EmailPattern,
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			HasCursor:     hasCursor,
+			AfterID:       afterID,
+			PageSize:      limit,
+		})
+	}
+}
+
+// convertUserRows convert rows of other sort order to GetManyUser row
+// since every query select same columns
+func convertUserRows[T ~struct {
+	ID        uuid.UUID
+	Email     string
+	Role      db.UserRole
+	CreatedAt pgtype.Timestamptz
+}](rows []*T) []*db.GetManyUserRow {
+	items := make([]*db.GetManyUserRow, 0, len(rows))
+	for _, row := range rows {
+		item := db.GetManyUserRow(*row)
+		items = append(items, &item)
+	}
+	return items
+}
+
+func (s *service) GetManyUser(
+	ctx context.Context,
+	req *pbusers.GetManyUserRequest,
+) (*pbusers.GetManyUserResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	q, err := parseUserListQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.listUsers(ctx, q)
+	if err != nil {
+		log.Printf("error: failed to get users. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	total, err := s.q.CountManyUser(ctx, &q.filter)
+	if err != nil {
+		log.Printf("error: failed to count users. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	res := &pbusers.GetManyUserResponse{
+		Users:      make([]*pbusers.UserSummary, 0, len(rows)),
+		TotalCount: uint64(total),
+	}
+	if int32(len(rows)) > q.pageSize {
+		rows = rows[:q.pageSize]
+		last := rows[len(rows)-1]
+		res.NextPageToken, err = encodePageToken(&pageCursor{
+			Sort:   q.sort,
+			Filter: q.filterDigest(),
+			ID:     last.ID,
+			Email:  last.Email,
+		})
+		if err != nil {
+			log.Printf("error: failed to encode page token. %s\n", err.Error())
+			return nil, errs.ErrInternalServer
+		}
+	}
+	for _, user := range rows {
+		summary := &pbusers.UserSummary{
+			Id:    user.ID.String(),
+			Email: user.Email,
+			Role:  convertDBRole(user.Role),
+		}
+		if user.CreatedAt.Valid {
+			summary.CreatedAt = timestamppb.New(user.CreatedAt.Time)
+		}
+		res.Users = append(res.Users, summary)
+	}
+	return res, nil
+}
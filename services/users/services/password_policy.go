@@ -0,0 +1,137 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/passwordpolicy"
+	v "github.com/nurfianqodar/school-microservices/services/users/utils/validation"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkPassword apply password policy to new password. When userID is
+// not nil, password is also compared against user password history.
+func (s *service) checkPassword(ctx context.Context, userID uuid.UUID, password, email string) error {
+	violations, err := s.pp.Check(password, email)
+	if err != nil {
+		log.Printf("error: failed to check password policy. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+
+	if userID != uuid.Nil && s.pp.History > 0 {
+		hashes, err := s.q.GetManyPasswordHistoryByUser(ctx, &db.GetManyPasswordHistoryByUserParams{
+			UserID: userID,
+			Limit:  int32(s.pp.History),
+		})
+		if err != nil {
+			log.Printf("error: failed to get password history. %s\n", err.Error())
+			return errs.ErrInternalServer
+		}
+		for _, hash := range hashes {
+			err := hasher.CompareHashWithPasswordContext(ctx, hash, password)
+			if err != nil {
+				// Only limiter or context error abort, other error is mismatch
+				if errors.Is(err, hasher.ErrSaturated) || ctx.Err() != nil {
+					return err
+				}
+				continue
+			}
+			violations = append(violations, passwordpolicy.Violation{Rule: passwordpolicy.RuleReused})
+			break
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return convertPasswordViolations(violations)
+}
+
+// recordPassword store new password hash in user password history and
+// drop entries older than policy history size. It is run within
+// transaction updating password so query error is returned as is.
+func (s *service) recordPassword(ctx context.Context, q db.Querier, userID uuid.UUID, passwordHash string) error {
+	if s.pp.History <= 0 {
+		return nil
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	err = q.CreateOnePasswordHistory(ctx, &db.CreateOnePasswordHistoryParams{
+		ID:           id,
+		UserID:       userID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = q.DeleteManyOldPasswordHistoryByUser(ctx, &db.DeleteManyOldPasswordHistoryByUserParams{
+		UserID: userID,
+		Keep:   int32(s.pp.History),
+	})
+	return err
+}
+
+// convertPasswordViolations convert policy violations to InvalidArgument
+// status with localized field violations
+func convertPasswordViolations(violations []passwordpolicy.Violation) error {
+	st := status.New(codes.InvalidArgument, "invalid input data")
+	fieldViolations := make([]*epb.BadRequest_FieldViolation, 0, len(violations))
+	for _, violation := range violations {
+		fieldViolations = append(fieldViolations, &epb.BadRequest_FieldViolation{
+			Field:       "Password",
+			Description: violation.Translate(v.Trans),
+			Reason:      string(violation.Rule),
+		})
+	}
+	ds, err := st.WithDetails(&epb.BadRequest{
+		FieldViolations: fieldViolations,
+	})
+	if err != nil {
+		log.Printf("error: failed to create error detail. %s", err.Error())
+		return errs.ErrInternalServer
+	}
+	return ds.Err()
+}
+
+// checkCurrentPassword compare password against current password of
+// user. Guessing password is throttled as failed login.
+func (s *service) checkCurrentPassword(ctx context.Context, userID uuid.UUID, email, password string) error {
+	if password == "" {
+		return status.Error(codes.InvalidArgument, "current password is required")
+	}
+	userThrottle := userLoginThrottle(userID.String())
+	if err := s.checkLoginThrottle(ctx, userThrottle); err != nil {
+		return err
+	}
+
+	creds, err := s.q.GetOneCredentialUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errUserNotFound
+		}
+		log.Printf("error: failed to get credential. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	if creds.ID != userID {
+		return errUserNotFound
+	}
+	if err := hasher.CompareHashWithPasswordContext(ctx, creds.PasswordHash, password); err != nil {
+		if errors.Is(err, errs.ErrInvalidCredential) {
+			if err := s.recordLoginFailure(ctx, userThrottle); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	return s.resetLoginThrottle(ctx, userThrottle)
+}
@@ -0,0 +1,185 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const passwordResetTokenTTL = time.Minute * 30
+
+var (
+	errInvalidResetToken = status.Error(codes.InvalidArgument, "invalid or expired reset token")
+)
+
+func (s *service) RequestPasswordReset(
+	ctx context.Context,
+	req *pbusers.RequestPasswordResetRequest,
+) (*pbusers.RequestPasswordResetResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Limit request by single client, every request is counted whether
+	// or not email is registered
+	ipThrottle := ipResetThrottle(s.clientIP(ctx))
+	if err := s.checkLoginThrottle(ctx, ipThrottle); err != nil {
+		return nil, err
+	}
+	if err := s.recordLoginFailure(ctx, ipThrottle); err != nil {
+		return nil, err
+	}
+
+	// Always success so email existence is not disclosed
+	res := &pbusers.RequestPasswordResetResponse{}
+
+	creds, err := s.q.GetOneCredentialUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return res, nil
+		}
+		log.Printf("error: failed to get credential. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	// Limit mail sent to single address silently, so other client can
+	// not lock owner out of password reset
+	allowed, err := s.allowThrottled(ctx, emailResetThrottle(req.Email))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return res, nil
+	}
+
+	secret, secretHash, err := newSecretToken()
+	if err != nil {
+		log.Printf("error: failed to generate reset token. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		log.Printf("error: failed to generate new uuid v7. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	_, err = s.q.CreateOnePasswordResetToken(ctx, &db.CreateOnePasswordResetTokenParams{
+		ID:        id,
+		UserID:    creds.ID,
+		TokenHash: secretHash,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(passwordResetTokenTTL), Valid: true},
+	})
+	if err != nil {
+		log.Printf("error: failed to insert reset token. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	body := fmt.Sprintf("Use this token to reset your password: %s", secret)
+	if url, ok := os.LookupEnv("PASSWORD_RESET_URL"); ok {
+		body = fmt.Sprintf("Open this link to reset your password: %s?token=%s", url, secret)
+	}
+	err = s.n.Notify(ctx, &notifier.Message{
+		To:      req.Email,
+		Subject: "Password reset",
+		Body:    body,
+	})
+	if err != nil {
+		log.Printf("error: failed to send reset token. %s\n", err.Error())
+	}
+
+	return res, nil
+}
+
+func (s *service) ConfirmPasswordReset(
+	ctx context.Context,
+	req *pbusers.ConfirmPasswordResetRequest,
+) (*pbusers.ConfirmPasswordResetResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Check password policy before consuming token so user can retry
+	// with other password
+	tokenHash := hashSecretToken(req.Token)
+	userID, err := s.q.GetOnePasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errInvalidResetToken
+		}
+		log.Printf("error: failed to get reset token. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	user, err := s.q.GetOneUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errInvalidResetToken
+		}
+		log.Printf("error: failed to get user. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	if err := s.checkPassword(ctx, userID, req.Password, user.Email); err != nil {
+		return nil, err
+	}
+
+	// Hash password
+	passwordHash, err := hasher.GenerateFromPasswordContext(ctx, req.Password, hasher.DefaultConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Consume reset token, update password and end every existing
+	// session atomically
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		userID, err = q.UseOnePasswordResetToken(ctx, tokenHash)
+		if err != nil {
+			return err
+		}
+		_, err = q.UpdateOnePasswordUser(ctx, &db.UpdateOnePasswordUserParams{
+			ID:           userID,
+			PasswordHash: passwordHash,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.recordPassword(ctx, q, userID, passwordHash); err != nil {
+			return err
+		}
+		if err := q.InvalidateManyPasswordResetTokenByUser(ctx, userID); err != nil {
+			return err
+		}
+		_, err = q.RevokeAllSessionByUser(ctx, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errInvalidResetToken
+		}
+		return nil, dbError(err, "reset user password")
+	}
+	// Failure is already logged, password is reset anyway
+	_ = s.resetLoginThrottle(ctx, emailResetThrottle(user.Email))
+	if err := token.RevokeSubject(ctx, userID.String()); err != nil {
+		log.Printf("error: failed to revoke user tokens. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	return &pbusers.ConfirmPasswordResetResponse{
+		Id: userID.String(),
+	}, nil
+}
@@ -0,0 +1,140 @@
+package svc
+
+import (
+	"context"
+	"html"
+	"log"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errEmptySearchQuery = status.Error(codes.InvalidArgument, "search query must contain letter or digit")
+
+// searchTerms split query into lower cased words. Only letter and digit
+// are kept so terms are safe to be placed inside tsquery.
+func searchTerms(query string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return slices.Compact(terms)
+}
+
+// wordQuery build tsquery matching email having word prefixed by every
+// term, e.g. "john doe" become "john:* & doe:*"
+func wordQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term+":*")
+	}
+	return strings.Join(parts, " & ")
+}
+
+// highlight escape email for HTML and wrap every case insensitive
+// occurrence of terms in <mark> tag. Overlapping occurrences are merged.
+func highlight(email string, terms []string) string {
+	lower := strings.ToLower(email)
+	if len(lower) != len(email) {
+		// Byte offset of lower cased email can not be mapped back
+		return html.EscapeString(email)
+	}
+
+	marked := make([]bool, len(email))
+	for _, term := range terms {
+		for start := 0; ; {
+			i := strings.Index(lower[start:], term)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(term); j++ {
+				marked[j] = true
+			}
+			start += i + 1
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(email); {
+		j := i
+		for j < len(email) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString("<mark>" + html.EscapeString(email[i:j]) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(email[i:j]))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func (s *service) SearchUsers(
+	ctx context.Context,
+	req *pbusers.SearchUsersRequest,
+) (*pbusers.SearchUsersResponse, error) {
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(req.Query)
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, errEmptySearchQuery
+	}
+
+	roles := make([]string, 0, len(req.Roles))
+	for _, r := range req.Roles {
+		role, err := convertRole(r)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(roles, string(role)) {
+			roles = append(roles, string(role))
+		}
+	}
+
+	pageSize := int32(defaultPageSize)
+	if req.PageSize > 0 {
+		pageSize = int32(min(req.PageSize, maxPageSize))
+	}
+
+	rows, err := s.q.SearchUser(ctx, &db.SearchUserParams{
+		WordQuery:       wordQuery(terms),
+		Query:           query,
+		Roles:           roles,
+		ContainsPattern: "%" + likeEscaper.Replace(query) + "%",
+		PageSize:        pageSize,
+	})
+	if err != nil {
+		log.Printf("error: failed to search users. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	res := &pbusers.SearchUsersResponse{
+		Results: make([]*pbusers.UserSearchResult, 0, len(rows)),
+	}
+	for _, row := range rows {
+		summary := &pbusers.UserSummary{
+			Id:    row.ID.String(),
+			Email: row.Email,
+			Role:  convertDBRole(row.Role),
+		}
+		if row.CreatedAt.Valid {
+			summary.CreatedAt = timestamppb.New(row.CreatedAt.Time)
+		}
+		res.Results = append(res.Results, &pbusers.UserSearchResult{
+			User:      summary,
+			Rank:      row.Rank,
+			Highlight: highlight(row.Email, terms),
+		})
+	}
+	return res, nil
+}
@@ -2,33 +2,58 @@ package svc
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/netip"
+	"slices"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/nurfianqodar/school-microservices/services/users/db"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/passwordpolicy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
 	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
-	v "github.com/nurfianqodar/school-microservices/services/users/utils/validation"
 	"github.com/nurfianqodar/school-microservices/utils/errs"
 	"github.com/nurfianqodar/school-microservices/utils/hasher"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	accessTokenTTL  = time.Minute * 30
+	refreshTokenTTL = time.Hour * 24 * 30
 )
 
 var (
-	errUserNotFound = status.Error(codes.NotFound, "user not found")
+	errUserNotFound     = status.Error(codes.NotFound, "user not found")
+	errInvalidTokenType = status.Error(codes.Unauthenticated, "invalid token type")
+	errTokenExpired     = status.Error(codes.Unauthenticated, "token expired")
+	errEmailExists      = status.Error(codes.AlreadyExists, "email already exist")
 )
 
 type service struct {
 	pbusers.UnimplementedUserServiceServer
-	q *db.Queries
+	q  db.Querier
+	tx database.TxRunner
+	n  notifier.Notifier
+	pp *passwordpolicy.Policy
+	// gateway addresses allowed to forward client address
+	proxies []netip.Prefix
 }
 
-func New(q *db.Queries) pbusers.UserServiceServer {
+func New(q db.Querier, tx database.TxRunner, n notifier.Notifier, pp *passwordpolicy.Policy) pbusers.UserServiceServer {
 	return &service{
-		q: q,
+		q:  q,
+		tx: tx,
+		n:  n,
+		pp: pp,
+
+		proxies: trustedProxies(),
 	}
 }
 
@@ -37,27 +62,17 @@ func (s *service) CreateOneUser(
 	req *pbusers.CreateOneUserRequest,
 ) (*pbusers.CreateOneUserResponse, error) {
 	// Validate request
-	if err := v.Validate.Struct(req); err != nil {
-		if validationErrs, ok := err.(validator.ValidationErrors); ok {
-			return nil, errs.ConvertValidationError(validationErrs, v.Trans)
-		} else {
-			log.Printf("error: failed to validate data. %s", err.Error())
-			return nil, errs.ErrInternalServer
-		}
+	if err := validateRequest(req); err != nil {
+		return nil, err
 	}
 
-	// Check email avaliable
-	countEmail, err := s.q.CountEmailUser(ctx, req.Email)
-	if err != nil {
-		log.Printf("error: failed to count email. %s", err.Error())
-		return nil, errs.ErrInternalServer
-	}
-	if countEmail != 0 {
-		return nil, status.Error(codes.AlreadyExists, "email already exist")
+	// Check password policy
+	if err := s.checkPassword(ctx, uuid.Nil, req.Password, req.Email); err != nil {
+		return nil, err
 	}
 
 	// Hash password
-	passwordHash, err := hasher.GenerateFromPassword(req.Password, hasher.DefaultConfig)
+	passwordHash, err := hasher.GenerateFromPasswordContext(ctx, req.Password, hasher.DefaultConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -84,13 +99,32 @@ func (s *service) CreateOneUser(
 		Role:         role,
 	}
 
-	// -- execute query
-	result, err := s.q.CreateOneUser(ctx, dbArgs)
+	// -- insert user along with its email verification token atomically,
+	// email availability is enforced by unique constraint
+	var result uuid.UUID
+	var verificationToken string
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		var err error
+		result, err = q.CreateOneUser(ctx, dbArgs)
+		if err != nil {
+			return err
+		}
+		if err := s.recordPassword(ctx, q, result, passwordHash); err != nil {
+			return err
+		}
+		verificationToken, err = createEmailVerification(ctx, q, result, req.Email)
+		return err
+	})
 	if err != nil {
-		log.Printf("error: failed to insert new user. %s\n", err.Error())
-		return nil, errs.ErrInternalServer
+		if database.IsUniqueViolation(err) {
+			return nil, errEmailExists
+		}
+		return nil, dbError(err, "insert new user")
 	}
 
+	// Send email verification
+	s.sendEmailVerification(ctx, req.Email, verificationToken)
+
 	return &pbusers.CreateOneUserResponse{
 		Id: result.String(),
 	}, nil
@@ -101,24 +135,25 @@ func (s *service) DeleteHardOneUser(
 	req *pbusers.DeleteHardOneUserRequest,
 ) (*pbusers.DeleteHardOneUserResponse, error) {
 	// count user by id
-	reqUUID, err := uuid.Parse(req.Id)
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
-	}
-
-	count, err := s.q.CountIDUser(ctx, reqUUID)
+	reqUUID, err := parseID(req.Id)
 	if err != nil {
-		log.Printf("error: failed to count user by id: %e\n", err)
-		return nil, errs.ErrInternalServer
-	}
-	if count == 0 {
-		return nil, errUserNotFound
+		return nil, err
 	}
 
-	deletedID, err := s.q.DeleteHardOneUser(ctx, reqUUID)
+	var deletedID uuid.UUID
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		count, err := q.CountIDUser(ctx, reqUUID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errUserNotFound
+		}
+		deletedID, err = q.DeleteHardOneUser(ctx, reqUUID)
+		return err
+	})
 	if err != nil {
-		log.Printf("error: failed to hard delete user by id: %e\n", err)
-		return nil, errs.ErrInternalServer
+		return nil, dbError(err, "hard delete user by id")
 	}
 
 	return &pbusers.DeleteHardOneUserResponse{
@@ -130,47 +165,27 @@ func (s *service) DeleteSoftOneUser(
 	ctx context.Context,
 	req *pbusers.DeleteSoftOneUserRequest,
 ) (*pbusers.DeleteSoftOneUserResponse, error) {
-	panic("not implemented")
-}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *service) GetManyUser(
-	ctx context.Context,
-	req *pbusers.GetManyUserRequest,
-) (*pbusers.GetManyUserResponse, error) {
-	res, err := s.q.GetManyUser(ctx, &db.GetManyUserParams{
-		Limit:  int32(req.Limit),
-		Offset: int32(req.Offset),
+	// Revoke issued tokens along with delete so deleted user lose access
+	// immediately
+	var deletedID uuid.UUID
+	err = s.runTxRevokingUser(ctx, reqUUID, func(q db.Querier) error {
+		deletedID, err = q.DeleteSoftOneUser(ctx, reqUUID)
+		return err
 	})
 	if err != nil {
-		return nil, errs.ErrInternalServer
-	}
-	users := make([]*pbusers.UserSummary, 0, len(res))
-	log.Printf("found %d users\n", len(users))
-	for _, user := range res {
-		var pbRole pbusers.UserRole
-
-		switch user.Role {
-		case db.UserRoleParent:
-			pbRole = pbusers.UserRole_Parent
-		case db.UserRoleStaff:
-			pbRole = pbusers.UserRole_Staff
-		case db.UserRoleStudent:
-			pbRole = pbusers.UserRole_Student
-		case db.UserRoleTeacher:
-			pbRole = pbusers.UserRole_Teacher
-		default:
-			pbRole = pbusers.UserRole_Unspecified
-		}
-
-		users = append(users, &pbusers.UserSummary{
-			Id:    user.ID.String(),
-			Email: user.Email,
-			Role:  pbRole,
-		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		return nil, dbError(err, "soft delete user by id")
 	}
 
-	return &pbusers.GetManyUserResponse{
-		Users: users,
+	return &pbusers.DeleteSoftOneUserResponse{
+		Id: deletedID.String(),
 	}, nil
 }
 
@@ -178,70 +193,308 @@ func (s *service) GetOneCredentialUserByEmail(
 	ctx context.Context,
 	req *pbusers.GetOneCredentialUserByEmailRequest,
 ) (*pbusers.GetOneCredentialUserByEmailResponse, error) {
-	panic("not implemented")
+	creds, err := s.q.GetOneCredentialUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		log.Printf("error: failed to get credential. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	return &pbusers.GetOneCredentialUserByEmailResponse{
+		Id:           creds.ID.String(),
+		PasswordHash: creds.PasswordHash,
+	}, nil
 }
 
 func (s *service) GetOneUser(
 	ctx context.Context,
 	req *pbusers.GetOneUserRequest,
 ) (*pbusers.GetOneUserResponse, error) {
-	panic("not implemented")
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.q.GetOneUser(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		log.Printf("error: failed to get user by id. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	res := &pbusers.GetOneUserResponse{
+		Id:        user.ID.String(),
+		Email:     user.Email,
+		Role:      string(user.Role),
+		CreatedAt: user.CreatedAt.Time.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Time.Format(time.RFC3339),
+	}
+	if user.EmailVerifiedAt.Valid {
+		res.EmailVerifiedAt = user.EmailVerifiedAt.Time.Format(time.RFC3339)
+	}
+	return res, nil
 }
 
 func (s *service) UpdateOneEmailUser(
 	ctx context.Context,
 	req *pbusers.UpdateOneEmailUserRequest,
 ) (*pbusers.UpdateOneEmailUserResponse, error) {
-	panic("not implemented")
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unchanged email is no-op so it is neither reported as taken nor
+	// unverified again
+	user, err := s.q.GetOneUser(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		log.Printf("error: failed to get user. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	if user.Email == req.Email {
+		return &pbusers.UpdateOneEmailUserResponse{
+			Id: reqUUID.String(),
+		}, nil
+	}
+
+	// Email availability is enforced by unique constraint. New email
+	// must be verified again, its token is stored along with the change.
+	var updatedID uuid.UUID
+	var verificationToken string
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		updatedID, err = q.UpdateOneEmailUser(ctx, &db.UpdateOneEmailUserParams{
+			ID:    reqUUID,
+			Email: req.Email,
+		})
+		if err != nil {
+			return err
+		}
+		verificationToken, err = createEmailVerification(ctx, q, updatedID, req.Email)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		if database.IsUniqueViolation(err) {
+			return nil, errEmailExists
+		}
+		return nil, dbError(err, "update user email")
+	}
+	s.sendEmailVerification(ctx, req.Email, verificationToken)
+
+	return &pbusers.UpdateOneEmailUserResponse{
+		Id: updatedID.String(),
+	}, nil
 }
 
 func (s *service) UpdateOnePasswordUser(
 	ctx context.Context,
 	req *pbusers.UpdateOnePasswordUserRequest,
 ) (*pbusers.UpdateOnePasswordUserResponse, error) {
-	panic("not implemented")
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check password policy and history
+	user, err := s.q.GetOneUser(ctx, reqUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		log.Printf("error: failed to get user. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	if err := s.checkPassword(ctx, reqUUID, req.Password, user.Email); err != nil {
+		return nil, err
+	}
+
+	// Access token alone is not enough to take over account, only staff
+	// may set password without knowing current one
+	if role, _ := policy.Role(ctx); role != db.UserRoleStaff {
+		if err := s.checkCurrentPassword(ctx, reqUUID, user.Email, req.CurrentPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	// Hash password
+	passwordHash, err := hasher.GenerateFromPasswordContext(ctx, req.Password, hasher.DefaultConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedID uuid.UUID
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		updatedID, err = q.UpdateOnePasswordUser(ctx, &db.UpdateOnePasswordUserParams{
+			ID:           reqUUID,
+			PasswordHash: passwordHash,
+		})
+		if err != nil {
+			return err
+		}
+		return s.recordPassword(ctx, q, reqUUID, passwordHash)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		return nil, dbError(err, "update user password")
+	}
+
+	// Revoke issued tokens so sessions made with old password end immediately
+	if err := token.RevokeSubject(ctx, reqUUID.String()); err != nil {
+		log.Printf("error: failed to revoke user tokens. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	return &pbusers.UpdateOnePasswordUserResponse{
+		Id: updatedID.String(),
+	}, nil
 }
 
 func (s *service) UpdateOneRoleUser(
 	ctx context.Context,
 	req *pbusers.UpdateOneRoleUserRequest,
 ) (*pbusers.UpdateOneRoleUserResponse, error) {
-	panic("not implemented")
+	// Validate request
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	reqUUID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	role, err := convertRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoke issued tokens along with update so new role take effect
+	// immediately
+	var updatedID uuid.UUID
+	err = s.runTxRevokingUser(ctx, reqUUID, func(q db.Querier) error {
+		updatedID, err = q.UpdateOneRoleUser(ctx, &db.UpdateOneRoleUserParams{
+			ID:   reqUUID,
+			Role: role,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		return nil, dbError(err, "update user role")
+	}
+
+	return &pbusers.UpdateOneRoleUserResponse{
+		Id: updatedID.String(),
+	}, nil
 }
 
 func (s *service) LoginUser(
 	ctx context.Context,
 	req *pbusers.LoginUserRequest,
 ) (*pbusers.LoginUserResponse, error) {
-	// Count email
-	count, err := s.q.CountEmailUser(ctx, req.Email)
-	if err != nil {
-		log.Printf("error: failed to count user by email. %s", err.Error())
-		return nil, errs.ErrInternalServer
+	// Refuse client locked by previous failed attempts
+	ipThrottle := ipLoginThrottle(s.clientIP(ctx))
+	if err := s.checkLoginThrottle(ctx, ipThrottle); err != nil {
+		return nil, err
+	}
+
+	// Resolve client audience
+	audiences := token.Audiences()
+	audience := req.ClientId
+	if audience == "" {
+		audience = audiences[0]
 	}
-	if count != 1 {
-		return nil, errs.ErrInvalidCredential
+	if !slices.Contains(audiences, audience) {
+		return nil, status.Error(codes.InvalidArgument, "unknown client id")
 	}
 
 	// Get credential
 	creds, err := s.q.GetOneCredentialUserByEmail(ctx, req.Email)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err := s.recordLoginFailure(ctx, ipThrottle); err != nil {
+				return nil, err
+			}
+			return nil, errs.ErrInvalidCredential
+		}
 		log.Printf("error: failed to get credential. %s", err.Error())
 		return nil, errs.ErrInternalServer
 	}
 
+	// Refuse account locked by previous failed attempts before hashing
+	userThrottle := userLoginThrottle(creds.ID.String())
+	if err := s.checkLoginThrottle(ctx, userThrottle); err != nil {
+		return nil, err
+	}
+
 	// Compare password and hash
-	if err = hasher.CompareHashWithPassword(creds.PasswordHash, req.Password); err != nil {
+	if err = hasher.CompareHashWithPasswordContext(ctx, creds.PasswordHash, req.Password); err != nil {
+		if errors.Is(err, errs.ErrInvalidCredential) {
+			for _, t := range []loginThrottle{userThrottle, ipThrottle} {
+				if err := s.recordLoginFailure(ctx, t); err != nil {
+					return nil, err
+				}
+			}
+		}
 		return nil, err
 	}
+	// Forget failed attempt of account and client, so client sharing
+	// address with user mistyping password is not locked out
+	for _, t := range []loginThrottle{userThrottle, ipThrottle} {
+		if err := s.resetLoginThrottle(ctx, t); err != nil {
+			return nil, err
+		}
+	}
+
+	// Upgrade hash generated with outdated parameters
+	s.rehashPassword(ctx, creds.ID, creds.PasswordHash, req.Password)
+
+	// Refuse unverified email when required
+	if !creds.EmailVerifiedAt.Valid && requireVerifiedEmail() {
+		return nil, errEmailNotVerified
+	}
 
-	// Create access and refresh token
-	// TODO fix audience
-	accessToken, err := token.CreateToken(token.TokenTypeAccess, creds.ID.String(), time.Minute*30, []string{"null"})
+	// Challenge second factor when enrolled, refuse when role require
+	// it but user has not enrolled yet
+	challenge, err := s.loginChallenge(ctx, creds, audience)
 	if err != nil {
 		return nil, err
 	}
-	refreshToken, err := token.CreateToken(token.TokenTypeRefresh, creds.ID.String(), time.Hour*24*30, []string{"null"})
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	// Create access and refresh token sharing new session
+	sessionID, err := uuid.NewV7()
+	if err != nil {
+		log.Printf("error: failed to generate new uuid v7. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	sub := token.Subject{
+		ID:        creds.ID.String(),
+		Role:      string(creds.Role),
+		SessionID: sessionID.String(),
+	}
+	accessToken, refreshToken, err := issueTokens(ctx, s.q, sub, []string{audience})
 	if err != nil {
 		return nil, err
 	}
@@ -251,3 +504,128 @@ func (s *service) LoginUser(
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+// rehashPassword store password hashed with current parameters when
+// oldHash is outdated. Failure is only logged since user already
+// authenticated.
+func (s *service) rehashPassword(ctx context.Context, id uuid.UUID, oldHash, password string) {
+	needs, err := hasher.NeedsRehash(oldHash, hasher.DefaultConfig)
+	if err != nil || !needs {
+		return
+	}
+	newHash, err := hasher.GenerateFromPasswordContext(ctx, password, hasher.DefaultConfig)
+	if err != nil {
+		log.Printf("error: failed to rehash password. %s\n", err.Error())
+		return
+	}
+	_, err = s.q.RehashOnePasswordUser(ctx, &db.RehashOnePasswordUserParams{
+		NewPasswordHash: newHash,
+		ID:              id,
+		OldPasswordHash: oldHash,
+	})
+	if err != nil {
+		log.Printf("error: failed to store rehashed password. %s\n", err.Error())
+	}
+}
+
+func (s *service) RefreshTokenUser(
+	ctx context.Context,
+	req *pbusers.RefreshTokenUserRequest,
+) (*pbusers.RefreshTokenUserResponse, error) {
+	claims, err := token.VerifyToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.GetTokenType() != token.TokenTypeRefresh {
+		return nil, errInvalidTokenType
+	}
+	if !claims.Exp.After(time.Now()) {
+		return nil, errTokenExpired
+	}
+
+	// Make sure user still exists
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	user, err := s.q.GetOneUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		log.Printf("error: failed to get user by id. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	// Rotate refresh token, only the latest token of session is accepted.
+	// Tokens are issued with current role of user.
+	sub := token.Subject{
+		ID:        claims.Sub,
+		Role:      string(user.Role),
+		SessionID: claims.Sid,
+	}
+	accessToken, refreshToken, err := s.rotateSession(ctx, claims, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pbusers.RefreshTokenUserResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *service) VerifyTokenUser(
+	ctx context.Context,
+	req *pbusers.VerifyTokenUserRequest,
+) (*pbusers.VerifyTokenUserResponse, error) {
+	claims, err := token.VerifyToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.GetTokenType() != token.TokenTypeAccess {
+		return nil, errInvalidTokenType
+	}
+	if !claims.Exp.After(time.Now()) {
+		return nil, errTokenExpired
+	}
+	if req.Audience != "" && !slices.Contains(claims.Aud, req.Audience) {
+		return nil, status.Error(codes.Unauthenticated, "invalid token audience")
+	}
+
+	return &pbusers.VerifyTokenUserResponse{
+		Exp:  timestamppb.New(claims.Exp.Time),
+		Iat:  timestamppb.New(claims.Iat.Time),
+		Nbf:  timestamppb.New(claims.Nbf.Time),
+		Iss:  claims.Iss,
+		Sub:  claims.Sub,
+		Aud:  claims.Aud,
+		Role: claims.Role,
+		Jti:  claims.Jti,
+		Sid:  claims.Sid,
+	}, nil
+}
+
+func (s *service) GetJwksUser(
+	ctx context.Context,
+	req *pbusers.GetJwksUserRequest,
+) (*pbusers.GetJwksUserResponse, error) {
+	publicKeys := token.PublicKeys()
+	keys := make([]*pbusers.Jwk, 0, len(publicKeys))
+	for _, k := range publicKeys {
+		keys = append(keys, &pbusers.Jwk{
+			Kty: k.Kty,
+			Kid: k.Kid,
+			Alg: k.Alg,
+			Use: k.Use,
+			N:   k.N,
+			E:   k.E,
+			Crv: k.Crv,
+			X:   k.X,
+		})
+	}
+
+	return &pbusers.GetJwksUserResponse{
+		Keys: keys,
+	}, nil
+}
@@ -0,0 +1,1191 @@
+package svc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	svc "github.com/nurfianqodar/school-microservices/services/users/services"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/memdb"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/passwordpolicy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token/revocation"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/totp"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const password = "secretpassword"
+
+func TestMain(m *testing.M) {
+	os.Setenv("SECRET", "unit-test-secret")
+	os.Unsetenv("AUDIENCES")
+	os.Unsetenv("REQUIRE_VERIFIED_EMAIL")
+	// Cheap parameters keep hashing fast in unit test
+	hasher.DefaultConfig = &hasher.Config{
+		Memory:      1024,
+		Iterations:  1,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+	os.Exit(m.Run())
+}
+
+// outbox is notifier which keep every message sent by service
+type outbox struct {
+	mu       sync.Mutex
+	messages []*notifier.Message
+}
+
+func (o *outbox) Notify(ctx context.Context, m *notifier.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, m)
+	return nil
+}
+
+// lastToken return token inside latest message sent to email with
+// subject or empty string when none was sent
+func (o *outbox) lastToken(email, subject string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		m := o.messages[i]
+		if m.To == email && m.Subject == subject {
+			fields := strings.Fields(m.Body)
+			return fields[len(fields)-1]
+		}
+	}
+	return ""
+}
+
+// count return number of messages sent to email with subject
+func (o *outbox) count(email, subject string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := 0
+	for _, m := range o.messages {
+		if m.To == email && m.Subject == subject {
+			n++
+		}
+	}
+	return n
+}
+
+type harness struct {
+	client pbusers.UserServiceClient
+	store  *memdb.Store
+	outbox *outbox
+}
+
+// newHarness serve users service backed by in-memory store through
+// bufconn listener
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	return newHarnessWithTx(t, func(store *memdb.Store) database.TxRunner { return store })
+}
+
+// newHarnessWithTx start service whose transactions are run by runner
+// returned from tx
+func newHarnessWithTx(t *testing.T, tx func(store *memdb.Store) database.TxRunner) *harness {
+	t.Helper()
+	store := memdb.New()
+	o := &outbox{}
+	token.SetRevocationStore(revocation.NewPostgres(store))
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(policy.UnaryServerInterceptor()))
+	pbusers.RegisterUserServiceServer(server, svc.New(store, tx(store), o, passwordpolicy.Default()))
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		token.SetRevocationStore(nil)
+	})
+
+	return &harness{
+		client: pbusers.NewUserServiceClient(conn),
+		store:  store,
+		outbox: o,
+	}
+}
+
+// authContext return context carrying access token of user id with role
+func authContext(t *testing.T, id string, role pbusers.UserRole) context.Context {
+	t.Helper()
+	sub := token.Subject{
+		ID:        id,
+		Role:      strings.ToLower(role.String()),
+		SessionID: uuid.NewString(),
+	}
+	accessToken, _, err := token.CreateToken(token.TokenTypeAccess, sub, time.Hour, token.Audiences())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bearerContext(accessToken)
+}
+
+func bearerContext(accessToken string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+accessToken)
+}
+
+// staffContext return context of staff which does not exist in store
+func staffContext(t *testing.T) context.Context {
+	return authContext(t, uuid.NewString(), pbusers.UserRole_Staff)
+}
+
+// createUser create user with role and random email and return its id
+// and email
+func (h *harness) createUser(t *testing.T, role pbusers.UserRole) (string, string) {
+	t.Helper()
+	email := "user" + uuid.NewString() + "@email.com"
+	return h.createUserWithEmail(t, role, email), email
+}
+
+func (h *harness) createUserWithEmail(t *testing.T, role pbusers.UserRole, email string) string {
+	t.Helper()
+	res, err := h.client.CreateOneUser(staffContext(t), &pbusers.CreateOneUserRequest{
+		Email:    email,
+		Password: password,
+		Role:     role,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Id
+}
+
+// login return tokens of user with email
+func (h *harness) login(t *testing.T, email string) *pbusers.LoginUserResponse {
+	t.Helper()
+	res, err := h.client.LoginUser(context.Background(), &pbusers.LoginUserRequest{
+		Email:    email,
+		Password: password,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+type testCase[Req any] struct {
+	name string
+	ctx  context.Context
+	req  Req
+	code codes.Code
+}
+
+// runCases invoke call with every case in order and compare returned
+// status code
+func runCases[Req, Res any](
+	t *testing.T,
+	call func(context.Context, Req, ...grpc.CallOption) (Res, error),
+	tests []testCase[Req],
+) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			_, err := call(ctx, tt.req)
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("expected code %s, got %s (%v)", tt.code, got, err)
+			}
+		})
+	}
+}
+
+func TestCreateOneUser(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+	_, taken := h.createUser(t, pbusers.UserRole_Teacher)
+	deletedID, deleted := h.createUser(t, pbusers.UserRole_Teacher)
+	if _, err := h.client.DeleteSoftOneUser(staff, &pbusers.DeleteSoftOneUserRequest{Id: deletedID}); err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(email, password string, role pbusers.UserRole) *pbusers.CreateOneUserRequest {
+		return &pbusers.CreateOneUserRequest{Email: email, Password: password, Role: role}
+	}
+	runCases(t, h.client.CreateOneUser, []testCase[*pbusers.CreateOneUserRequest]{
+		{"Should create user", staff, newRequest("new@email.com", password, pbusers.UserRole_Student), codes.OK},
+		{"Should refuse taken email", staff, newRequest(taken, password, pbusers.UserRole_Student), codes.AlreadyExists},
+		{"Should reuse email of soft deleted user", staff, newRequest(deleted, password, pbusers.UserRole_Student), codes.OK},
+		{"Should refuse invalid email", staff, newRequest("invalid", password, pbusers.UserRole_Student), codes.InvalidArgument},
+		{"Should refuse short password", staff, newRequest("short@email.com", "short", pbusers.UserRole_Student), codes.InvalidArgument},
+		{"Should refuse unspecified role", staff, newRequest("role@email.com", password, pbusers.UserRole_Unspecified), codes.InvalidArgument},
+		{"Should refuse non staff", authContext(t, uuid.NewString(), pbusers.UserRole_Teacher), newRequest("teacher@email.com", password, pbusers.UserRole_Student), codes.PermissionDenied},
+		{"Should refuse anonymous", nil, newRequest("anonymous@email.com", password, pbusers.UserRole_Student), codes.Unauthenticated},
+	})
+}
+
+func TestCreateOneUserRollback(t *testing.T) {
+	h, tx := newFailingHarness(t)
+	staff := staffContext(t)
+	req := &pbusers.CreateOneUserRequest{Email: "new@email.com", Password: password, Role: pbusers.UserRole_Student}
+
+	tx.fail = true
+	runCases(t, h.client.CreateOneUser, []testCase[*pbusers.CreateOneUserRequest]{
+		{"Should fail when verification token can not be stored", staff, req, codes.Internal},
+	})
+	tx.fail = false
+	runCases(t, h.client.CreateOneUser, []testCase[*pbusers.CreateOneUserRequest]{
+		{"Should create user on retry", staff, req, codes.OK},
+	})
+	if n := h.outbox.count(req.Email, "Email verification"); n != 1 {
+		t.Fatalf("expected single verification mail, got %d", n)
+	}
+}
+
+func TestGetOneUser(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	deletedID, _ := h.createUser(t, pbusers.UserRole_Student)
+	if _, err := h.client.DeleteSoftOneUser(staff, &pbusers.DeleteSoftOneUserRequest{Id: deletedID}); err != nil {
+		t.Fatal(err)
+	}
+
+	runCases(t, h.client.GetOneUser, []testCase[*pbusers.GetOneUserRequest]{
+		{"Should get user as staff", staff, &pbusers.GetOneUserRequest{Id: id}, codes.OK},
+		{"Should get user as self", authContext(t, id, pbusers.UserRole_Student), &pbusers.GetOneUserRequest{Id: id}, codes.OK},
+		{"Should refuse other student", authContext(t, uuid.NewString(), pbusers.UserRole_Student), &pbusers.GetOneUserRequest{Id: id}, codes.PermissionDenied},
+		{"Should hide soft deleted user", staff, &pbusers.GetOneUserRequest{Id: deletedID}, codes.NotFound},
+		{"Should return not found", staff, &pbusers.GetOneUserRequest{Id: uuid.NewString()}, codes.NotFound},
+		{"Should refuse invalid id", staff, &pbusers.GetOneUserRequest{Id: "invalid"}, codes.InvalidArgument},
+	})
+}
+
+func TestGetOneCredentialUserByEmail(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+
+	runCases(t, h.client.GetOneCredentialUserByEmail, []testCase[*pbusers.GetOneCredentialUserByEmailRequest]{
+		{"Should never expose credential to staff", staffContext(t), &pbusers.GetOneCredentialUserByEmailRequest{Email: email}, codes.PermissionDenied},
+		{"Should refuse anonymous", nil, &pbusers.GetOneCredentialUserByEmailRequest{Email: email}, codes.Unauthenticated},
+	})
+}
+
+func TestGetManyUser(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+	h.createUser(t, pbusers.UserRole_Student)
+	deletedID, _ := h.createUser(t, pbusers.UserRole_Student)
+	if _, err := h.client.DeleteSoftOneUser(staff, &pbusers.DeleteSoftOneUserRequest{Id: deletedID}); err != nil {
+		t.Fatal(err)
+	}
+
+	runCases(t, h.client.GetManyUser, []testCase[*pbusers.GetManyUserRequest]{
+		{"Should list users as staff", staff, &pbusers.GetManyUserRequest{PageSize: 10}, codes.OK},
+		{"Should list users as teacher", authContext(t, uuid.NewString(), pbusers.UserRole_Teacher), &pbusers.GetManyUserRequest{PageSize: 10}, codes.OK},
+		{"Should refuse student", authContext(t, uuid.NewString(), pbusers.UserRole_Student), &pbusers.GetManyUserRequest{PageSize: 10}, codes.PermissionDenied},
+		{"Should refuse malformed page token", staff, &pbusers.GetManyUserRequest{PageToken: "invalid"}, codes.InvalidArgument},
+		{"Should refuse unknown sort", staff, &pbusers.GetManyUserRequest{Sort: pbusers.UserSort(99)}, codes.InvalidArgument},
+		{"Should refuse empty created range", staff, &pbusers.GetManyUserRequest{
+			CreatedAfter:  timestamppb.New(time.Now()),
+			CreatedBefore: timestamppb.New(time.Now().Add(-time.Hour)),
+		}, codes.InvalidArgument},
+	})
+
+	res, err := h.client.GetManyUser(staff, &pbusers.GetManyUserRequest{PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Users) != 1 {
+		t.Fatalf("expected 1 active user, got %d", len(res.Users))
+	}
+}
+
+// listAll page through GetManyUser and return email of every user along
+// with total count reported by first page
+func listAll(t *testing.T, h *harness, ctx context.Context, req *pbusers.GetManyUserRequest) ([]string, uint64) {
+	t.Helper()
+	var (
+		emails []string
+		total  uint64
+	)
+	for page := 0; ; page++ {
+		res, err := h.client.GetManyUser(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page == 0 {
+			total = res.TotalCount
+		}
+		if uint32(len(res.Users)) > req.PageSize {
+			t.Fatalf("page contain %d users, page size is %d", len(res.Users), req.PageSize)
+		}
+		for _, u := range res.Users {
+			emails = append(emails, u.Email)
+		}
+		if res.NextPageToken == "" {
+			return emails, total
+		}
+		req.PageToken = res.NextPageToken
+	}
+}
+
+func TestGetManyUserPagination(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+	// Created in this order so created sort differ from email sort
+	created := []string{"c@email.com", "a@email.com", "e_x@email.com", "b@email.com", "d@email.com", "ex@email.com"}
+	for i, email := range created {
+		role := pbusers.UserRole_Student
+		if i%2 == 1 {
+			role = pbusers.UserRole_Teacher
+		}
+		h.createUserWithEmail(t, role, email)
+	}
+
+	tests := []struct {
+		name string
+		req  *pbusers.GetManyUserRequest
+		want []string
+	}{
+		{"Should sort by creation", &pbusers.GetManyUserRequest{}, created},
+		{"Should sort by newest", &pbusers.GetManyUserRequest{Sort: pbusers.UserSort_CreatedDesc}, []string{"ex@email.com", "d@email.com", "b@email.com", "e_x@email.com", "a@email.com", "c@email.com"}},
+		{"Should sort by email", &pbusers.GetManyUserRequest{Sort: pbusers.UserSort_EmailAsc}, []string{"a@email.com", "b@email.com", "c@email.com", "d@email.com", "e_x@email.com", "ex@email.com"}},
+		{"Should sort by email descending", &pbusers.GetManyUserRequest{Sort: pbusers.UserSort_EmailDesc}, []string{"ex@email.com", "e_x@email.com", "d@email.com", "c@email.com", "b@email.com", "a@email.com"}},
+		{"Should filter by role", &pbusers.GetManyUserRequest{Role: pbusers.UserRole_Teacher}, []string{"a@email.com", "b@email.com", "ex@email.com"}},
+		{"Should filter by literal email prefix", &pbusers.GetManyUserRequest{EmailPrefix: "e_"}, []string{"e_x@email.com"}},
+		{"Should filter by created range", &pbusers.GetManyUserRequest{CreatedBefore: timestamppb.New(time.Now().Add(-time.Hour))}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.PageSize = 2
+			got, total := listAll(t, h, staff, tt.req)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			if total != uint64(len(tt.want)) {
+				t.Fatalf("expected total %d, got %d", len(tt.want), total)
+			}
+		})
+	}
+
+	t.Run("Should refuse page token with other filter", func(t *testing.T) {
+		res, err := h.client.GetManyUser(staff, &pbusers.GetManyUserRequest{PageSize: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = h.client.GetManyUser(staff, &pbusers.GetManyUserRequest{
+			PageSize:  2,
+			PageToken: res.NextPageToken,
+			Role:      pbusers.UserRole_Teacher,
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected code %s, got %v", codes.InvalidArgument, err)
+		}
+	})
+
+	t.Run("Should cap page size", func(t *testing.T) {
+		for range 100 {
+			h.createUser(t, pbusers.UserRole_Student)
+		}
+		res, err := h.client.GetManyUser(staff, &pbusers.GetManyUserRequest{PageSize: 1000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Users) != 100 || res.NextPageToken == "" {
+			t.Fatalf("expected capped page with next token, got %d users", len(res.Users))
+		}
+	})
+}
+
+func TestSearchUsers(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+	h.createUserWithEmail(t, pbusers.UserRole_Student, "john.doe@school.com")
+	h.createUserWithEmail(t, pbusers.UserRole_Teacher, "jane.doe@school.com")
+	h.createUserWithEmail(t, pbusers.UserRole_Student, "bjohnson@school.com")
+	deletedID := h.createUserWithEmail(t, pbusers.UserRole_Student, "johnny@school.com")
+	if _, err := h.client.DeleteSoftOneUser(staff, &pbusers.DeleteSoftOneUserRequest{Id: deletedID}); err != nil {
+		t.Fatal(err)
+	}
+
+	runCases(t, h.client.SearchUsers, []testCase[*pbusers.SearchUsersRequest]{
+		{"Should search as teacher", authContext(t, uuid.NewString(), pbusers.UserRole_Teacher), &pbusers.SearchUsersRequest{Query: "john"}, codes.OK},
+		{"Should refuse student", authContext(t, uuid.NewString(), pbusers.UserRole_Student), &pbusers.SearchUsersRequest{Query: "john"}, codes.PermissionDenied},
+		{"Should refuse empty query", staff, &pbusers.SearchUsersRequest{}, codes.InvalidArgument},
+		{"Should refuse query without word", staff, &pbusers.SearchUsersRequest{Query: "@._"}, codes.InvalidArgument},
+		{"Should refuse unspecified role", staff, &pbusers.SearchUsersRequest{Query: "john", Roles: []pbusers.UserRole{pbusers.UserRole_Unspecified}}, codes.InvalidArgument},
+	})
+
+	search := func(t *testing.T, req *pbusers.SearchUsersRequest) []*pbusers.UserSearchResult {
+		t.Helper()
+		res, err := h.client.SearchUsers(staff, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Results
+	}
+
+	t.Run("Should rank word prefix above substring", func(t *testing.T) {
+		results := search(t, &pbusers.SearchUsersRequest{Query: "john"})
+		var got []string
+		for _, r := range results {
+			got = append(got, r.User.Email)
+		}
+		want := []string{"john.doe@school.com", "bjohnson@school.com"}
+		if !slices.Equal(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		if results[0].Rank <= results[1].Rank {
+			t.Fatalf("expected descending rank, got %v and %v", results[0].Rank, results[1].Rank)
+		}
+		if want := "<mark>john</mark>.doe@school.com"; results[0].Highlight != want {
+			t.Fatalf("expected highlight %q, got %q", want, results[0].Highlight)
+		}
+	})
+
+	t.Run("Should match every word", func(t *testing.T) {
+		results := search(t, &pbusers.SearchUsersRequest{Query: "ja doe"})
+		if len(results) != 1 || results[0].User.Email != "jane.doe@school.com" {
+			t.Fatalf("expected jane only, got %v", results)
+		}
+		if want := "<mark>ja</mark>ne.<mark>doe</mark>@school.com"; results[0].Highlight != want {
+			t.Fatalf("expected highlight %q, got %q", want, results[0].Highlight)
+		}
+	})
+
+	t.Run("Should filter by role", func(t *testing.T) {
+		results := search(t, &pbusers.SearchUsersRequest{Query: "doe", Roles: []pbusers.UserRole{pbusers.UserRole_Teacher}})
+		if len(results) != 1 || results[0].User.Email != "jane.doe@school.com" {
+			t.Fatalf("expected teacher only, got %v", results)
+		}
+	})
+
+	t.Run("Should limit results", func(t *testing.T) {
+		if results := search(t, &pbusers.SearchUsersRequest{Query: "school", PageSize: 2}); len(results) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(results))
+		}
+	})
+}
+
+func TestUpdateOneEmailUserRollback(t *testing.T) {
+	h, tx := newFailingHarness(t)
+	id, email := h.createUser(t, pbusers.UserRole_Student)
+
+	tx.fail = true
+	_, err := h.client.UpdateOneEmailUser(staffContext(t), &pbusers.UpdateOneEmailUserRequest{Id: id, Email: "updated@email.com"})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected verification token failure, got %v", err)
+	}
+	user, err := h.store.GetOneUser(context.Background(), uuid.MustParse(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != email {
+		t.Fatalf("expected email kept as %s, got %s", email, user.Email)
+	}
+}
+
+func TestUpdateOnePasswordUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	self := authContext(t, id, pbusers.UserRole_Student)
+	staff := staffContext(t)
+
+	newRequest := func(id, password string) *pbusers.UpdateOnePasswordUserRequest {
+		return &pbusers.UpdateOnePasswordUserRequest{Id: id, Password: password}
+	}
+	withCurrent := func(req *pbusers.UpdateOnePasswordUserRequest, current string) *pbusers.UpdateOnePasswordUserRequest {
+		req.CurrentPassword = current
+		return req
+	}
+	runCases(t, h.client.UpdateOnePasswordUser, []testCase[*pbusers.UpdateOnePasswordUserRequest]{
+		{"Should refuse short password", self, newRequest(id, "short"), codes.InvalidArgument},
+		{"Should refuse other user", authContext(t, uuid.NewString(), pbusers.UserRole_Teacher), newRequest(id, "anothersecretpassword"), codes.PermissionDenied},
+		{"Should return not found", staff, newRequest(uuid.NewString(), "anothersecretpassword"), codes.NotFound},
+		{"Should require current password", self, newRequest(id, "newsecretpassword"), codes.InvalidArgument},
+		{"Should refuse wrong current password", self, withCurrent(newRequest(id, "newsecretpassword"), "wrongpassword"), codes.Unauthenticated},
+		{"Should update own password", self, withCurrent(newRequest(id, "newsecretpassword"), password), codes.OK},
+		{"Should revoke token issued before update", self, newRequest(id, "othersecretpassword"), codes.Unauthenticated},
+		{"Should refuse reused password", staff, newRequest(id, password), codes.InvalidArgument},
+		{"Should update password as staff", staff, newRequest(id, "othersecretpassword"), codes.OK},
+	})
+}
+
+func TestUpdateOneEmailUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	_, taken := h.createUser(t, pbusers.UserRole_Student)
+	self := authContext(t, id, pbusers.UserRole_Student)
+
+	newRequest := func(id, email string) *pbusers.UpdateOneEmailUserRequest {
+		return &pbusers.UpdateOneEmailUserRequest{Id: id, Email: email}
+	}
+	runCases(t, h.client.UpdateOneEmailUser, []testCase[*pbusers.UpdateOneEmailUserRequest]{
+		{"Should update own email", self, newRequest(id, "updated@email.com"), codes.OK},
+		{"Should accept unchanged email", self, newRequest(id, "updated@email.com"), codes.OK},
+		{"Should refuse taken email", self, newRequest(id, taken), codes.AlreadyExists},
+		{"Should refuse invalid email", self, newRequest(id, "invalid"), codes.InvalidArgument},
+		{"Should refuse other user", authContext(t, uuid.NewString(), pbusers.UserRole_Student), newRequest(id, "other@email.com"), codes.PermissionDenied},
+		{"Should return not found", staffContext(t), newRequest(uuid.NewString(), "missing@email.com"), codes.NotFound},
+	})
+
+	if n := h.outbox.count("updated@email.com", "Email verification"); n != 1 {
+		t.Fatalf("expected single verification sent to updated email, got %d", n)
+	}
+}
+
+func TestUpdateOneRoleUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	staff := staffContext(t)
+
+	newRequest := func(id string, role pbusers.UserRole) *pbusers.UpdateOneRoleUserRequest {
+		return &pbusers.UpdateOneRoleUserRequest{Id: id, Role: role}
+	}
+	runCases(t, h.client.UpdateOneRoleUser, []testCase[*pbusers.UpdateOneRoleUserRequest]{
+		{"Should refuse own role update", authContext(t, id, pbusers.UserRole_Student), newRequest(id, pbusers.UserRole_Staff), codes.PermissionDenied},
+		{"Should update role", staff, newRequest(id, pbusers.UserRole_Teacher), codes.OK},
+		{"Should refuse unspecified role", staff, newRequest(id, pbusers.UserRole_Unspecified), codes.InvalidArgument},
+		{"Should return not found", staff, newRequest(uuid.NewString(), pbusers.UserRole_Teacher), codes.NotFound},
+	})
+}
+
+func TestDeleteSoftOneUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	staff := staffContext(t)
+
+	runCases(t, h.client.DeleteSoftOneUser, []testCase[*pbusers.DeleteSoftOneUserRequest]{
+		{"Should refuse non staff", authContext(t, id, pbusers.UserRole_Student), &pbusers.DeleteSoftOneUserRequest{Id: id}, codes.PermissionDenied},
+		{"Should soft delete user", staff, &pbusers.DeleteSoftOneUserRequest{Id: id}, codes.OK},
+		{"Should not delete twice", staff, &pbusers.DeleteSoftOneUserRequest{Id: id}, codes.NotFound},
+		{"Should refuse invalid id", staff, &pbusers.DeleteSoftOneUserRequest{Id: "invalid"}, codes.InvalidArgument},
+	})
+}
+
+func TestDeleteHardOneUser(t *testing.T) {
+	h := newHarness(t)
+	id, email := h.createUser(t, pbusers.UserRole_Student)
+	h.login(t, email)
+	staff := staffContext(t)
+
+	runCases(t, h.client.DeleteHardOneUser, []testCase[*pbusers.DeleteHardOneUserRequest]{
+		{"Should refuse non staff", authContext(t, id, pbusers.UserRole_Student), &pbusers.DeleteHardOneUserRequest{Id: id}, codes.PermissionDenied},
+		{"Should delete user with sessions", staff, &pbusers.DeleteHardOneUserRequest{Id: id}, codes.OK},
+		{"Should return not found", staff, &pbusers.DeleteHardOneUserRequest{Id: id}, codes.NotFound},
+	})
+}
+
+func TestRevokeUserRollback(t *testing.T) {
+	h, tx := newFailingHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	staff := staffContext(t)
+
+	tx.fail = true
+	if _, err := h.client.UpdateOneRoleUser(staff, &pbusers.UpdateOneRoleUserRequest{Id: id, Role: pbusers.UserRole_Teacher}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected revocation failure, got %v", err)
+	}
+	if _, err := h.client.DeleteSoftOneUser(staff, &pbusers.DeleteSoftOneUserRequest{Id: id}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected revocation failure, got %v", err)
+	}
+
+	user, err := h.store.GetOneUser(context.Background(), uuid.MustParse(id))
+	if err != nil {
+		t.Fatalf("expected user kept, got %v", err)
+	}
+	if user.Role != db.UserRoleStudent {
+		t.Fatalf("expected role kept, got %s", user.Role)
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	deletedID, deleted := h.createUser(t, pbusers.UserRole_Student)
+	if _, err := h.client.DeleteSoftOneUser(staffContext(t), &pbusers.DeleteSoftOneUserRequest{Id: deletedID}); err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(email, password, clientID string) *pbusers.LoginUserRequest {
+		return &pbusers.LoginUserRequest{Email: email, Password: password, ClientId: clientID}
+	}
+	runCases(t, h.client.LoginUser, []testCase[*pbusers.LoginUserRequest]{
+		{"Should login", nil, newRequest(email, password, ""), codes.OK},
+		{"Should login with known client", nil, newRequest(email, password, token.DefaultAudience), codes.OK},
+		{"Should refuse unknown client", nil, newRequest(email, password, "unknown"), codes.InvalidArgument},
+		{"Should refuse wrong password", nil, newRequest(email, "wrongpassword", ""), codes.Unauthenticated},
+		{"Should refuse unknown email", nil, newRequest("unknown@email.com", password, ""), codes.Unauthenticated},
+		{"Should refuse soft deleted user", nil, newRequest(deleted, password, ""), codes.Unauthenticated},
+	})
+}
+
+func TestLoginUserSpoofedClientIP(t *testing.T) {
+	h := newHarness(t)
+
+	// Rotating x-real-ip from untrusted peer must not escape ip throttle
+	for i := range 25 {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-real-ip", fmt.Sprintf("203.0.113.%d", i))
+		_, err := h.client.LoginUser(ctx, &pbusers.LoginUserRequest{
+			Email:    fmt.Sprintf("unknown%d@email.com", i),
+			Password: password,
+		})
+		if status.Code(err) == codes.ResourceExhausted {
+			return
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	t.Fatal("expected peer to be throttled regardless of x-real-ip")
+}
+
+func TestLoginUserResetClientThrottle(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+
+	// Failed attempts below ip limit are forgotten after successful login
+	fail := func() {
+		for i := range 15 {
+			_, err := h.client.LoginUser(context.Background(), &pbusers.LoginUserRequest{
+				Email:    fmt.Sprintf("unknown%d@email.com", i),
+				Password: password,
+			})
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("unexpected error %v", err)
+			}
+		}
+	}
+	fail()
+	h.login(t, email)
+	fail()
+}
+
+func TestVerifyTokenUser(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	tokens := h.login(t, email)
+
+	runCases(t, h.client.VerifyTokenUser, []testCase[*pbusers.VerifyTokenUserRequest]{
+		{"Should verify access token", nil, &pbusers.VerifyTokenUserRequest{AccessToken: tokens.AccessToken}, codes.OK},
+		{"Should refuse refresh token", nil, &pbusers.VerifyTokenUserRequest{AccessToken: tokens.RefreshToken}, codes.Unauthenticated},
+		{"Should refuse malformed token", nil, &pbusers.VerifyTokenUserRequest{AccessToken: "invalid"}, codes.Unauthenticated},
+	})
+}
+
+func TestRefreshTokenUser(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	tokens := h.login(t, email)
+
+	runCases(t, h.client.RefreshTokenUser, []testCase[*pbusers.RefreshTokenUserRequest]{
+		{"Should refuse access token", nil, &pbusers.RefreshTokenUserRequest{RefreshToken: tokens.AccessToken}, codes.Unauthenticated},
+		{"Should rotate refresh token", nil, &pbusers.RefreshTokenUserRequest{RefreshToken: tokens.RefreshToken}, codes.OK},
+		{"Should refuse reused refresh token", nil, &pbusers.RefreshTokenUserRequest{RefreshToken: tokens.RefreshToken}, codes.Unauthenticated},
+	})
+}
+
+// failingTx run transaction on store, queries inside it fail as
+// implemented by failingQuerier while fail is set
+type failingTx struct {
+	*memdb.Store
+	fail bool
+}
+
+// newFailingHarness start service whose transactions are run by returned
+// failingTx
+func newFailingHarness(t *testing.T) (*harness, *failingTx) {
+	t.Helper()
+	tx := &failingTx{}
+	h := newHarnessWithTx(t, func(store *memdb.Store) database.TxRunner {
+		tx.Store = store
+		return tx
+	})
+	return h, tx
+}
+
+type failingQuerier struct {
+	db.Querier
+}
+
+func (failingQuerier) CreateOneSession(context.Context, *db.CreateOneSessionParams) (uuid.UUID, error) {
+	return uuid.Nil, fmt.Errorf("insert session failed")
+}
+
+func (failingQuerier) CreateOneMfaRecoveryCode(context.Context, *db.CreateOneMfaRecoveryCodeParams) error {
+	return fmt.Errorf("insert recovery code failed")
+}
+
+func (failingQuerier) CreateOneEmailVerificationToken(context.Context, *db.CreateOneEmailVerificationTokenParams) (uuid.UUID, error) {
+	return uuid.Nil, fmt.Errorf("insert verification token failed")
+}
+
+func (failingQuerier) UpsertOneUserTokenRevocation(context.Context, *db.UpsertOneUserTokenRevocationParams) error {
+	return fmt.Errorf("revoke user tokens failed")
+}
+
+func (f *failingTx) RunTx(ctx context.Context, fn func(q db.Querier) error) error {
+	return f.Store.RunTx(ctx, func(q db.Querier) error {
+		if f.fail {
+			q = failingQuerier{q}
+		}
+		return fn(q)
+	})
+}
+
+func TestRefreshTokenUserRollback(t *testing.T) {
+	h, tx := newFailingHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	tokens := h.login(t, email)
+	req := &pbusers.RefreshTokenUserRequest{RefreshToken: tokens.RefreshToken}
+
+	tx.fail = true
+	runCases(t, h.client.RefreshTokenUser, []testCase[*pbusers.RefreshTokenUserRequest]{
+		{"Should fail when new session can not be stored", nil, req, codes.Internal},
+	})
+
+	tx.fail = false
+	runCases(t, h.client.RefreshTokenUser, []testCase[*pbusers.RefreshTokenUserRequest]{
+		{"Should keep old refresh token usable", nil, req, codes.OK},
+		{"Should refuse reused refresh token", nil, req, codes.Unauthenticated},
+	})
+}
+
+func TestGetJwksUser(t *testing.T) {
+	h := newHarness(t)
+
+	runCases(t, h.client.GetJwksUser, []testCase[*pbusers.GetJwksUserRequest]{
+		{"Should return keys anonymously", nil, &pbusers.GetJwksUserRequest{}, codes.OK},
+	})
+}
+
+func TestUnlockUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	staff := staffContext(t)
+
+	runCases(t, h.client.UnlockUser, []testCase[*pbusers.UnlockUserRequest]{
+		{"Should unlock user", staff, &pbusers.UnlockUserRequest{Id: id}, codes.OK},
+		{"Should refuse non staff", authContext(t, id, pbusers.UserRole_Student), &pbusers.UnlockUserRequest{Id: id}, codes.PermissionDenied},
+		{"Should return not found", staff, &pbusers.UnlockUserRequest{Id: uuid.NewString()}, codes.NotFound},
+	})
+}
+
+func TestLogoutUser(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	tokens := h.login(t, email)
+	other := h.login(t, email)
+
+	runCases(t, h.client.LogoutUser, []testCase[*pbusers.LogoutUserRequest]{
+		{"Should refuse access token", nil, &pbusers.LogoutUserRequest{RefreshToken: tokens.AccessToken}, codes.Unauthenticated},
+		{"Should logout session", nil, &pbusers.LogoutUserRequest{RefreshToken: tokens.RefreshToken, AccessToken: tokens.AccessToken}, codes.OK},
+		{"Should logout every session", nil, &pbusers.LogoutUserRequest{RefreshToken: other.RefreshToken, All: true}, codes.OK},
+		{"Should refuse revoked refresh token", nil, &pbusers.LogoutUserRequest{RefreshToken: other.RefreshToken}, codes.Unauthenticated},
+	})
+
+	if _, err := h.client.VerifyTokenUser(context.Background(), &pbusers.VerifyTokenUserRequest{AccessToken: tokens.AccessToken}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected access token revoked, got %v", err)
+	}
+}
+
+func TestListSessionsUser(t *testing.T) {
+	h := newHarness(t)
+	id, email := h.createUser(t, pbusers.UserRole_Student)
+	tokens := h.login(t, email)
+	self := bearerContext(tokens.AccessToken)
+
+	runCases(t, h.client.ListSessionsUser, []testCase[*pbusers.ListSessionsUserRequest]{
+		{"Should list own sessions", self, &pbusers.ListSessionsUserRequest{Id: id}, codes.OK},
+		{"Should list sessions as staff", staffContext(t), &pbusers.ListSessionsUserRequest{Id: id}, codes.OK},
+		{"Should refuse other user", self, &pbusers.ListSessionsUserRequest{Id: uuid.NewString()}, codes.PermissionDenied},
+	})
+
+	res, err := h.client.ListSessionsUser(self, &pbusers.ListSessionsUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(res.Sessions))
+	}
+}
+
+func TestRevokeSessionUser(t *testing.T) {
+	h := newHarness(t)
+	id, email := h.createUser(t, pbusers.UserRole_Student)
+	tokens := h.login(t, email)
+	self := bearerContext(tokens.AccessToken)
+	sessions, err := h.client.ListSessionsUser(self, &pbusers.ListSessionsUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID := sessions.Sessions[0].Id
+
+	newRequest := func(id, sessionID string) *pbusers.RevokeSessionUserRequest {
+		return &pbusers.RevokeSessionUserRequest{Id: id, SessionId: sessionID}
+	}
+	runCases(t, h.client.RevokeSessionUser, []testCase[*pbusers.RevokeSessionUserRequest]{
+		{"Should refuse other user", authContext(t, uuid.NewString(), pbusers.UserRole_Student), newRequest(id, sessionID), codes.PermissionDenied},
+		{"Should refuse invalid session id", self, newRequest(id, "invalid"), codes.InvalidArgument},
+		{"Should revoke own session", self, newRequest(id, sessionID), codes.OK},
+		{"Should not revoke twice", self, newRequest(id, sessionID), codes.NotFound},
+	})
+
+	if _, err := h.client.RefreshTokenUser(context.Background(), &pbusers.RefreshTokenUserRequest{RefreshToken: tokens.RefreshToken}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected refresh token of revoked session refused, got %v", err)
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+
+	runCases(t, h.client.RequestPasswordReset, []testCase[*pbusers.RequestPasswordResetRequest]{
+		{"Should send reset token", nil, &pbusers.RequestPasswordResetRequest{Email: email}, codes.OK},
+		{"Should not disclose unknown email", nil, &pbusers.RequestPasswordResetRequest{Email: "unknown@email.com"}, codes.OK},
+		{"Should refuse invalid email", nil, &pbusers.RequestPasswordResetRequest{Email: "invalid"}, codes.InvalidArgument},
+	})
+
+	if h.outbox.lastToken(email, "Password reset") == "" {
+		t.Fatal("reset token was not sent")
+	}
+	if h.outbox.lastToken("unknown@email.com", "Password reset") != "" {
+		t.Fatal("reset token sent to unknown email")
+	}
+	// Mail sent to single address is limited silently
+	for range 5 {
+		if _, err := h.client.RequestPasswordReset(context.Background(), &pbusers.RequestPasswordResetRequest{Email: email}); err != nil {
+			t.Fatalf("expected extra reset request accepted, got %v", err)
+		}
+	}
+	if n := h.outbox.count(email, "Password reset"); n != 3 {
+		t.Fatalf("expected 3 reset mails, got %d", n)
+	}
+
+	// Confirming reset lift the limit
+	resetToken := h.outbox.lastToken(email, "Password reset")
+	if _, err := h.client.ConfirmPasswordReset(context.Background(), &pbusers.ConfirmPasswordResetRequest{Token: resetToken, Password: "newsecretpassword"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.client.RequestPasswordReset(context.Background(), &pbusers.RequestPasswordResetRequest{Email: email}); err != nil {
+		t.Fatal(err)
+	}
+	if n := h.outbox.count(email, "Password reset"); n != 4 {
+		t.Fatalf("expected reset mail after confirmation, got %d", n)
+	}
+}
+
+func TestRequestPasswordResetPerClient(t *testing.T) {
+	h := newHarness(t)
+
+	var err error
+	for i := range 15 {
+		_, err = h.client.RequestPasswordReset(context.Background(), &pbusers.RequestPasswordResetRequest{
+			Email: fmt.Sprintf("user%d@email.com", i),
+		})
+		if err != nil {
+			break
+		}
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected client limited, got %v", err)
+	}
+}
+
+func TestConfirmPasswordReset(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	if _, err := h.client.RequestPasswordReset(context.Background(), &pbusers.RequestPasswordResetRequest{Email: email}); err != nil {
+		t.Fatal(err)
+	}
+	resetToken := h.outbox.lastToken(email, "Password reset")
+
+	newRequest := func(token, password string) *pbusers.ConfirmPasswordResetRequest {
+		return &pbusers.ConfirmPasswordResetRequest{Token: token, Password: password}
+	}
+	runCases(t, h.client.ConfirmPasswordReset, []testCase[*pbusers.ConfirmPasswordResetRequest]{
+		{"Should refuse unknown token", nil, newRequest("invalid", "newsecretpassword"), codes.InvalidArgument},
+		{"Should refuse reused password", nil, newRequest(resetToken, password), codes.InvalidArgument},
+		{"Should reset password", nil, newRequest(resetToken, "newsecretpassword"), codes.OK},
+		{"Should not reset twice", nil, newRequest(resetToken, "othersecretpassword"), codes.InvalidArgument},
+	})
+
+	_, err := h.client.LoginUser(context.Background(), &pbusers.LoginUserRequest{Email: email, Password: "newsecretpassword"})
+	if err != nil {
+		t.Fatalf("expected login with new password, got %v", err)
+	}
+}
+
+func TestVerifyEmailUser(t *testing.T) {
+	h := newHarness(t)
+	_, email := h.createUser(t, pbusers.UserRole_Student)
+	verificationToken := h.outbox.lastToken(email, "Email verification")
+
+	runCases(t, h.client.VerifyEmailUser, []testCase[*pbusers.VerifyEmailUserRequest]{
+		{"Should refuse unknown token", nil, &pbusers.VerifyEmailUserRequest{Token: "invalid"}, codes.InvalidArgument},
+		{"Should verify email", nil, &pbusers.VerifyEmailUserRequest{Token: verificationToken}, codes.OK},
+		{"Should not verify twice", nil, &pbusers.VerifyEmailUserRequest{Token: verificationToken}, codes.InvalidArgument},
+	})
+}
+
+func totpCode(secret string) (string, error) {
+	return totp.Code(secret, totp.Step(time.Now()))
+}
+
+// wrongTotpCode return code which differ from code at every digit
+func wrongTotpCode(code string) string {
+	wrong := []byte(code)
+	for i, c := range wrong {
+		wrong[i] = '0' + (c-'0'+5)%10
+	}
+	return string(wrong)
+}
+
+// enrollTotp enroll and confirm TOTP of user and return its secret and
+// recovery codes
+func enrollTotp(t *testing.T, h *harness, ctx context.Context, id string) (string, []string) {
+	t.Helper()
+	enrollment, err := h.client.EnrollTotpUser(ctx, &pbusers.EnrollTotpUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totpCode(enrollment.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := h.client.ConfirmTotpUser(ctx, &pbusers.ConfirmTotpUserRequest{Id: id, Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enrollment.Secret, res.RecoveryCodes
+}
+
+func TestVerifyMfaUser(t *testing.T) {
+	h := newHarness(t)
+	id, email := h.createUser(t, pbusers.UserRole_Student)
+	_, recoveryCodes := enrollTotp(t, h, authContext(t, id, pbusers.UserRole_Student), id)
+	challenge := h.login(t, email)
+	if !challenge.MfaRequired || challenge.AccessToken != "" {
+		t.Fatalf("expected mfa challenge, got %v", challenge)
+	}
+	tokens := h.login(t, email)
+
+	newRequest := func(mfaToken, code string) *pbusers.VerifyMfaUserRequest {
+		return &pbusers.VerifyMfaUserRequest{MfaToken: mfaToken, Code: code}
+	}
+	runCases(t, h.client.VerifyMfaUser, []testCase[*pbusers.VerifyMfaUserRequest]{
+		{"Should refuse malformed token", nil, newRequest("invalid", recoveryCodes[0]), codes.Unauthenticated},
+		{"Should refuse wrong code", nil, newRequest(challenge.MfaToken, "000000"), codes.Unauthenticated},
+		{"Should accept recovery code", nil, newRequest(challenge.MfaToken, recoveryCodes[0]), codes.OK},
+		{"Should not complete challenge twice", nil, newRequest(challenge.MfaToken, recoveryCodes[1]), codes.Unauthenticated},
+		{"Should not accept used recovery code", nil, newRequest(tokens.MfaToken, recoveryCodes[0]), codes.Unauthenticated},
+	})
+}
+
+func TestEnrollTotpUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+
+	runCases(t, h.client.EnrollTotpUser, []testCase[*pbusers.EnrollTotpUserRequest]{
+		{"Should enroll self", authContext(t, id, pbusers.UserRole_Student), &pbusers.EnrollTotpUserRequest{Id: id}, codes.OK},
+		{"Should never reveal secret to staff", staffContext(t), &pbusers.EnrollTotpUserRequest{Id: id}, codes.PermissionDenied},
+	})
+
+	enrollTotp(t, h, authContext(t, id, pbusers.UserRole_Student), id)
+	_, err := h.client.EnrollTotpUser(authContext(t, id, pbusers.UserRole_Student), &pbusers.EnrollTotpUserRequest{Id: id})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected code %s, got %v", codes.AlreadyExists, err)
+	}
+}
+
+func TestConfirmTotpUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	pendingID, _ := h.createUser(t, pbusers.UserRole_Student)
+	self := authContext(t, id, pbusers.UserRole_Student)
+	enrollment, err := h.client.EnrollTotpUser(self, &pbusers.EnrollTotpUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totpCode(enrollment.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(id, code string) *pbusers.ConfirmTotpUserRequest {
+		return &pbusers.ConfirmTotpUserRequest{Id: id, Code: code}
+	}
+	runCases(t, h.client.ConfirmTotpUser, []testCase[*pbusers.ConfirmTotpUserRequest]{
+		{"Should refuse malformed code", self, newRequest(id, "abc"), codes.InvalidArgument},
+		{"Should refuse wrong code", self, newRequest(id, wrongTotpCode(code)), codes.Unauthenticated},
+		{"Should refuse not enrolled user", authContext(t, pendingID, pbusers.UserRole_Student), newRequest(pendingID, code), codes.FailedPrecondition},
+		{"Should confirm enrollment", self, newRequest(id, code), codes.OK},
+		{"Should not confirm twice", self, newRequest(id, code), codes.AlreadyExists},
+	})
+}
+
+func TestConfirmTotpUserThrottle(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	self := authContext(t, id, pbusers.UserRole_Student)
+	enrollment, err := h.client.EnrollTotpUser(self, &pbusers.EnrollTotpUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totpCode(enrollment.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 10 {
+		_, err = h.client.ConfirmTotpUser(self, &pbusers.ConfirmTotpUserRequest{Id: id, Code: wrongTotpCode(code)})
+		if status.Code(err) != codes.Unauthenticated {
+			break
+		}
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected guessing code throttled, got %v", err)
+	}
+}
+
+func TestConfirmTotpUserRollback(t *testing.T) {
+	h, tx := newFailingHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	self := authContext(t, id, pbusers.UserRole_Student)
+	enrollment, err := h.client.EnrollTotpUser(self, &pbusers.EnrollTotpUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totpCode(enrollment.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx.fail = true
+	_, err = h.client.ConfirmTotpUser(self, &pbusers.ConfirmTotpUserRequest{Id: id, Code: code})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected recovery code failure, got %v", err)
+	}
+	mfa, err := h.store.GetOneUserMfa(context.Background(), uuid.MustParse(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mfa.ConfirmedAt.Valid {
+		t.Fatal("expected enrollment left pending")
+	}
+}
+
+func TestDisableTotpUser(t *testing.T) {
+	h := newHarness(t)
+	id, _ := h.createUser(t, pbusers.UserRole_Student)
+	enrollTotp(t, h, authContext(t, id, pbusers.UserRole_Student), id)
+	selfID, _ := h.createUser(t, pbusers.UserRole_Student)
+	self := authContext(t, selfID, pbusers.UserRole_Student)
+	_, recoveryCodes := enrollTotp(t, h, self, selfID)
+	staff := staffContext(t)
+
+	runCases(t, h.client.DisableTotpUser, []testCase[*pbusers.DisableTotpUserRequest]{
+		{"Should refuse other user", authContext(t, uuid.NewString(), pbusers.UserRole_Student), &pbusers.DisableTotpUserRequest{Id: id}, codes.PermissionDenied},
+		{"Should disable as staff without code", staff, &pbusers.DisableTotpUserRequest{Id: id}, codes.OK},
+		{"Should not disable twice", staff, &pbusers.DisableTotpUserRequest{Id: id}, codes.FailedPrecondition},
+		{"Should require code from self", self, &pbusers.DisableTotpUserRequest{Id: selfID}, codes.InvalidArgument},
+		{"Should refuse invalid code from self", self, &pbusers.DisableTotpUserRequest{Id: selfID, Code: "aaaa-bbbb"}, codes.Unauthenticated},
+		{"Should disable as self with recovery code", self, &pbusers.DisableTotpUserRequest{Id: selfID, Code: recoveryCodes[0]}, codes.OK},
+	})
+}
+
+func TestUpdateMfaRoleUser(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+
+	newRequest := func(role pbusers.UserRole, required bool) *pbusers.UpdateMfaRoleUserRequest {
+		return &pbusers.UpdateMfaRoleUserRequest{Role: role, Required: required}
+	}
+	runCases(t, h.client.UpdateMfaRoleUser, []testCase[*pbusers.UpdateMfaRoleUserRequest]{
+		{"Should require mfa for role", staff, newRequest(pbusers.UserRole_Teacher, true), codes.OK},
+		{"Should refuse unspecified role", staff, newRequest(pbusers.UserRole_Unspecified, true), codes.InvalidArgument},
+		{"Should refuse non staff", authContext(t, uuid.NewString(), pbusers.UserRole_Teacher), newRequest(pbusers.UserRole_Teacher, false), codes.PermissionDenied},
+	})
+
+	_, email := h.createUser(t, pbusers.UserRole_Teacher)
+	_, err := h.client.LoginUser(context.Background(), &pbusers.LoginUserRequest{Email: email, Password: password})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected enrollment required, got %v", err)
+	}
+}
+
+func TestIssueMfaEnrollmentUser(t *testing.T) {
+	h := newHarness(t)
+	staff := staffContext(t)
+	if _, err := h.client.UpdateMfaRoleUser(staff, &pbusers.UpdateMfaRoleUserRequest{Role: pbusers.UserRole_Teacher, Required: true}); err != nil {
+		t.Fatal(err)
+	}
+	id, email := h.createUser(t, pbusers.UserRole_Teacher)
+	otherID, _ := h.createUser(t, pbusers.UserRole_Teacher)
+
+	runCases(t, h.client.IssueMfaEnrollmentUser, []testCase[*pbusers.IssueMfaEnrollmentUserRequest]{
+		{"Should refuse self", authContext(t, id, pbusers.UserRole_Teacher), &pbusers.IssueMfaEnrollmentUserRequest{Id: id}, codes.PermissionDenied},
+		{"Should return not found", staff, &pbusers.IssueMfaEnrollmentUserRequest{Id: uuid.NewString()}, codes.NotFound},
+		{"Should issue as staff", staff, &pbusers.IssueMfaEnrollmentUserRequest{Id: id}, codes.OK},
+	})
+	enrollment := bearerContext(h.outbox.lastToken(email, "Two-factor authentication enrollment"))
+
+	// Enrollment token only authenticate enrollment of its owner
+	if _, err := h.client.GetOneUser(enrollment, &pbusers.GetOneUserRequest{Id: id}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected code %s, got %v", codes.PermissionDenied, err)
+	}
+	if _, err := h.client.EnrollTotpUser(enrollment, &pbusers.EnrollTotpUserRequest{Id: otherID}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected code %s, got %v", codes.PermissionDenied, err)
+	}
+
+	_, recoveryCodes := enrollTotp(t, h, enrollment, id)
+	if len(recoveryCodes) == 0 {
+		t.Fatal("no recovery code returned")
+	}
+	if _, err := h.client.EnrollTotpUser(enrollment, &pbusers.EnrollTotpUserRequest{Id: id}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected used enrollment token refused, got %v", err)
+	}
+	if _, err := h.client.IssueMfaEnrollmentUser(staff, &pbusers.IssueMfaEnrollmentUserRequest{Id: id}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected code %s, got %v", codes.AlreadyExists, err)
+	}
+
+	// Enrolled user pass challenge, TOTP code was spent by confirmation
+	challenge := h.login(t, email)
+	if !challenge.MfaRequired {
+		t.Fatalf("expected mfa challenge, got %v", challenge)
+	}
+	res, err := h.client.VerifyMfaUser(context.Background(), &pbusers.VerifyMfaUserRequest{MfaToken: challenge.MfaToken, Code: recoveryCodes[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken == "" {
+		t.Fatal("no access token issued")
+	}
+}
@@ -0,0 +1,260 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	errSessionNotFound = status.Error(codes.NotFound, "session not found")
+	errSessionRevoked  = status.Error(codes.Unauthenticated, "session revoked")
+	errTokenReused     = status.Error(codes.Unauthenticated, "refresh token reuse detected")
+)
+
+// runTxRevokingUser run fn in transaction and revoke every token of user
+// issued so far. Revocation kept in users database commit along with fn,
+// other store revoke before transaction begin so failed revocation never
+// leave change applied while old token stay valid.
+func (s *service) runTxRevokingUser(ctx context.Context, userID uuid.UUID, fn func(q db.Querier) error) error {
+	if !token.RevocationJoinsTx() {
+		if err := token.RevokeSubject(ctx, userID.String()); err != nil {
+			return err
+		}
+		return s.tx.RunTx(ctx, fn)
+	}
+	return s.tx.RunTx(ctx, func(q db.Querier) error {
+		if err := fn(q); err != nil {
+			return err
+		}
+		return token.RevokeSubjectTx(ctx, q, userID.String())
+	})
+}
+
+// createSession store refresh token claims as new session entry using q
+func createSession(ctx context.Context, q db.Querier, c *token.Claims) error {
+	id, err := uuid.Parse(c.Jti)
+	if err != nil {
+		log.Printf("error: invalid refresh token id. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	familyID, err := uuid.Parse(c.Sid)
+	if err != nil {
+		log.Printf("error: invalid session id. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	userID, err := uuid.Parse(c.Sub)
+	if err != nil {
+		log.Printf("error: invalid user id. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+
+	_, err = q.CreateOneSession(ctx, &db.CreateOneSessionParams{
+		ID:        id,
+		FamilyID:  familyID,
+		UserID:    userID,
+		ExpiresAt: pgtype.Timestamptz{Time: c.Exp.Time, Valid: true},
+	})
+	if err != nil {
+		log.Printf("error: failed to insert new session. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	return nil
+}
+
+// issueTokens create access and refresh token for sub and store the
+// refresh token as session entry using q
+func issueTokens(ctx context.Context, q db.Querier, sub token.Subject, aud []string) (string, string, error) {
+	accessToken, _, err := token.CreateToken(token.TokenTypeAccess, sub, accessTokenTTL, aud)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, refreshClaims, err := token.CreateToken(token.TokenTypeRefresh, sub, refreshTokenTTL, aud)
+	if err != nil {
+		return "", "", err
+	}
+	if err := createSession(ctx, q, refreshClaims); err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// rotateSession mark refresh token as used and issue tokens for sub
+// within single transaction, so old token is only burned once new one is
+// stored. Token which can not be rotated is passed to rejectSession.
+func (s *service) rotateSession(ctx context.Context, c *token.Claims, sub token.Subject) (string, string, error) {
+	id, err := uuid.Parse(c.Jti)
+	if err != nil {
+		return "", "", status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	var accessToken, refreshToken string
+	err = s.tx.RunTx(ctx, func(q db.Querier) error {
+		if _, err := q.RotateOneSession(ctx, id); err != nil {
+			return err
+		}
+		accessToken, refreshToken, err = issueTokens(ctx, q, sub, c.Aud)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", s.rejectSession(ctx, id)
+		}
+		if _, ok := status.FromError(err); ok {
+			return "", "", err
+		}
+		log.Printf("error: failed to rotate session. %s\n", err.Error())
+		return "", "", errs.ErrInternalServer
+	}
+	return accessToken, refreshToken, nil
+}
+
+// rejectSession find out why refresh token id can not be rotated. When
+// token was already rotated it is treated as stolen and whole session
+// family is revoked. It run outside rotation transaction so revocation
+// is not rolled back.
+func (s *service) rejectSession(ctx context.Context, id uuid.UUID) error {
+	session, err := s.q.GetOneSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.Unauthenticated, "invalid token")
+		}
+		log.Printf("error: failed to get session. %s\n", err.Error())
+		return errs.ErrInternalServer
+	}
+	if session.RotatedAt.Valid && !session.RevokedAt.Valid {
+		log.Printf("warning: refresh token reuse detected, revoking session %s\n", session.FamilyID.String())
+		_, err := s.q.RevokeFamilySession(ctx, &db.RevokeFamilySessionParams{
+			FamilyID: session.FamilyID,
+			UserID:   session.UserID,
+		})
+		if err != nil {
+			log.Printf("error: failed to revoke session. %s\n", err.Error())
+			return errs.ErrInternalServer
+		}
+		return errTokenReused
+	}
+	return errSessionRevoked
+}
+
+func (s *service) LogoutUser(
+	ctx context.Context,
+	req *pbusers.LogoutUserRequest,
+) (*pbusers.LogoutUserResponse, error) {
+	claims, err := token.VerifyToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.GetTokenType() != token.TokenTypeRefresh {
+		return nil, errInvalidTokenType
+	}
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if req.All {
+		_, err = s.q.RevokeAllSessionByUser(ctx, userID)
+		if err == nil {
+			err = token.RevokeSubject(ctx, claims.Sub)
+		}
+	} else {
+		familyID, parseErr := uuid.Parse(claims.Sid)
+		if parseErr != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		_, err = s.q.RevokeFamilySession(ctx, &db.RevokeFamilySessionParams{
+			FamilyID: familyID,
+			UserID:   userID,
+		})
+	}
+	if err != nil {
+		log.Printf("error: failed to revoke session. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	// Revoke access token of session when provided
+	if req.AccessToken != "" {
+		accessClaims, err := token.VerifyToken(ctx, req.AccessToken)
+		if err == nil && accessClaims.Sub == claims.Sub && accessClaims.GetTokenType() == token.TokenTypeAccess {
+			if err := token.RevokeToken(ctx, accessClaims.Jti, accessClaims.Exp.Time); err != nil {
+				log.Printf("error: failed to revoke access token. %s\n", err.Error())
+				return nil, errs.ErrInternalServer
+			}
+		}
+	}
+
+	return &pbusers.LogoutUserResponse{
+		Id: userID.String(),
+	}, nil
+}
+
+func (s *service) ListSessionsUser(
+	ctx context.Context,
+	req *pbusers.ListSessionsUserRequest,
+) (*pbusers.ListSessionsUserResponse, error) {
+	userID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.q.GetManySessionByUser(ctx, userID)
+	if err != nil {
+		log.Printf("error: failed to get sessions. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+
+	sessions := make([]*pbusers.Session, 0, len(res))
+	for _, session := range res {
+		sessions = append(sessions, &pbusers.Session{
+			Id:        session.FamilyID.String(),
+			CreatedAt: timestamppb.New(session.CreatedAt.Time),
+			ExpiresAt: timestamppb.New(session.ExpiresAt.Time),
+		})
+	}
+
+	return &pbusers.ListSessionsUserResponse{
+		Sessions: sessions,
+	}, nil
+}
+
+func (s *service) RevokeSessionUser(
+	ctx context.Context,
+	req *pbusers.RevokeSessionUserRequest,
+) (*pbusers.RevokeSessionUserResponse, error) {
+	userID, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	familyID, err := parseID(req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.q.RevokeFamilySession(ctx, &db.RevokeFamilySessionParams{
+		FamilyID: familyID,
+		UserID:   userID,
+	})
+	if err != nil {
+		log.Printf("error: failed to revoke session. %s\n", err.Error())
+		return nil, errs.ErrInternalServer
+	}
+	if count == 0 {
+		return nil, errSessionNotFound
+	}
+
+	return &pbusers.RevokeSessionUserResponse{
+		SessionId: familyID.String(),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	"google.golang.org/grpc/metadata"
+)
+
+// staffContext create staff user directly in database and return
+// context carrying its access token
+func staffContext(tb testing.TB) context.Context {
+	tb.Helper()
+	ctx := context.Background()
+	q := connectDB(tb)
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	passwordHash, err := hasher.GenerateFromPassword("secretpassword", hasher.DefaultConfig)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_, err = q.CreateOneUser(ctx, &db.CreateOneUserParams{
+		ID:           id,
+		Email:        fmt.Sprintf("staff%s@email.com", uuid.NewString()),
+		Role:         db.UserRoleStaff,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		tb.Fatalf("unable to create staff user: %s", err.Error())
+	}
+	tb.Cleanup(func() {
+		_, _ = q.DeleteHardOneUser(ctx, id)
+	})
+
+	sub := token.Subject{ID: id.String(), Role: string(db.UserRoleStaff), SessionID: uuid.NewString()}
+	accessToken, _, err := token.CreateToken(token.TokenTypeAccess, sub, time.Hour, token.Audiences())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken)
+}
+
+// userContext return context carrying access token of logged in user
+func userContext(accessToken string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+accessToken)
+}
+
+// connectDB return queries connected directly to harness database
+func connectDB(tb testing.TB) *db.Queries {
+	tb.Helper()
+	if h == nil {
+		tb.Skip(hErr.Error())
+	}
+	return h.Queries
+}
@@ -1,45 +1,22 @@
 package user_test
 
 import (
-	"context"
 	"fmt"
-	"log"
-	"os"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
-func createService() pbusers.UserServiceClient {
-	host, ok := os.LookupEnv("HOST")
-	if !ok {
-		log.Fatal("HOST not found")
-	}
-	port, ok := os.LookupEnv("PORT")
-	if !ok {
-		log.Fatal("PORT not found")
-	}
-	addr := fmt.Sprintf("%s:%s", host, port)
-
-	client, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Fatalf("unable to connect to client: %s", err.Error())
-	}
-	service := pbusers.NewUserServiceClient(client)
-
-	return service
-}
-
 func TestCreateUser(t *testing.T) {
-	service := createService()
+	service := createService(t)
+	staffCtx := staffContext(t)
 
 	t.Run("Should success create user", func(t *testing.T) {
-		ctx := context.TODO()
+		ctx := staffCtx
 		req := &pbusers.CreateOneUserRequest{
 			Email:    "dummy@email.com",
 			Password: "secretpassword",
@@ -59,7 +36,7 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	t.Run("Should validation error", func(t *testing.T) {
-		ctx := context.TODO()
+		ctx := staffCtx
 		req := &pbusers.CreateOneUserRequest{
 			Email:    "dummyemail.com",
 			Password: "sword",
@@ -82,7 +59,7 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	t.Run("Should conflict error", func(t *testing.T) {
-		ctx := context.TODO()
+		ctx := staffCtx
 		req := &pbusers.CreateOneUserRequest{
 			Email:    "dummy@email.com",
 			Password: "secretpassword",
@@ -111,14 +88,57 @@ func TestCreateUser(t *testing.T) {
 			t.Fail()
 		}
 	})
+
+	t.Run("Should create only one of concurrent duplicate", func(t *testing.T) {
+		ctx := staffCtx
+		req := &pbusers.CreateOneUserRequest{
+			Email:    fmt.Sprintf("user%s@email.com", uuid.NewString()),
+			Password: "secretpassword",
+			Role:     pbusers.UserRole_Student,
+		}
+
+		const n = 5
+		results := make(chan error, n)
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := service.CreateOneUser(ctx, req)
+				if err == nil {
+					t.Cleanup(func() {
+						_, _ = service.DeleteHardOneUser(ctx, &pbusers.DeleteHardOneUserRequest{Id: res.Id})
+					})
+				}
+				results <- err
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		created := 0
+		for err := range results {
+			switch status.Code(err) {
+			case codes.OK:
+				created++
+			case codes.AlreadyExists:
+			default:
+				t.Errorf("unexpected error %v", err)
+			}
+		}
+		if created != 1 {
+			t.Errorf("expected one user created, got %d", created)
+		}
+	})
 }
 
 func BenchmarkCreateUser(b *testing.B) {
-	service := createService()
+	service := createService(b)
+	staffCtx := staffContext(b)
 
 	for b.Loop() {
 
-		ctx := context.TODO()
+		ctx := staffCtx
 
 		req := &pbusers.CreateOneUserRequest{
 			Email:    fmt.Sprintf("user%s@email.com", uuid.NewString()),
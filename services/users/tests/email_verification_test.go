@@ -0,0 +1,97 @@
+package user_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// createVerificationToken insert email verification token for user
+// directly in database since the token itself is only delivered through
+// notifier
+func createVerificationToken(t *testing.T, userID, email string) string {
+	t.Helper()
+	secret := uuid.NewString()
+	sum := sha256.Sum256([]byte(secret))
+	_, err := connectDB(t).CreateOneEmailVerificationToken(context.Background(), &db.CreateOneEmailVerificationTokenParams{
+		ID:        uuid.New(),
+		UserID:    uuid.MustParse(userID),
+		Email:     email,
+		TokenHash: hex.EncodeToString(sum[:]),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("unable to create verification token: %s", err.Error())
+	}
+	return secret
+}
+
+func TestVerifyEmail(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	t.Run("Should success verify email once", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		user, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.EmailVerifiedAt != "" {
+			t.Fatal("new user email should not be verified")
+		}
+
+		secret := createVerificationToken(t, id, user.Email)
+		res, err := service.VerifyEmailUser(context.TODO(), &pbusers.VerifyEmailUserRequest{Token: secret})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Id != id {
+			t.Fail()
+		}
+
+		user, err = service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.EmailVerifiedAt == "" {
+			t.Fail()
+		}
+
+		_, err = service.VerifyEmailUser(context.TODO(), &pbusers.VerifyEmailUserRequest{Token: secret})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should reject token of previous email", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		user, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		secret := createVerificationToken(t, id, user.Email)
+
+		_, err = service.UpdateOneEmailUser(ctx, &pbusers.UpdateOneEmailUserRequest{
+			Id:    id,
+			Email: fmt.Sprintf("user%s@email.com", uuid.NewString()),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = service.VerifyEmailUser(context.TODO(), &pbusers.VerifyEmailUserRequest{Token: secret})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+}
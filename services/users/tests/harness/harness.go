@@ -0,0 +1,152 @@
+// Package harness boot users service on bufconn against throwaway
+// PostgreSQL so integration tests run without externally started server
+// and database. Local postgres binary is launched in temporary directory
+// when installed, otherwise throwaway database is created in TEST_DSN
+// server.
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	migrationsfs "github.com/nurfianqodar/school-microservices/services/users/misc/db/migrations"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	svc "github.com/nurfianqodar/school-microservices/services/users/services"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/migrate"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/passwordpolicy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token/revocation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// defaultSecret sign token when SECRET environment variable was not set
+const defaultSecret = "harness-secret"
+
+// Harness is running users service backed by migrated throwaway database
+type Harness struct {
+	// Client connected to service through bufconn
+	Client pbusers.UserServiceClient
+	// Pool and Queries connect directly to database, used to arrange
+	// state which is not reachable through service
+	Pool    *pgxpool.Pool
+	Queries *db.Queries
+
+	cluster cluster
+	server  *grpc.Server
+	conn    *grpc.ClientConn
+}
+
+// Start launch database, apply migrations and serve users service
+// configured the same way as cmd/run. ErrUnavailable is returned when no
+// postgres can be found.
+func Start(ctx context.Context) (h *Harness, err error) {
+	if _, ok := os.LookupEnv("SECRET"); !ok {
+		os.Setenv("SECRET", defaultSecret)
+	}
+
+	c, err := startCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h = &Harness{cluster: c}
+	defer func() {
+		if err != nil {
+			h.Close()
+			h = nil
+		}
+	}()
+
+	dbConfig, err := database.LoadConfig(c.DSN())
+	if err != nil {
+		return nil, err
+	}
+	h.Pool, err = database.Connect(ctx, dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := migrate.Load(migrationsfs.FS)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate.New(h.Pool, migrations).Up(ctx); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return nil, err
+	}
+
+	pp, err := passwordpolicy.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	h.Queries = db.New(h.Pool)
+	token.SetRevocationStore(revocation.NewPostgres(h.Queries))
+
+	lis := bufconn.Listen(1 << 20)
+	h.server = grpc.NewServer(grpc.UnaryInterceptor(policy.UnaryServerInterceptor()))
+	service := svc.New(h.Queries, database.NewTxRunner(h.Pool, h.Queries, dbConfig), notifier.NewLog(), pp)
+	pbusers.RegisterUserServiceServer(h.server, service)
+	go h.server.Serve(lis)
+
+	h.conn, err = grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	h.Client = pbusers.NewUserServiceClient(h.conn)
+	return h, nil
+}
+
+// Reset remove every row except applied migration version so next test
+// start from empty database
+func (h *Harness) Reset(ctx context.Context) error {
+	rows, err := h.Pool.Query(ctx, `
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = 'public' AND tablename <> 'schema_migrations'
+	`)
+	if err != nil {
+		return err
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	for i, table := range tables {
+		tables[i] = pgx.Identifier{table}.Sanitize()
+	}
+	_, err = h.Pool.Exec(ctx, fmt.Sprintf("TRUNCATE %s CASCADE", strings.Join(tables, ", ")))
+	return err
+}
+
+// Close stop service and throw database away
+func (h *Harness) Close() error {
+	if h.conn != nil {
+		h.conn.Close()
+	}
+	if h.server != nil {
+		h.server.Stop()
+	}
+	token.SetRevocationStore(nil)
+	if h.Pool != nil {
+		h.Pool.Close()
+	}
+	return h.cluster.Stop()
+}
@@ -0,0 +1,238 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrUnavailable returned when neither postgres binary nor external test
+// database can be found. Integration tests are skipped on such machine.
+var ErrUnavailable = errors.New("harness: postgres is not available, install it or set TEST_DSN")
+
+const (
+	// EnvPostgresBin is directory containing initdb and postgres binary
+	EnvPostgresBin = "POSTGRES_BIN"
+	// EnvTestDSN is external server used when postgres binary is not
+	// installed. Throwaway database is created inside it.
+	EnvTestDSN = "TEST_DSN"
+)
+
+// cluster is throwaway database used by harness
+type cluster interface {
+	DSN() string
+	Stop() error
+}
+
+// startCluster launch local postgres binary when found, otherwise create
+// throwaway database in TEST_DSN server
+func startCluster(ctx context.Context) (cluster, error) {
+	bin, err := findPostgres()
+	if err == nil {
+		return startLocal(ctx, bin)
+	}
+	if !errors.Is(err, ErrUnavailable) {
+		return nil, err
+	}
+	if dsn, ok := os.LookupEnv(EnvTestDSN); ok && dsn != "" {
+		return createExternal(ctx, dsn)
+	}
+	return nil, ErrUnavailable
+}
+
+// findPostgres return directory containing initdb and postgres binary.
+// POSTGRES_BIN take precedence over PATH and common install location.
+func findPostgres() (string, error) {
+	has := func(dir string) bool {
+		for _, name := range []string{"initdb", "postgres"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	if dir, ok := os.LookupEnv(EnvPostgresBin); ok && dir != "" {
+		if !has(dir) {
+			return "", fmt.Errorf("harness: initdb or postgres not found in %s", dir)
+		}
+		return dir, nil
+	}
+	if path, err := exec.LookPath("initdb"); err == nil && has(filepath.Dir(path)) {
+		return filepath.Dir(path), nil
+	}
+
+	// Debian install versioned binary outside PATH, prefer newest one
+	dirs, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	dirs = append(dirs, "/usr/local/pgsql/bin", "/opt/homebrew/bin", "/usr/local/bin")
+	for _, dir := range dirs {
+		if has(dir) {
+			return dir, nil
+		}
+	}
+	return "", ErrUnavailable
+}
+
+// local is postgres server owned by harness running in temporary directory
+type local struct {
+	dir  string
+	port int
+	cmd  *exec.Cmd
+	done chan error
+}
+
+func startLocal(ctx context.Context, bin string) (*local, error) {
+	dir, err := os.MkdirTemp("", "users-harness-")
+	if err != nil {
+		return nil, err
+	}
+	data := filepath.Join(dir, "data")
+
+	// Trust authentication is fine since server only listen on loopback
+	// and unix socket inside temporary directory
+	initdb := exec.CommandContext(ctx, filepath.Join(bin, "initdb"),
+		"-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync")
+	if out, err := initdb.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("harness: initdb: %w\n%s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	// Durability is not needed by throwaway database
+	var logs bytes.Buffer
+	cmd := exec.Command(filepath.Join(bin, "postgres"),
+		"-D", data,
+		"-p", strconv.Itoa(port),
+		"-k", dir,
+		"-c", "listen_addresses=127.0.0.1",
+		"-c", "fsync=off",
+		"-c", "synchronous_commit=off",
+		"-c", "full_page_writes=off",
+	)
+	cmd.Stdout = &logs
+	cmd.Stderr = &logs
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("harness: start postgres: %w", err)
+	}
+	l := &local{dir: dir, port: port, cmd: cmd, done: make(chan error, 1)}
+	go func() { l.done <- cmd.Wait() }()
+
+	if err := l.waitReady(ctx); err != nil {
+		l.Stop()
+		return nil, fmt.Errorf("%w\n%s", err, logs.String())
+	}
+	return l, nil
+}
+
+func (l *local) DSN() string {
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", l.port)
+}
+
+// waitReady poll server until it accept connection or exit
+func (l *local) waitReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for {
+		conn, err := pgx.Connect(ctx, l.DSN())
+		if err == nil {
+			return conn.Close(ctx)
+		}
+		select {
+		case err := <-l.done:
+			l.done <- err
+			return fmt.Errorf("harness: postgres exited: %v", err)
+		case <-ctx.Done():
+			return fmt.Errorf("harness: postgres not ready: %w", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Stop shut server down using fast shutdown and remove its data
+func (l *local) Stop() error {
+	defer os.RemoveAll(l.dir)
+	if err := l.cmd.Process.Signal(os.Interrupt); err != nil {
+		return nil
+	}
+	select {
+	case <-l.done:
+	case <-time.After(10 * time.Second):
+		_ = l.cmd.Process.Kill()
+		<-l.done
+	}
+	return nil
+}
+
+func freePort() (int, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("harness: find free port: %w", err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port, nil
+}
+
+// external is throwaway database created in server owned by someone else
+type external struct {
+	admin string
+	dsn   string
+	name  string
+}
+
+func createExternal(ctx context.Context, dsn string) (*external, error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("harness: connect %s: %w", EnvTestDSN, err)
+	}
+	defer conn.Close(ctx)
+
+	name := fmt.Sprintf("users_harness_%d_%d", os.Getpid(), time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+pgx.Identifier{name}.Sanitize()); err != nil {
+		return nil, fmt.Errorf("harness: create database: %w", err)
+	}
+	return &external{admin: dsn, dsn: withDatabase(dsn, name), name: name}, nil
+}
+
+// withDatabase return dsn connecting to database name instead
+func withDatabase(dsn, name string) string {
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		u.Path = "/" + name
+		return u.String()
+	}
+	// Later keyword override earlier one in keyword/value form
+	return dsn + " dbname=" + name
+}
+
+func (e *external) DSN() string {
+	return e.dsn
+}
+
+func (e *external) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := pgx.Connect(ctx, e.admin)
+	if err != nil {
+		return fmt.Errorf("harness: connect %s: %w", EnvTestDSN, err)
+	}
+	defer conn.Close(ctx)
+	_, err = conn.Exec(ctx, "DROP DATABASE IF EXISTS "+pgx.Identifier{e.name}.Sanitize()+" WITH (FORCE)")
+	return err
+}
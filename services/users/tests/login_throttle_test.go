@@ -0,0 +1,86 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginLockout(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	email := fmt.Sprintf("user%s@email.com", uuid.NewString())
+	res, err := service.CreateOneUser(ctx, &pbusers.CreateOneUserRequest{
+		Email:    email,
+		Password: "secretpassword",
+		Role:     pbusers.UserRole_Student,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = service.DeleteHardOneUser(ctx, &pbusers.DeleteHardOneUserRequest{Id: res.Id})
+	})
+
+	// x-real-ip is ignored since harness peer is not trusted proxy,
+	// database is reset after every test so ip throttle start empty
+	clientCtx := context.Background()
+
+	t.Run("Should lock account after repeated failure", func(t *testing.T) {
+		for range 5 {
+			_, err := service.LoginUser(clientCtx, &pbusers.LoginUserRequest{
+				Email:    email,
+				Password: "wrongpassword",
+			})
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("unexpected error %v", err)
+			}
+		}
+
+		_, err := service.LoginUser(clientCtx, &pbusers.LoginUserRequest{
+			Email:    email,
+			Password: "secretpassword",
+		})
+		st := status.Convert(err)
+		if st.Code() != codes.ResourceExhausted {
+			t.Fatalf("unexpected error %v", err)
+		}
+		var retryInfo *epb.RetryInfo
+		for _, d := range st.Details() {
+			if info, ok := d.(*epb.RetryInfo); ok {
+				retryInfo = info
+			}
+		}
+		if retryInfo == nil || retryInfo.GetRetryDelay().AsDuration() <= 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should login after unlocked by staff", func(t *testing.T) {
+		if _, err := service.UnlockUser(ctx, &pbusers.UnlockUserRequest{Id: res.Id}); err != nil {
+			t.Fatal(err)
+		}
+		_, err := service.LoginUser(clientCtx, &pbusers.LoginUserRequest{
+			Email:    email,
+			Password: "secretpassword",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should deny unlock by non staff", func(t *testing.T) {
+		_, tokens := loginDummyUser(t, service)
+		_, err := service.UnlockUser(userContext(tokens.AccessToken), &pbusers.UnlockUserRequest{Id: res.Id})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fail()
+		}
+	})
+}
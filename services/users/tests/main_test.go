@@ -0,0 +1,48 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/tests/harness"
+)
+
+var (
+	h    *harness.Harness
+	hErr error
+)
+
+func TestMain(m *testing.M) {
+	h, hErr = harness.Start(context.Background())
+	if hErr != nil && !errors.Is(hErr, harness.ErrUnavailable) {
+		log.Fatalf("unable to start harness: %s", hErr.Error())
+	}
+
+	code := m.Run()
+	if h != nil {
+		if err := h.Close(); err != nil {
+			log.Printf("error: failed to close harness. %s\n", err.Error())
+		}
+	}
+	os.Exit(code)
+}
+
+// createService return client of service started by harness and empty
+// database once test finished. Test is skipped when postgres is not
+// available.
+func createService(tb testing.TB) pbusers.UserServiceClient {
+	tb.Helper()
+	if h == nil {
+		tb.Skip(hErr.Error())
+	}
+	tb.Cleanup(func() {
+		if err := h.Reset(context.Background()); err != nil {
+			tb.Errorf("unable to reset database: %s", err.Error())
+		}
+	})
+	return h.Client
+}
@@ -0,0 +1,123 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/totp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTotp(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	email := fmt.Sprintf("user%s@email.com", uuid.NewString())
+	created, err := service.CreateOneUser(ctx, &pbusers.CreateOneUserRequest{
+		Email:    email,
+		Password: "secretpassword",
+		Role:     pbusers.UserRole_Teacher,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = service.DeleteHardOneUser(ctx, &pbusers.DeleteHardOneUserRequest{Id: created.Id})
+	})
+
+	login := func() (*pbusers.LoginUserResponse, error) {
+		return service.LoginUser(context.TODO(), &pbusers.LoginUserRequest{
+			Email:    email,
+			Password: "secretpassword",
+		})
+	}
+
+	var recoveryCodes []string
+
+	t.Run("Should enroll and confirm totp", func(t *testing.T) {
+		tokens, err := login()
+		if err != nil {
+			t.Fatal(err)
+		}
+		userCtx := userContext(tokens.AccessToken)
+
+		enrollment, err := service.EnrollTotpUser(userCtx, &pbusers.EnrollTotpUserRequest{Id: created.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := service.ConfirmTotpUser(userCtx, &pbusers.ConfirmTotpUserRequest{
+			Id:   created.Id,
+			Code: code,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.RecoveryCodes) == 0 {
+			t.Fatal("no recovery code returned")
+		}
+		recoveryCodes = res.RecoveryCodes
+	})
+
+	t.Run("Should challenge login and accept recovery code once", func(t *testing.T) {
+		if len(recoveryCodes) == 0 {
+			t.Skip("totp not enrolled")
+		}
+		challenge, err := login()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !challenge.MfaRequired || challenge.MfaToken == "" || challenge.AccessToken != "" {
+			t.Fatalf("unexpected login response %v", challenge)
+		}
+
+		_, err = service.VerifyMfaUser(context.TODO(), &pbusers.VerifyMfaUserRequest{
+			MfaToken: challenge.MfaToken,
+			Code:     "000000",
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+
+		res, err := service.VerifyMfaUser(context.TODO(), &pbusers.VerifyMfaUserRequest{
+			MfaToken: challenge.MfaToken,
+			Code:     recoveryCodes[0],
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.AccessToken == "" || res.RefreshToken == "" {
+			t.Fail()
+		}
+
+		// Challenge token is single use
+		_, err = service.VerifyMfaUser(context.TODO(), &pbusers.VerifyMfaUserRequest{
+			MfaToken: challenge.MfaToken,
+			Code:     recoveryCodes[1],
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should not challenge after disabled by staff", func(t *testing.T) {
+		if _, err := service.DisableTotpUser(ctx, &pbusers.DisableTotpUserRequest{Id: created.Id}); err != nil {
+			t.Fatal(err)
+		}
+		res, err := login()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.MfaRequired || res.AccessToken == "" {
+			t.Fail()
+		}
+	})
+}
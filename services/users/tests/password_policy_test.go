@@ -0,0 +1,99 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// violationReasons return reason of every password field violation
+func violationReasons(err error) []string {
+	reasons := make([]string, 0)
+	for _, detail := range status.Convert(err).Details() {
+		if br, ok := detail.(*epb.BadRequest); ok {
+			for _, fv := range br.FieldViolations {
+				if fv.Field == "Password" {
+					reasons = append(reasons, fv.Reason)
+				}
+			}
+		}
+	}
+	return reasons
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	service := createService(t)
+	staffCtx := staffContext(t)
+
+	t.Run("Should reject password containing email", func(t *testing.T) {
+		local := "user" + uuid.NewString()[:8]
+		_, err := service.CreateOneUser(staffCtx, &pbusers.CreateOneUserRequest{
+			Email:    fmt.Sprintf("%s@email.com", local),
+			Password: local + "secret",
+			Role:     pbusers.UserRole_Student,
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+		if reasons := violationReasons(err); len(reasons) != 1 || reasons[0] != "password_email" {
+			t.Errorf("unexpected violations %v", reasons)
+		}
+	})
+
+	t.Run("Should reject reused password on update", func(t *testing.T) {
+		id := createDummyUser(t, staffCtx, service)
+		_, err := service.UpdateOnePasswordUser(staffCtx, &pbusers.UpdateOnePasswordUserRequest{
+			Id:       id,
+			Password: "secretpassword",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+		if reasons := violationReasons(err); len(reasons) != 1 || reasons[0] != "password_reused" {
+			t.Errorf("unexpected violations %v", reasons)
+		}
+
+		_, err = service.UpdateOnePasswordUser(staffCtx, &pbusers.UpdateOnePasswordUserRequest{
+			Id:       id,
+			Password: "newsecretpassword",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = service.UpdateOnePasswordUser(staffCtx, &pbusers.UpdateOnePasswordUserRequest{
+			Id:       id,
+			Password: "secretpassword",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected previous password rejected, got %v", err)
+		}
+	})
+
+	t.Run("Should keep reset token when password rejected", func(t *testing.T) {
+		id, _ := loginDummyUser(t, service)
+		secret := createResetToken(t, id, time.Minute)
+
+		_, err := service.ConfirmPasswordReset(context.TODO(), &pbusers.ConfirmPasswordResetRequest{
+			Token:    secret,
+			Password: "secretpassword",
+		})
+		if reasons := violationReasons(err); len(reasons) != 1 || reasons[0] != "password_reused" {
+			t.Fatalf("unexpected violations %v", reasons)
+		}
+
+		_, err = service.ConfirmPasswordReset(context.TODO(), &pbusers.ConfirmPasswordResetRequest{
+			Token:    secret,
+			Password: "newsecretpassword",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
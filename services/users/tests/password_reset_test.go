@@ -0,0 +1,92 @@
+package user_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// createResetToken insert reset token for user directly in database
+// since the token itself is only delivered through notifier
+func createResetToken(t *testing.T, userID string, ttl time.Duration) string {
+	t.Helper()
+	secret := uuid.NewString()
+	sum := sha256.Sum256([]byte(secret))
+	_, err := connectDB(t).CreateOnePasswordResetToken(context.Background(), &db.CreateOnePasswordResetTokenParams{
+		ID:        uuid.New(),
+		UserID:    uuid.MustParse(userID),
+		TokenHash: hex.EncodeToString(sum[:]),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("unable to create reset token: %s", err.Error())
+	}
+	return secret
+}
+
+func TestPasswordReset(t *testing.T) {
+	service := createService(t)
+
+	t.Run("Should success request reset for unknown email", func(t *testing.T) {
+		_, err := service.RequestPasswordReset(context.TODO(), &pbusers.RequestPasswordResetRequest{
+			Email: "unknown" + uuid.NewString() + "@email.com",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should success confirm reset once", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		secret := createResetToken(t, id, time.Minute)
+
+		res, err := service.ConfirmPasswordReset(context.TODO(), &pbusers.ConfirmPasswordResetRequest{
+			Token:    secret,
+			Password: "newsecretpassword",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Id != id {
+			t.Fail()
+		}
+
+		// Existing session is revoked
+		_, err = service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+
+		// Token can not be reused
+		_, err = service.ConfirmPasswordReset(context.TODO(), &pbusers.ConfirmPasswordResetRequest{
+			Token:    secret,
+			Password: "othersecretpassword",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should reject expired token", func(t *testing.T) {
+		id, _ := loginDummyUser(t, service)
+		secret := createResetToken(t, id, -time.Minute)
+		_, err := service.ConfirmPasswordReset(context.TODO(), &pbusers.ConfirmPasswordResetRequest{
+			Token:    secret,
+			Password: "newsecretpassword",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package user_test
+
+import (
+	"testing"
+
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPolicy(t *testing.T) {
+	service := createService(t)
+
+	t.Run("Should unauthenticated without token", func(t *testing.T) {
+		_, err := service.GetManyUser(t.Context(), &pbusers.GetManyUserRequest{PageSize: 10})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should permission denied for student hard delete", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		ctx := userContext(tokens.AccessToken)
+		_, err := service.DeleteHardOneUser(ctx, &pbusers.DeleteHardOneUserRequest{Id: id})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should success student update own password", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		ctx := userContext(tokens.AccessToken)
+		_, err := service.UpdateOnePasswordUser(ctx, &pbusers.UpdateOnePasswordUserRequest{
+			Id:              id,
+			Password:        "newsecretpassword",
+			CurrentPassword: "secretpassword",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should permission denied for student update own role", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		ctx := userContext(tokens.AccessToken)
+		_, err := service.UpdateOneRoleUser(ctx, &pbusers.UpdateOneRoleUserRequest{
+			Id:   id,
+			Role: pbusers.UserRole_Staff,
+		})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fail()
+		}
+	})
+	t.Run("Should reject token after role changed", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		_, err := service.UpdateOneRoleUser(staffContext(t), &pbusers.UpdateOneRoleUserRequest{
+			Id:   id,
+			Role: pbusers.UserRole_Teacher,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := userContext(tokens.AccessToken)
+		_, err = service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: id})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func loginDummyUser(t *testing.T, service pbusers.UserServiceClient) (string, *pbusers.LoginUserResponse) {
+	t.Helper()
+	email := fmt.Sprintf("user%s@email.com", uuid.NewString())
+	ctx := staffContext(t)
+	res, err := service.CreateOneUser(ctx, &pbusers.CreateOneUserRequest{
+		Email:    email,
+		Password: "secretpassword",
+		Role:     pbusers.UserRole_Student,
+	})
+	if err != nil {
+		t.Fatalf("unable to create dummy user: %s", err.Error())
+	}
+	t.Cleanup(func() {
+		_, _ = service.DeleteHardOneUser(ctx, &pbusers.DeleteHardOneUserRequest{Id: res.Id})
+	})
+
+	tokens, err := service.LoginUser(context.TODO(), &pbusers.LoginUserRequest{
+		Email:    email,
+		Password: "secretpassword",
+	})
+	if err != nil {
+		t.Fatalf("unable to login dummy user: %s", err.Error())
+	}
+	return res.Id, tokens
+}
+
+func TestRefreshToken(t *testing.T) {
+	service := createService(t)
+
+	t.Run("Should success refresh token", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		res, err := service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := service.VerifyTokenUser(context.TODO(), &pbusers.VerifyTokenUserRequest{
+			AccessToken: res.AccessToken,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.Sub != id || claims.Role != "student" || claims.Sid == "" {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should revoke session on refresh token reuse", func(t *testing.T) {
+		_, tokens := loginDummyUser(t, service)
+		rotated, err := service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reuse old refresh token
+		_, err = service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+
+		// Rotated token is revoked as well
+		_, err = service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: rotated.RefreshToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should reject access token", func(t *testing.T) {
+		_, tokens := loginDummyUser(t, service)
+		_, err := service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.AccessToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should reject refresh token on verify", func(t *testing.T) {
+		_, tokens := loginDummyUser(t, service)
+		_, err := service.VerifyTokenUser(context.TODO(), &pbusers.VerifyTokenUserRequest{
+			AccessToken: tokens.RefreshToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRehashOnLogin(t *testing.T) {
+	service := createService(t)
+	q := connectDB(t)
+	ctx := context.Background()
+
+	weak := &hasher.Config{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	argon2Hash, err := hasher.GenerateFromPassword("secretpassword", weak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("secretpassword"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"outdated argon2id": argon2Hash,
+		"imported bcrypt":   string(bcryptHash),
+	}
+	for name, passwordHash := range cases {
+		t.Run(name, func(t *testing.T) {
+			id := uuid.New()
+			email := fmt.Sprintf("user%s@email.com", uuid.NewString())
+			_, err = q.CreateOneUser(ctx, &db.CreateOneUserParams{
+				ID:           id,
+				Email:        email,
+				Role:         db.UserRoleStudent,
+				PasswordHash: passwordHash,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _, _ = q.DeleteHardOneUser(ctx, id) })
+
+			_, err = service.LoginUser(ctx, &pbusers.LoginUserRequest{
+				Email:    email,
+				Password: "secretpassword",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			creds, err := q.GetOneCredentialUserByEmail(ctx, email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if creds.PasswordHash == passwordHash || !strings.HasPrefix(creds.PasswordHash, "$argon2id$") {
+				t.Errorf("password was not rehashed: %s", creds.PasswordHash)
+			}
+			if err := hasher.CompareHashWithPassword(creds.PasswordHash, "secretpassword"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
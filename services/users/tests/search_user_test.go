@@ -0,0 +1,78 @@
+package user_test
+
+import (
+	"testing"
+
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSearchUser(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	for _, u := range []struct {
+		email string
+		role  pbusers.UserRole
+	}{
+		{"john.doe@school.com", pbusers.UserRole_Student},
+		{"jane.doe@school.com", pbusers.UserRole_Teacher},
+		{"bjohnson@school.com", pbusers.UserRole_Student},
+	} {
+		_, err := service.CreateOneUser(ctx, &pbusers.CreateOneUserRequest{
+			Email:    u.email,
+			Password: "secretpassword",
+			Role:     u.role,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Should rank word prefix first", func(t *testing.T) {
+		res, err := service.SearchUsers(ctx, &pbusers.SearchUsersRequest{Query: "john"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Results) != 2 || res.Results[0].User.Email != "john.doe@school.com" {
+			t.Fatalf("expected john.doe first of 2 results, got %v", res.Results)
+		}
+		if res.Results[0].Rank < res.Results[1].Rank {
+			t.Fatalf("expected descending rank, got %v", res.Results)
+		}
+		if res.Results[0].Highlight != "<mark>john</mark>.doe@school.com" {
+			t.Fatalf("unexpected highlight %q", res.Results[0].Highlight)
+		}
+	})
+
+	t.Run("Should tolerate misspelled query", func(t *testing.T) {
+		res, err := service.SearchUsers(ctx, &pbusers.SearchUsersRequest{Query: "bjohnsom"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Results) == 0 || res.Results[0].User.Email != "bjohnson@school.com" {
+			t.Fatalf("expected bjohnson by similarity, got %v", res.Results)
+		}
+	})
+
+	t.Run("Should filter by role", func(t *testing.T) {
+		res, err := service.SearchUsers(ctx, &pbusers.SearchUsersRequest{
+			Query: "doe",
+			Roles: []pbusers.UserRole{pbusers.UserRole_Teacher},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Results) != 1 || res.Results[0].User.Email != "jane.doe@school.com" {
+			t.Fatalf("expected jane.doe only, got %v", res.Results)
+		}
+	})
+
+	t.Run("Should invalid argument error", func(t *testing.T) {
+		_, err := service.SearchUsers(ctx, &pbusers.SearchUsersRequest{Query: "%_"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+}
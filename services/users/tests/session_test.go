@@ -0,0 +1,78 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSession(t *testing.T) {
+	service := createService(t)
+
+	t.Run("Should list and revoke own session", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		ctx := userContext(tokens.AccessToken)
+
+		res, err := service.ListSessionsUser(ctx, &pbusers.ListSessionsUserRequest{Id: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Sessions) != 1 {
+			t.Fatalf("expected 1 session, got %d", len(res.Sessions))
+		}
+
+		_, err = service.RevokeSessionUser(ctx, &pbusers.RevokeSessionUserRequest{
+			Id:        id,
+			SessionId: res.Sessions[0].Id,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should reject refresh after logout", func(t *testing.T) {
+		_, tokens := loginDummyUser(t, service)
+		_, err := service.LogoutUser(context.TODO(), &pbusers.LogoutUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = service.RefreshTokenUser(context.TODO(), &pbusers.RefreshTokenUserRequest{
+			RefreshToken: tokens.RefreshToken,
+		})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should not found revoking other user session", func(t *testing.T) {
+		id, tokens := loginDummyUser(t, service)
+		_, otherTokens := loginDummyUser(t, service)
+		otherClaims, err := service.VerifyTokenUser(context.TODO(), &pbusers.VerifyTokenUserRequest{
+			AccessToken: otherTokens.AccessToken,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = service.RevokeSessionUser(userContext(tokens.AccessToken), &pbusers.RevokeSessionUserRequest{
+			Id:        id,
+			SessionId: otherClaims.Sid,
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func createDummyUser(t *testing.T, ctx context.Context, service pbusers.UserServiceClient) string {
+	t.Helper()
+	res, err := service.CreateOneUser(ctx, &pbusers.CreateOneUserRequest{
+		Email:    fmt.Sprintf("user%s@email.com", uuid.NewString()),
+		Password: "secretpassword",
+		Role:     pbusers.UserRole_Student,
+	})
+	if err != nil {
+		t.Fatalf("unable to create dummy user: %s", err.Error())
+	}
+	t.Cleanup(func() {
+		_, _ = service.DeleteHardOneUser(ctx, &pbusers.DeleteHardOneUserRequest{Id: res.Id})
+	})
+	return res.Id
+}
+
+func TestGetUser(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	t.Run("Should success get user", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		res, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Id != id || res.Role != "student" {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should not found error", func(t *testing.T) {
+		_, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: uuid.NewString()})
+		if status.Code(err) != codes.NotFound {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should invalid argument error", func(t *testing.T) {
+		_, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: "invalid"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	t.Run("Should success update email", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		_, err := service.UpdateOneEmailUser(ctx, &pbusers.UpdateOneEmailUserRequest{
+			Id:    id,
+			Email: fmt.Sprintf("user%s@email.com", uuid.NewString()),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Should conflict error on taken email", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		other := createDummyUser(t, ctx, service)
+		otherUser, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: other})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = service.UpdateOneEmailUser(ctx, &pbusers.UpdateOneEmailUserRequest{
+			Id:    id,
+			Email: otherUser.Email,
+		})
+		if status.Code(err) != codes.AlreadyExists {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should validation error on short password", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		_, err := service.UpdateOnePasswordUser(ctx, &pbusers.UpdateOnePasswordUserRequest{
+			Id:       id,
+			Password: "short",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fail()
+		}
+	})
+
+	t.Run("Should success update role", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		_, err := service.UpdateOneRoleUser(ctx, &pbusers.UpdateOneRoleUserRequest{
+			Id:   id,
+			Role: pbusers.UserRole_Teacher,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestDeleteSoftUser(t *testing.T) {
+	service := createService(t)
+	ctx := staffContext(t)
+
+	t.Run("Should hide user after soft delete", func(t *testing.T) {
+		id := createDummyUser(t, ctx, service)
+		if _, err := service.DeleteSoftOneUser(ctx, &pbusers.DeleteSoftOneUserRequest{Id: id}); err != nil {
+			t.Fatal(err)
+		}
+		_, err := service.GetOneUser(ctx, &pbusers.GetOneUserRequest{Id: id})
+		if status.Code(err) != codes.NotFound {
+			t.Fail()
+		}
+		_, err = service.DeleteSoftOneUser(ctx, &pbusers.DeleteSoftOneUserRequest{Id: id})
+		if status.Code(err) != codes.NotFound {
+			t.Fail()
+		}
+	})
+}
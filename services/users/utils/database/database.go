@@ -0,0 +1,198 @@
+// Package database create PostgreSQL connection pool used by users
+// service
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Environment variables read by LoadConfig
+const (
+	EnvMaxConns          = "DB_MAX_CONNS"
+	EnvMinConns          = "DB_MIN_CONNS"
+	EnvMaxConnLifetime   = "DB_MAX_CONN_LIFETIME"
+	EnvMaxConnIdleTime   = "DB_MAX_CONN_IDLE_TIME"
+	EnvHealthCheckPeriod = "DB_HEALTH_CHECK_PERIOD"
+	EnvStatementCache    = "DB_STATEMENT_CACHE_MODE"
+	EnvConnectTimeout    = "DB_CONNECT_TIMEOUT"
+	EnvTxIsolation       = "DB_TX_ISOLATION"
+	EnvTxMaxRetries      = "DB_TX_MAX_RETRIES"
+)
+
+// DefaultConnectTimeout is how long Connect retry before giving up
+const DefaultConnectTimeout = time.Minute
+
+// Backoff bound between connection attempt
+const (
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 10 * time.Second
+)
+
+var execModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// Config hold pool config along with startup connect timeout and
+// transaction setting
+type Config struct {
+	Pool           *pgxpool.Config
+	ConnectTimeout time.Duration
+	TxIsolation    pgx.TxIsoLevel
+	TxMaxRetries   int
+}
+
+// LoadConfig parse dsn and apply DB_* environment variables on top of
+// it. Pool setting not set in environment keep value from dsn or pgxpool
+// default.
+func LoadConfig(dsn string) (*Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("database: parse dsn: %w", err)
+	}
+	c := &Config{
+		Pool:           poolConfig,
+		ConnectTimeout: DefaultConnectTimeout,
+		TxIsolation:    DefaultTxIsolation,
+		TxMaxRetries:   DefaultTxMaxRetries,
+	}
+
+	for env, dst := range map[string]*int32{
+		EnvMaxConns: &poolConfig.MaxConns,
+		EnvMinConns: &poolConfig.MinConns,
+	} {
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("database: invalid %s", env)
+		}
+		*dst = int32(n)
+	}
+
+	for env, dst := range map[string]*time.Duration{
+		EnvMaxConnLifetime:   &poolConfig.MaxConnLifetime,
+		EnvMaxConnIdleTime:   &poolConfig.MaxConnIdleTime,
+		EnvHealthCheckPeriod: &poolConfig.HealthCheckPeriod,
+		EnvConnectTimeout:    &c.ConnectTimeout,
+	} {
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("database: invalid %s", env)
+		}
+		*dst = d
+	}
+
+	if value, ok := os.LookupEnv(EnvStatementCache); ok && value != "" {
+		mode, ok := execModes[value]
+		if !ok {
+			return nil, fmt.Errorf("database: unknown %s %q", EnvStatementCache, value)
+		}
+		poolConfig.ConnConfig.DefaultQueryExecMode = mode
+	}
+
+	if value, ok := os.LookupEnv(EnvTxIsolation); ok && value != "" {
+		level, err := parseIsolation(value)
+		if err != nil {
+			return nil, err
+		}
+		c.TxIsolation = level
+	}
+	if value, ok := os.LookupEnv(EnvTxMaxRetries); ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("database: invalid %s", EnvTxMaxRetries)
+		}
+		c.TxMaxRetries = n
+	}
+
+	if poolConfig.MaxConns == 0 || poolConfig.MinConns > poolConfig.MaxConns {
+		return nil, fmt.Errorf("database: %s must be greater than zero and not less than %s", EnvMaxConns, EnvMinConns)
+	}
+	return c, nil
+}
+
+// Connect create pool and wait until database is reachable. Failed
+// attempt is retried with exponential backoff until ConnectTimeout.
+func Connect(ctx context.Context, c *Config) (*pgxpool.Pool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ConnectTimeout)
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		pool, err := pgxpool.NewWithConfig(ctx, c.Pool)
+		if err == nil {
+			if err = pool.Ping(ctx); err == nil {
+				return pool, nil
+			}
+			pool.Close()
+		}
+
+		wait := backoff(attempt)
+		log.Printf("error: database is not reachable, retrying in %s. %s\n", wait, err.Error())
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("database: unable to connect: %w", err)
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff return delay before next attempt
+func backoff(attempt int) time.Duration {
+	d := minBackoff
+	for range attempt {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Stats is snapshot of pool statistics
+type Stats struct {
+	MaxConns             int32 `json:"max_conns"`
+	TotalConns           int32 `json:"total_conns"`
+	IdleConns            int32 `json:"idle_conns"`
+	AcquiredConns        int32 `json:"acquired_conns"`
+	ConstructingConns    int32 `json:"constructing_conns"`
+	AcquireCount         int64 `json:"acquire_count"`
+	EmptyAcquireCount    int64 `json:"empty_acquire_count"`
+	CanceledAcquireCount int64 `json:"canceled_acquire_count"`
+	AcquireDurationMs    int64 `json:"acquire_duration_ms"`
+	NewConnsCount        int64 `json:"new_conns_count"`
+}
+
+// PoolStats return statistics of pool
+func PoolStats(pool *pgxpool.Pool) Stats {
+	s := pool.Stat()
+	return Stats{
+		MaxConns:             s.MaxConns(),
+		TotalConns:           s.TotalConns(),
+		IdleConns:            s.IdleConns(),
+		AcquiredConns:        s.AcquiredConns(),
+		ConstructingConns:    s.ConstructingConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		CanceledAcquireCount: s.CanceledAcquireCount(),
+		AcquireDurationMs:    s.AcquireDuration().Milliseconds(),
+		NewConnsCount:        s.NewConnsCount(),
+	}
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+)
+
+// PostgreSQL error codes handled by transaction runner and service
+const (
+	CodeUniqueViolation      = "23505"
+	CodeSerializationFailure = "40001"
+	CodeDeadlockDetected     = "40P01"
+)
+
+// Default transaction setting
+const (
+	DefaultTxIsolation  = pgx.Serializable
+	DefaultTxMaxRetries = 3
+)
+
+var isolationLevels = map[string]pgx.TxIsoLevel{
+	"read_committed":  pgx.ReadCommitted,
+	"repeatable_read": pgx.RepeatableRead,
+	"serializable":    pgx.Serializable,
+}
+
+// TxRunner run function atomically within single transaction
+type TxRunner interface {
+	// RunTx run fn using queries bound to new transaction. Transaction is
+	// committed when fn return nil and rolled back otherwise.
+	RunTx(ctx context.Context, fn func(q db.Querier) error) error
+}
+
+type txRunner struct {
+	pool       *pgxpool.Pool
+	q          *db.Queries
+	isolation  pgx.TxIsoLevel
+	maxRetries int
+}
+
+// NewTxRunner create runner starting transaction from pool with
+// isolation of c. Transaction failing on serialization failure or
+// deadlock is retried up to c.TxMaxRetries times.
+func NewTxRunner(pool *pgxpool.Pool, q *db.Queries, c *Config) TxRunner {
+	return &txRunner{
+		pool:       pool,
+		q:          q,
+		isolation:  c.TxIsolation,
+		maxRetries: c.TxMaxRetries,
+	}
+}
+
+func (r *txRunner) RunTx(ctx context.Context, fn func(q db.Querier) error) error {
+	for attempt := 0; ; attempt++ {
+		err := pgx.BeginTxFunc(ctx, r.pool, pgx.TxOptions{IsoLevel: r.isolation}, func(tx pgx.Tx) error {
+			return fn(r.q.WithTx(tx))
+		})
+		if err == nil || !IsRetryable(err) || attempt >= r.maxRetries {
+			return err
+		}
+
+		// Jitter so conflicting transactions do not collide again
+		wait := time.Duration(attempt+1)*10*time.Millisecond + rand.N(10*time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func pgCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// IsRetryable report whether transaction failed with err may succeed
+// when run again
+func IsRetryable(err error) bool {
+	code := pgCode(err)
+	return code == CodeSerializationFailure || code == CodeDeadlockDetected
+}
+
+// IsUniqueViolation report whether err is caused by unique constraint
+func IsUniqueViolation(err error) bool {
+	return pgCode(err) == CodeUniqueViolation
+}
+
+func parseIsolation(value string) (pgx.TxIsoLevel, error) {
+	level, ok := isolationLevels[value]
+	if !ok {
+		return "", fmt.Errorf("database: unknown %s %q", EnvTxIsolation, value)
+	}
+	return level, nil
+}
@@ -0,0 +1,400 @@
+package memdb
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+)
+
+// Sessions
+
+func (s *Store) CreateOneSession(ctx context.Context, arg *db.CreateOneSessionParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "sessions_user_id_fkey"); err != nil {
+		return uuid.Nil, err
+	}
+	if _, ok := s.t.sessions[arg.ID]; ok {
+		return uuid.Nil, uniqueViolation("sessions_pkey")
+	}
+	s.t.sessions[arg.ID] = db.Session{
+		ID:        arg.ID,
+		FamilyID:  arg.FamilyID,
+		UserID:    arg.UserID,
+		CreatedAt: now(),
+		ExpiresAt: arg.ExpiresAt,
+	}
+	return arg.ID, nil
+}
+
+func (s *Store) GetOneSession(ctx context.Context, id uuid.UUID) (*db.GetOneSessionRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.sessions[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &db.GetOneSessionRow{
+		ID:        r.ID,
+		FamilyID:  r.FamilyID,
+		UserID:    r.UserID,
+		ExpiresAt: r.ExpiresAt,
+		RotatedAt: r.RotatedAt,
+		RevokedAt: r.RevokedAt,
+	}, nil
+}
+
+// activeSession report whether session is neither rotated, revoked nor
+// expired
+func activeSession(r db.Session) bool {
+	return !r.RotatedAt.Valid && !r.RevokedAt.Valid && before(now(), r.ExpiresAt)
+}
+
+func (s *Store) GetManySessionByUser(ctx context.Context, userID uuid.UUID) ([]*db.GetManySessionByUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]db.Session, 0)
+	for _, r := range s.t.sessions {
+		if r.UserID == userID && activeSession(r) {
+			sessions = append(sessions, r)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b db.Session) int {
+		return b.CreatedAt.Time.Compare(a.CreatedAt.Time)
+	})
+
+	items := []*db.GetManySessionByUserRow{}
+	for _, r := range sessions {
+		items = append(items, &db.GetManySessionByUserRow{
+			FamilyID:  r.FamilyID,
+			CreatedAt: r.CreatedAt,
+			ExpiresAt: r.ExpiresAt,
+		})
+	}
+	return items, nil
+}
+
+func (s *Store) RotateOneSession(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.sessions[id]
+	if !ok || !activeSession(r) {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	r.RotatedAt = now()
+	s.t.sessions[id] = r
+	return r.FamilyID, nil
+}
+
+// revokeSessions revoke every unrevoked session matching fn
+func (s *Store) revokeSessions(fn func(r db.Session) bool) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := int64(0)
+	for id, r := range s.t.sessions {
+		if !r.RevokedAt.Valid && fn(r) {
+			r.RevokedAt = now()
+			s.t.sessions[id] = r
+			rows++
+		}
+	}
+	return rows
+}
+
+func (s *Store) RevokeFamilySession(ctx context.Context, arg *db.RevokeFamilySessionParams) (int64, error) {
+	return s.revokeSessions(func(r db.Session) bool {
+		return r.FamilyID == arg.FamilyID && r.UserID == arg.UserID
+	}), nil
+}
+
+func (s *Store) RevokeAllSessionByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.revokeSessions(func(r db.Session) bool {
+		return r.UserID == userID
+	}), nil
+}
+
+// Token revocations
+
+func (s *Store) CreateOneTokenRevocation(ctx context.Context, arg *db.CreateOneTokenRevocationParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.tokenRevocations[arg.Jti]; !ok {
+		s.t.tokenRevocations[arg.Jti] = db.TokenRevocation{Jti: arg.Jti, ExpiresAt: arg.ExpiresAt}
+	}
+	return nil
+}
+
+func (s *Store) CountTokenRevocation(ctx context.Context, jti uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.tokenRevocations[jti]; ok {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (s *Store) DeleteExpiredTokenRevocation(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := int64(0)
+	for jti, r := range s.t.tokenRevocations {
+		if before(r.ExpiresAt, now()) {
+			delete(s.t.tokenRevocations, jti)
+			rows++
+		}
+	}
+	return rows, nil
+}
+
+func (s *Store) UpsertOneUserTokenRevocation(ctx context.Context, arg *db.UpsertOneUserTokenRevocationParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "user_token_revocations_user_id_fkey"); err != nil {
+		return err
+	}
+	r, ok := s.t.userTokenRevocations[arg.UserID]
+	if !ok || before(r.IssuedBefore, arg.IssuedBefore) {
+		r = db.UserTokenRevocation{UserID: arg.UserID, IssuedBefore: arg.IssuedBefore}
+	}
+	s.t.userTokenRevocations[arg.UserID] = r
+	return nil
+}
+
+func (s *Store) GetOneUserTokenRevocation(ctx context.Context, userID uuid.UUID) (pgtype.Timestamptz, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.userTokenRevocations[userID]
+	if !ok {
+		return pgtype.Timestamptz{}, pgx.ErrNoRows
+	}
+	return r.IssuedBefore, nil
+}
+
+// Password reset and email verification tokens
+
+func (s *Store) CreateOnePasswordResetToken(ctx context.Context, arg *db.CreateOnePasswordResetTokenParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "password_reset_tokens_user_id_fkey"); err != nil {
+		return uuid.Nil, err
+	}
+	for _, r := range s.t.passwordResetTokens {
+		if r.TokenHash == arg.TokenHash {
+			return uuid.Nil, uniqueViolation("password_reset_tokens_token_hash_key")
+		}
+	}
+	s.t.passwordResetTokens[arg.ID] = db.PasswordResetToken{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		TokenHash: arg.TokenHash,
+		CreatedAt: now(),
+		ExpiresAt: arg.ExpiresAt,
+	}
+	return arg.ID, nil
+}
+
+// validResetToken return unused and unexpired reset token by hash
+func (s *Store) validResetToken(tokenHash string) (db.PasswordResetToken, bool) {
+	for _, r := range s.t.passwordResetTokens {
+		if r.TokenHash == tokenHash && !r.UsedAt.Valid && before(now(), r.ExpiresAt) {
+			return r, true
+		}
+	}
+	return db.PasswordResetToken{}, false
+}
+
+func (s *Store) GetOnePasswordResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.validResetToken(tokenHash)
+	if !ok {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	return r.UserID, nil
+}
+
+func (s *Store) UseOnePasswordResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.validResetToken(tokenHash)
+	if !ok {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	r.UsedAt = now()
+	s.t.passwordResetTokens[r.ID] = r
+	return r.UserID, nil
+}
+
+func (s *Store) InvalidateManyPasswordResetTokenByUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, r := range s.t.passwordResetTokens {
+		if r.UserID == userID && !r.UsedAt.Valid {
+			r.UsedAt = now()
+			s.t.passwordResetTokens[id] = r
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateOneEmailVerificationToken(ctx context.Context, arg *db.CreateOneEmailVerificationTokenParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "email_verification_tokens_user_id_fkey"); err != nil {
+		return uuid.Nil, err
+	}
+	for _, r := range s.t.emailTokens {
+		if r.TokenHash == arg.TokenHash {
+			return uuid.Nil, uniqueViolation("email_verification_tokens_token_hash_key")
+		}
+	}
+	s.t.emailTokens[arg.ID] = db.EmailVerificationToken{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Email:     arg.Email,
+		TokenHash: arg.TokenHash,
+		CreatedAt: now(),
+		ExpiresAt: arg.ExpiresAt,
+	}
+	return arg.ID, nil
+}
+
+func (s *Store) UseOneEmailVerificationToken(ctx context.Context, tokenHash string) (*db.UseOneEmailVerificationTokenRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, r := range s.t.emailTokens {
+		if r.TokenHash == tokenHash && !r.UsedAt.Valid && before(now(), r.ExpiresAt) {
+			r.UsedAt = now()
+			s.t.emailTokens[id] = r
+			return &db.UseOneEmailVerificationTokenRow{UserID: r.UserID, Email: r.Email}, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (s *Store) InvalidateManyEmailVerificationTokenByUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, r := range s.t.emailTokens {
+		if r.UserID == userID && !r.UsedAt.Valid {
+			r.UsedAt = now()
+			s.t.emailTokens[id] = r
+		}
+	}
+	return nil
+}
+
+// Login throttles
+
+func (s *Store) GetOneLoginThrottle(ctx context.Context, key string) (*db.GetOneLoginThrottleRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.loginThrottles[key]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &db.GetOneLoginThrottleRow{Failures: r.Failures, LastFailedAt: r.LastFailedAt, LockedUntil: r.LockedUntil}, nil
+}
+
+func (s *Store) UpsertOneFailureLoginThrottle(ctx context.Context, arg *db.UpsertOneFailureLoginThrottleParams) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.loginThrottles[arg.Key]
+	switch {
+	case !ok:
+		r = db.LoginThrottle{Key: arg.Key, Failures: 1}
+	case before(r.LastFailedAt, arg.LastFailedAt):
+		r.Failures = 1
+	default:
+		r.Failures++
+	}
+	r.LastFailedAt = now()
+	s.t.loginThrottles[arg.Key] = r
+	return r.Failures, nil
+}
+
+func (s *Store) LockOneLoginThrottle(ctx context.Context, arg *db.LockOneLoginThrottleParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.t.loginThrottles[arg.Key]; ok {
+		r.LockedUntil = arg.LockedUntil
+		s.t.loginThrottles[arg.Key] = r
+	}
+	return nil
+}
+
+func (s *Store) DeleteOneLoginThrottle(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.loginThrottles[key]; !ok {
+		return 0, nil
+	}
+	delete(s.t.loginThrottles, key)
+	return 1, nil
+}
+
+// Password histories
+
+func (s *Store) CreateOnePasswordHistory(ctx context.Context, arg *db.CreateOnePasswordHistoryParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "password_histories_user_id_fkey"); err != nil {
+		return err
+	}
+	s.t.passwordHistories = append(s.t.passwordHistories, db.PasswordHistory{
+		ID:           arg.ID,
+		UserID:       arg.UserID,
+		PasswordHash: arg.PasswordHash,
+		CreatedAt:    now(),
+	})
+	return nil
+}
+
+// historyByUser return password history of user newest first. Rows are
+// appended in insertion order so it break tie of equal created_at.
+func (s *Store) historyByUser(userID uuid.UUID) []db.PasswordHistory {
+	histories := make([]db.PasswordHistory, 0)
+	for _, r := range slices.Backward(s.t.passwordHistories) {
+		if r.UserID == userID {
+			histories = append(histories, r)
+		}
+	}
+	slices.SortStableFunc(histories, func(a, b db.PasswordHistory) int {
+		return cmp.Compare(b.CreatedAt.Time.UnixNano(), a.CreatedAt.Time.UnixNano())
+	})
+	return histories
+}
+
+func (s *Store) GetManyPasswordHistoryByUser(ctx context.Context, arg *db.GetManyPasswordHistoryByUserParams) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := []string{}
+	for _, r := range s.historyByUser(arg.UserID) {
+		if int32(len(items)) >= arg.Limit {
+			break
+		}
+		items = append(items, r.PasswordHash)
+	}
+	return items, nil
+}
+
+func (s *Store) DeleteManyOldPasswordHistoryByUser(ctx context.Context, arg *db.DeleteManyOldPasswordHistoryByUserParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keep := make(map[uuid.UUID]bool)
+	for i, r := range s.historyByUser(arg.UserID) {
+		if int32(i) < arg.Keep {
+			keep[r.ID] = true
+		}
+	}
+	count := len(s.t.passwordHistories)
+	s.t.passwordHistories = slices.DeleteFunc(s.t.passwordHistories, func(r db.PasswordHistory) bool {
+		return r.UserID == arg.UserID && !keep[r.ID]
+	})
+	return int64(count - len(s.t.passwordHistories)), nil
+}
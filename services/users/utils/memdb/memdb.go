@@ -0,0 +1,549 @@
+// Package memdb is in-memory implementation of db.Querier used to run
+// users service without PostgreSQL in unit test. It follow constraint
+// and soft delete semantic of SQL queries.
+package memdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+)
+
+var (
+	_ db.Querier        = (*Store)(nil)
+	_ database.TxRunner = (*Store)(nil)
+)
+
+// tables hold every row by value so it can be cloned cheaply for
+// transaction rollback
+type tables struct {
+	users                map[uuid.UUID]db.User
+	sessions             map[uuid.UUID]db.Session
+	tokenRevocations     map[uuid.UUID]db.TokenRevocation
+	userTokenRevocations map[uuid.UUID]db.UserTokenRevocation
+	passwordResetTokens  map[uuid.UUID]db.PasswordResetToken
+	emailTokens          map[uuid.UUID]db.EmailVerificationToken
+	loginThrottles       map[string]db.LoginThrottle
+	userMfa              map[uuid.UUID]db.UserMfa
+	recoveryCodes        []db.MfaRecoveryCode
+	mfaRolePolicies      map[db.UserRole]db.MfaRolePolicy
+	passwordHistories    []db.PasswordHistory
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		users:                maps.Clone(t.users),
+		sessions:             maps.Clone(t.sessions),
+		tokenRevocations:     maps.Clone(t.tokenRevocations),
+		userTokenRevocations: maps.Clone(t.userTokenRevocations),
+		passwordResetTokens:  maps.Clone(t.passwordResetTokens),
+		emailTokens:          maps.Clone(t.emailTokens),
+		loginThrottles:       maps.Clone(t.loginThrottles),
+		userMfa:              maps.Clone(t.userMfa),
+		recoveryCodes:        slices.Clone(t.recoveryCodes),
+		mfaRolePolicies:      maps.Clone(t.mfaRolePolicies),
+		passwordHistories:    slices.Clone(t.passwordHistories),
+	}
+}
+
+// Store is in-memory database. It also implement database.TxRunner by
+// serializing transactions and restoring snapshot on failure.
+type Store struct {
+	mu   sync.Mutex
+	txMu sync.Mutex
+	t    *tables
+}
+
+func New() *Store {
+	return &Store{
+		t: &tables{
+			users:                make(map[uuid.UUID]db.User),
+			sessions:             make(map[uuid.UUID]db.Session),
+			tokenRevocations:     make(map[uuid.UUID]db.TokenRevocation),
+			userTokenRevocations: make(map[uuid.UUID]db.UserTokenRevocation),
+			passwordResetTokens:  make(map[uuid.UUID]db.PasswordResetToken),
+			emailTokens:          make(map[uuid.UUID]db.EmailVerificationToken),
+			loginThrottles:       make(map[string]db.LoginThrottle),
+			userMfa:              make(map[uuid.UUID]db.UserMfa),
+			mfaRolePolicies:      make(map[db.UserRole]db.MfaRolePolicy),
+		},
+	}
+}
+
+func (s *Store) RunTx(ctx context.Context, fn func(q db.Querier) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.t.clone()
+	s.mu.Unlock()
+
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		s.t = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func now() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now(), Valid: true}
+}
+
+// before report whether a is valid and earlier than b, matching SQL
+// comparison where NULL is never true
+func before(a, b pgtype.Timestamptz) bool {
+	return a.Valid && b.Valid && a.Time.Before(b.Time)
+}
+
+func uniqueViolation(constraint string) error {
+	return &pgconn.PgError{Code: database.CodeUniqueViolation, ConstraintName: constraint}
+}
+
+func foreignKeyViolation(constraint string) error {
+	return &pgconn.PgError{Code: "23503", ConstraintName: constraint}
+}
+
+// requireUser mimic foreign key to users table
+func (s *Store) requireUser(id uuid.UUID, constraint string) error {
+	if _, ok := s.t.users[id]; !ok {
+		return foreignKeyViolation(constraint)
+	}
+	return nil
+}
+
+// activeUser return user which is not soft deleted
+func (s *Store) activeUser(id uuid.UUID) (db.User, bool) {
+	u, ok := s.t.users[id]
+	return u, ok && !u.DeletedAt.Valid
+}
+
+// emailTaken mimic UNIQUE NULLS NOT DISTINCT (email, deleted_at)
+func (s *Store) emailTaken(email string, deletedAt pgtype.Timestamptz, except uuid.UUID) bool {
+	for id, u := range s.t.users {
+		if id != except && u.Email == email && u.DeletedAt == deletedAt {
+			return true
+		}
+	}
+	return false
+}
+
+// Users
+
+func (s *Store) CreateOneUser(ctx context.Context, arg *db.CreateOneUserParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.users[arg.ID]; ok {
+		return uuid.Nil, uniqueViolation("users_pkey")
+	}
+	if s.emailTaken(arg.Email, pgtype.Timestamptz{}, uuid.Nil) {
+		return uuid.Nil, uniqueViolation("users_email_deleted_at_key")
+	}
+	ts := now()
+	s.t.users[arg.ID] = db.User{
+		ID:           arg.ID,
+		Email:        arg.Email,
+		Role:         arg.Role,
+		PasswordHash: arg.PasswordHash,
+		CreatedAt:    ts,
+		UpdatedAt:    ts,
+	}
+	return arg.ID, nil
+}
+
+func (s *Store) GetOneUser(ctx context.Context, id uuid.UUID) (*db.GetOneUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.activeUser(id)
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &db.GetOneUserRow{
+		ID:              u.ID,
+		Email:           u.Email,
+		Role:            u.Role,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}, nil
+}
+
+func (s *Store) GetOneCredentialUserByEmail(ctx context.Context, email string) (*db.GetOneCredentialUserByEmailRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.t.users {
+		if u.Email == email && !u.DeletedAt.Valid {
+			return &db.GetOneCredentialUserByEmailRow{
+				ID:              u.ID,
+				PasswordHash:    u.PasswordHash,
+				Role:            u.Role,
+				EmailVerifiedAt: u.EmailVerifiedAt,
+			}, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+// userFilter mimic WHERE clause shared by GetManyUser queries and
+// CountManyUser
+type userFilter struct {
+	role          db.NullUserRole
+	emailPattern  string
+	createdAfter  pgtype.Timestamptz
+	createdBefore pgtype.Timestamptz
+}
+
+func (f userFilter) match(u db.User) bool {
+	if u.DeletedAt.Valid {
+		return false
+	}
+	if f.role.Valid && u.Role != f.role.UserRole {
+		return false
+	}
+	if f.emailPattern != "" && !like(u.Email, f.emailPattern) {
+		return false
+	}
+	if f.createdAfter.Valid && !(u.CreatedAt.Valid && !u.CreatedAt.Time.Before(f.createdAfter.Time)) {
+		return false
+	}
+	if f.createdBefore.Valid && !before(u.CreatedAt, f.createdBefore) {
+		return false
+	}
+	return true
+}
+
+// like report whether s match SQL LIKE pattern using backslash as
+// escape character
+func like(s, pattern string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			expr.WriteString(".*")
+		case r == '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(s)
+}
+
+func compareID(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+func compareEmail(a, b db.User) int {
+	if c := strings.Compare(a.Email, b.Email); c != 0 {
+		return c
+	}
+	return compareID(a.ID, b.ID)
+}
+
+// listUsers return at most limit users matching f ordered by cmp. When
+// cursor is set only users ordered after it are returned. Email is
+// compared byte-wise instead of using database collation.
+func (s *Store) listUsers(f userFilter, cmp func(a, b db.User) int, cursor *db.User, limit int32) []*db.GetManyUserRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := make([]db.User, 0, len(s.t.users))
+	for _, u := range s.t.users {
+		if f.match(u) && (cursor == nil || cmp(u, *cursor) > 0) {
+			users = append(users, u)
+		}
+	}
+	slices.SortFunc(users, cmp)
+
+	items := []*db.GetManyUserRow{}
+	for _, u := range users {
+		if int32(len(items)) >= limit {
+			break
+		}
+		items = append(items, &db.GetManyUserRow{ID: u.ID, Email: u.Email, Role: u.Role, CreatedAt: u.CreatedAt})
+	}
+	return items
+}
+
+// convertRows convert rows of GetManyUser to row type of query sharing
+// same columns
+func convertRows[T ~struct {
+	ID        uuid.UUID
+	Email     string
+	Role      db.UserRole
+	CreatedAt pgtype.Timestamptz
+}](rows []*db.GetManyUserRow) []*T {
+	items := make([]*T, 0, len(rows))
+	for _, row := range rows {
+		item := T(*row)
+		items = append(items, &item)
+	}
+	return items
+}
+
+func (s *Store) GetManyUser(ctx context.Context, arg *db.GetManyUserParams) ([]*db.GetManyUserRow, error) {
+	f := userFilter{arg.Role, arg.EmailPattern, arg.CreatedAfter, arg.CreatedBefore}
+	var cursor *db.User
+	if arg.HasCursor {
+		cursor = &db.User{ID: arg.AfterID}
+	}
+	cmp := func(a, b db.User) int { return compareID(a.ID, b.ID) }
+	return s.listUsers(f, cmp, cursor, arg.PageSize), nil
+}
+
+func (s *Store) GetManyUserDesc(ctx context.Context, arg *db.GetManyUserDescParams) ([]*db.GetManyUserDescRow, error) {
+	f := userFilter{arg.Role, arg.EmailPattern, arg.CreatedAfter, arg.CreatedBefore}
+	var cursor *db.User
+	if arg.HasCursor {
+		cursor = &db.User{ID: arg.AfterID}
+	}
+	cmp := func(a, b db.User) int { return compareID(b.ID, a.ID) }
+	return convertRows[db.GetManyUserDescRow](s.listUsers(f, cmp, cursor, arg.PageSize)), nil
+}
+
+func (s *Store) GetManyUserByEmail(ctx context.Context, arg *db.GetManyUserByEmailParams) ([]*db.GetManyUserByEmailRow, error) {
+	f := userFilter{arg.Role, arg.EmailPattern, arg.CreatedAfter, arg.CreatedBefore}
+	var cursor *db.User
+	if arg.HasCursor {
+		cursor = &db.User{ID: arg.AfterID, Email: arg.AfterEmail}
+	}
+	return convertRows[db.GetManyUserByEmailRow](s.listUsers(f, compareEmail, cursor, arg.PageSize)), nil
+}
+
+func (s *Store) GetManyUserByEmailDesc(ctx context.Context, arg *db.GetManyUserByEmailDescParams) ([]*db.GetManyUserByEmailDescRow, error) {
+	f := userFilter{arg.Role, arg.EmailPattern, arg.CreatedAfter, arg.CreatedBefore}
+	var cursor *db.User
+	if arg.HasCursor {
+		cursor = &db.User{ID: arg.AfterID, Email: arg.AfterEmail}
+	}
+	cmp := func(a, b db.User) int { return compareEmail(b, a) }
+	return convertRows[db.GetManyUserByEmailDescRow](s.listUsers(f, cmp, cursor, arg.PageSize)), nil
+}
+
+func (s *Store) CountManyUser(ctx context.Context, arg *db.CountManyUserParams) (int64, error) {
+	f := userFilter{arg.Role, arg.EmailPattern, arg.CreatedAfter, arg.CreatedBefore}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := int64(0)
+	for _, u := range s.t.users {
+		if f.match(u) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// emailWords split email the same way as search index expression
+var emailWords = strings.NewReplacer("@", " ", ".", " ", "_", " ", "-", " ", "+", " ")
+
+// matchWords report whether every term of prefix tsquery "a:* & b:*"
+// prefix some word of email
+func matchWords(email, query string) bool {
+	if query == "" {
+		return false
+	}
+	words := strings.Fields(strings.ToLower(emailWords.Replace(email)))
+	for _, term := range strings.Split(query, " & ") {
+		term = strings.ToLower(strings.TrimSuffix(term, ":*"))
+		if !slices.ContainsFunc(words, func(w string) bool { return strings.HasPrefix(w, term) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchUser approximate full text and trigram ranking. Word prefix match
+// rank above plain substring match, trigram similarity is not computed so
+// misspelled query only match when it is substring of email.
+func (s *Store) SearchUser(ctx context.Context, arg *db.SearchUserParams) ([]*db.SearchUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := []*db.SearchUserRow{}
+	for _, u := range s.t.users {
+		if u.DeletedAt.Valid {
+			continue
+		}
+		if len(arg.Roles) > 0 && !slices.Contains(arg.Roles, string(u.Role)) {
+			continue
+		}
+		var rank float32
+		if matchWords(u.Email, arg.WordQuery) {
+			rank += 1
+		}
+		if like(strings.ToLower(u.Email), strings.ToLower(arg.ContainsPattern)) {
+			rank += 0.5
+		}
+		if rank == 0 {
+			continue
+		}
+		items = append(items, &db.SearchUserRow{
+			ID:        u.ID,
+			Email:     u.Email,
+			Role:      u.Role,
+			CreatedAt: u.CreatedAt,
+			Rank:      rank,
+		})
+	}
+	slices.SortFunc(items, func(a, b *db.SearchUserRow) int {
+		if a.Rank != b.Rank {
+			if a.Rank > b.Rank {
+				return -1
+			}
+			return 1
+		}
+		return compareID(a.ID, b.ID)
+	})
+	if int32(len(items)) > arg.PageSize {
+		items = items[:arg.PageSize]
+	}
+	return items, nil
+}
+
+// updateUser apply fn to user which is not soft deleted
+func (s *Store) updateUser(id uuid.UUID, fn func(u *db.User) error) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.activeUser(id)
+	if !ok {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	if err := fn(&u); err != nil {
+		return uuid.Nil, err
+	}
+	s.t.users[id] = u
+	return id, nil
+}
+
+func (s *Store) UpdateOnePasswordUser(ctx context.Context, arg *db.UpdateOnePasswordUserParams) (uuid.UUID, error) {
+	return s.updateUser(arg.ID, func(u *db.User) error {
+		u.PasswordHash = arg.PasswordHash
+		u.UpdatedAt = now()
+		return nil
+	})
+}
+
+func (s *Store) UpdateOneEmailUser(ctx context.Context, arg *db.UpdateOneEmailUserParams) (uuid.UUID, error) {
+	return s.updateUser(arg.ID, func(u *db.User) error {
+		if s.emailTaken(arg.Email, u.DeletedAt, u.ID) {
+			return uniqueViolation("users_email_deleted_at_key")
+		}
+		u.Email = arg.Email
+		u.EmailVerifiedAt = pgtype.Timestamptz{}
+		u.UpdatedAt = now()
+		return nil
+	})
+}
+
+func (s *Store) UpdateOneRoleUser(ctx context.Context, arg *db.UpdateOneRoleUserParams) (uuid.UUID, error) {
+	return s.updateUser(arg.ID, func(u *db.User) error {
+		u.Role = arg.Role
+		u.UpdatedAt = now()
+		return nil
+	})
+}
+
+func (s *Store) VerifyOneEmailUser(ctx context.Context, arg *db.VerifyOneEmailUserParams) (uuid.UUID, error) {
+	return s.updateUser(arg.ID, func(u *db.User) error {
+		if u.Email != arg.Email {
+			return pgx.ErrNoRows
+		}
+		ts := now()
+		u.EmailVerifiedAt = ts
+		u.UpdatedAt = ts
+		return nil
+	})
+}
+
+func (s *Store) RehashOnePasswordUser(ctx context.Context, arg *db.RehashOnePasswordUserParams) (int64, error) {
+	_, err := s.updateUser(arg.ID, func(u *db.User) error {
+		if u.PasswordHash != arg.OldPasswordHash {
+			return pgx.ErrNoRows
+		}
+		u.PasswordHash = arg.NewPasswordHash
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (s *Store) DeleteSoftOneUser(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.activeUser(id)
+	if !ok {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	deletedAt := now()
+	if s.emailTaken(u.Email, deletedAt, u.ID) {
+		return uuid.Nil, uniqueViolation("users_email_deleted_at_key")
+	}
+	u.DeletedAt = deletedAt
+	s.t.users[id] = u
+	return id, nil
+}
+
+func (s *Store) DeleteHardOneUser(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.users[id]; !ok {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	delete(s.t.users, id)
+
+	// ON DELETE CASCADE
+	maps.DeleteFunc(s.t.sessions, func(_ uuid.UUID, r db.Session) bool { return r.UserID == id })
+	maps.DeleteFunc(s.t.passwordResetTokens, func(_ uuid.UUID, r db.PasswordResetToken) bool { return r.UserID == id })
+	maps.DeleteFunc(s.t.emailTokens, func(_ uuid.UUID, r db.EmailVerificationToken) bool { return r.UserID == id })
+	delete(s.t.userTokenRevocations, id)
+	delete(s.t.userMfa, id)
+	s.t.recoveryCodes = slices.DeleteFunc(s.t.recoveryCodes, func(r db.MfaRecoveryCode) bool { return r.UserID == id })
+	s.t.passwordHistories = slices.DeleteFunc(s.t.passwordHistories, func(r db.PasswordHistory) bool { return r.UserID == id })
+	return id, nil
+}
+
+func (s *Store) CountEmailUser(ctx context.Context, email string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := int64(0)
+	for _, u := range s.t.users {
+		if u.Email == email && !u.DeletedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Store) CountIDUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.users[id]; ok {
+		return 1, nil
+	}
+	return 0, nil
+}
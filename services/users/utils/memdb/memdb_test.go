@@ -0,0 +1,90 @@
+package memdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/database"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/memdb"
+)
+
+func createUser(t *testing.T, q db.Querier, email string) uuid.UUID {
+	t.Helper()
+	id, err := q.CreateOneUser(context.Background(), &db.CreateOneUserParams{
+		ID:           uuid.New(),
+		Email:        email,
+		Role:         db.UserRoleStudent,
+		PasswordHash: "hash",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestEmailUniqueness(t *testing.T) {
+	ctx := context.Background()
+	s := memdb.New()
+	id := createUser(t, s, "user@email.com")
+
+	_, err := s.CreateOneUser(ctx, &db.CreateOneUserParams{
+		ID:    uuid.New(),
+		Email: "user@email.com",
+		Role:  db.UserRoleStudent,
+	})
+	if !database.IsUniqueViolation(err) {
+		t.Fatalf("expected unique violation, got %v", err)
+	}
+
+	// Soft deleted user release email and is no longer counted
+	if _, err := s.DeleteSoftOneUser(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetOneUser(ctx, id); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected soft deleted user hidden, got %v", err)
+	}
+	if _, err := s.DeleteSoftOneUser(ctx, id); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected soft deleted user not deleted twice, got %v", err)
+	}
+	createUser(t, s, "user@email.com")
+	count, err := s.CountEmailUser(ctx, "user@email.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 active user with email, got %d", count)
+	}
+}
+
+func TestRunTxRollback(t *testing.T) {
+	ctx := context.Background()
+	s := memdb.New()
+	errAbort := errors.New("abort")
+
+	var id uuid.UUID
+	err := s.RunTx(ctx, func(q db.Querier) error {
+		id = createUser(t, q, "user@email.com")
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+	if _, err := s.GetOneUser(ctx, id); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected insert rolled back, got %v", err)
+	}
+
+	err = s.RunTx(ctx, func(q db.Querier) error {
+		id = createUser(t, q, "user@email.com")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetOneUser(ctx, id); err != nil {
+		t.Fatalf("expected insert committed, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package memdb
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+)
+
+func (s *Store) GetOneUserMfa(ctx context.Context, userID uuid.UUID) (*db.GetOneUserMfaRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.userMfa[userID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &db.GetOneUserMfaRow{
+		Secret:       r.Secret,
+		LastUsedStep: r.LastUsedStep,
+		ConfirmedAt:  r.ConfirmedAt,
+	}, nil
+}
+
+func (s *Store) UpsertOnePendingUserMfa(ctx context.Context, arg *db.UpsertOnePendingUserMfaParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "user_mfa_user_id_fkey"); err != nil {
+		return 0, err
+	}
+	if r, ok := s.t.userMfa[arg.UserID]; ok && r.ConfirmedAt.Valid {
+		return 0, nil
+	}
+	s.t.userMfa[arg.UserID] = db.UserMfa{
+		UserID:    arg.UserID,
+		Secret:    arg.Secret,
+		CreatedAt: now(),
+	}
+	return 1, nil
+}
+
+func (s *Store) ConfirmOneUserMfa(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.userMfa[userID]
+	if !ok || r.ConfirmedAt.Valid {
+		return 0, nil
+	}
+	r.ConfirmedAt = now()
+	s.t.userMfa[userID] = r
+	return 1, nil
+}
+
+func (s *Store) UseStepUserMfa(ctx context.Context, arg *db.UseStepUserMfaParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.userMfa[arg.UserID]
+	if !ok || r.LastUsedStep >= arg.LastUsedStep {
+		return 0, nil
+	}
+	r.LastUsedStep = arg.LastUsedStep
+	s.t.userMfa[arg.UserID] = r
+	return 1, nil
+}
+
+func (s *Store) DeleteOneUserMfa(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.t.userMfa[userID]; !ok {
+		return 0, nil
+	}
+	delete(s.t.userMfa, userID)
+	return 1, nil
+}
+
+func (s *Store) CreateOneMfaRecoveryCode(ctx context.Context, arg *db.CreateOneMfaRecoveryCodeParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.requireUser(arg.UserID, "mfa_recovery_codes_user_id_fkey"); err != nil {
+		return err
+	}
+	s.t.recoveryCodes = append(s.t.recoveryCodes, db.MfaRecoveryCode{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		CodeHash:  arg.CodeHash,
+		CreatedAt: now(),
+	})
+	return nil
+}
+
+func (s *Store) GetManyMfaRecoveryCodeByUser(ctx context.Context, userID uuid.UUID) ([]*db.GetManyMfaRecoveryCodeByUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := []*db.GetManyMfaRecoveryCodeByUserRow{}
+	for _, r := range s.t.recoveryCodes {
+		if r.UserID == userID && !r.UsedAt.Valid {
+			items = append(items, &db.GetManyMfaRecoveryCodeByUserRow{ID: r.ID, CodeHash: r.CodeHash})
+		}
+	}
+	return items, nil
+}
+
+func (s *Store) UseOneMfaRecoveryCode(ctx context.Context, id uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, r := range s.t.recoveryCodes {
+		if r.ID == id && !r.UsedAt.Valid {
+			s.t.recoveryCodes[i].UsedAt = now()
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *Store) DeleteManyMfaRecoveryCodeByUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.t.recoveryCodes = slices.DeleteFunc(s.t.recoveryCodes, func(r db.MfaRecoveryCode) bool {
+		return r.UserID == userID
+	})
+	return nil
+}
+
+func (s *Store) GetOneMfaRolePolicy(ctx context.Context, role db.UserRole) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.t.mfaRolePolicies[role]
+	if !ok {
+		return false, pgx.ErrNoRows
+	}
+	return r.Required, nil
+}
+
+func (s *Store) UpsertOneMfaRolePolicy(ctx context.Context, arg *db.UpsertOneMfaRolePolicyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.t.mfaRolePolicies[arg.Role] = db.MfaRolePolicy{Role: arg.Role, Required: arg.Required}
+	return nil
+}
@@ -0,0 +1,376 @@
+// Package migrate apply versioned SQL migration to users database.
+// Applied version is tracked in schema_migrations table using same
+// layout as golang-migrate so database migrated by either tool stay
+// compatible.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey is advisory lock key held while migrating so only one
+// instance migrate at a time
+const lockKey int64 = 0x75736572736d6967
+
+var (
+	ErrDirty       = errors.New("migrate: database is dirty, fix it manually then use force")
+	ErrAhead       = errors.New("migrate: database schema is ahead of binary")
+	ErrNoChange    = errors.New("migrate: no change")
+	ErrNoDown      = errors.New("migrate: migration has no down script")
+	ErrUnknownStep = errors.New("migrate: unknown version")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is single schema version
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load read migrations from fsys sorted by version. Every version must
+// have up script while down script is optional.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrate: invalid version %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d has different names", version)
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrate: version %d has no up script", m.Version)
+		}
+		migrations = append(migrations, m)
+	}
+	slices.SortFunc(migrations, func(a, b *Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// step apply or revert single migration
+type step struct {
+	m  *Migration
+	up bool
+	// to is version recorded after step
+	to int64
+}
+
+// plan return steps moving schema from current to target version.
+// Version 0 mean no migration applied.
+func plan(migrations []*Migration, current, target int64) ([]step, error) {
+	index := func(version int64) (int, bool) {
+		if version == 0 {
+			return -1, true
+		}
+		i := slices.IndexFunc(migrations, func(m *Migration) bool { return m.Version == version })
+		return i, i >= 0
+	}
+
+	from, ok := index(current)
+	if !ok {
+		if len(migrations) > 0 && current > migrations[len(migrations)-1].Version {
+			return nil, ErrAhead
+		}
+		return nil, fmt.Errorf("%w %d", ErrUnknownStep, current)
+	}
+	to, ok := index(target)
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownStep, target)
+	}
+
+	steps := make([]step, 0)
+	for i := from + 1; i <= to; i++ {
+		steps = append(steps, step{m: migrations[i], up: true, to: migrations[i].Version})
+	}
+	for i := from; i > to; i-- {
+		prev := int64(0)
+		if i > 0 {
+			prev = migrations[i-1].Version
+		}
+		if migrations[i].Down == "" {
+			return nil, fmt.Errorf("%w %d", ErrNoDown, migrations[i].Version)
+		}
+		steps = append(steps, step{m: migrations[i], up: false, to: prev})
+	}
+	return steps, nil
+}
+
+// Migrator run migrations against database
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []*Migration
+}
+
+func New(pool *pgxpool.Pool, migrations []*Migration) *Migrator {
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+	}
+}
+
+// Latest return newest version known by binary
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// withLock run fn on single connection holding migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			log.Printf("error: failed to release migration lock. %s\n", err.Error())
+		}
+	}()
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version bigint NOT NULL PRIMARY KEY,
+    dirty boolean NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("migrate: create schema table: %w", err)
+	}
+	return fn(conn)
+}
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func readVersion(ctx context.Context, q querier) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := q.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("migrate: read version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+func writeVersion(ctx context.Context, q querier, version int64, dirty bool) error {
+	if _, err := q.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("migrate: write version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := q.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", version, dirty)
+	if err != nil {
+		return fmt.Errorf("migrate: write version: %w", err)
+	}
+	return nil
+}
+
+// Version return current schema version and whether last migration
+// failed halfway
+func (m *Migrator) Version(ctx context.Context) (version int64, dirty bool, err error) {
+	err = m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err = readVersion(ctx, conn)
+		return err
+	})
+	return version, dirty, err
+}
+
+// migrateTo move schema to target, each step run in its own transaction
+func (m *Migrator) migrateTo(ctx context.Context, target func(current int64) (int64, error)) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirty
+		}
+		to, err := target(current)
+		if err != nil {
+			return err
+		}
+		steps, err := plan(m.migrations, current, to)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return ErrNoChange
+		}
+
+		for _, s := range steps {
+			if err := runStep(ctx, conn, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// stepConn is connection able to run step
+type stepConn interface {
+	querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// runStep mark version of s dirty, then run its script and record new
+// version in single transaction. Version is left dirty when script fail
+// so it has to be fixed by hand and cleared using force.
+func runStep(ctx context.Context, conn stepConn, s step) error {
+	script, direction := s.m.Up, "up"
+	if !s.up {
+		script, direction = s.m.Down, "down"
+	}
+	if err := writeVersion(ctx, conn, s.m.Version, true); err != nil {
+		return err
+	}
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, script); err != nil {
+			return err
+		}
+		return writeVersion(ctx, tx, s.to, false)
+	})
+	if err != nil {
+		return fmt.Errorf("migrate: %d_%s %s: %w", s.m.Version, s.m.Name, direction, err)
+	}
+	log.Printf("migrated %d_%s %s\n", s.m.Version, s.m.Name, direction)
+	return nil
+}
+
+// Up apply every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.migrateTo(ctx, func(int64) (int64, error) {
+		return m.Latest(), nil
+	})
+}
+
+// Down revert n latest applied migrations
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.migrateTo(ctx, func(current int64) (int64, error) {
+		i := slices.IndexFunc(m.migrations, func(mg *Migration) bool { return mg.Version == current })
+		if current != 0 && i < 0 {
+			return 0, fmt.Errorf("%w %d", ErrUnknownStep, current)
+		}
+		if i-n < 0 {
+			return 0, nil
+		}
+		return m.migrations[i-n].Version, nil
+	})
+}
+
+// Goto migrate up or down to version, 0 revert every migration
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	return m.migrateTo(ctx, func(int64) (int64, error) {
+		return version, nil
+	})
+}
+
+// Force record version as applied without running any script and clear
+// dirty flag. It is used to recover dirty database or adopt database
+// migrated by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(mg *Migration) bool { return mg.Version == version }) {
+		return fmt.Errorf("%w %d", ErrUnknownStep, version)
+	}
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return writeVersion(ctx, conn, version, false)
+	})
+}
+
+// Status describe single migration state
+type Status struct {
+	Migration *Migration
+	Applied   bool
+}
+
+// Status return every known migration along with whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	current, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		statuses = append(statuses, Status{Migration: mg, Applied: mg.Version <= current})
+	}
+	return statuses, nil
+}
+
+// Check refuse schema which is dirty or newer than binary. Schema
+// behind binary is only reported, database migrated by hand has no
+// recorded version until it is adopted using force.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	return checkVersion(current, dirty, m.Latest())
+}
+
+func checkVersion(current int64, dirty bool, latest int64) error {
+	if dirty {
+		return ErrDirty
+	}
+	if current > latest {
+		return fmt.Errorf("%w: database at %d, binary at %d", ErrAhead, current, latest)
+	}
+	if current < latest {
+		log.Printf("warning: database schema at %d is behind binary at %d, run migrate up\n", current, latest)
+	}
+	return nil
+}
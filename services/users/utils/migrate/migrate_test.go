@@ -0,0 +1,202 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	migrationsfs "github.com/nurfianqodar/school-microservices/services/users/misc/db/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"2_second.up.sql":  {Data: []byte("CREATE TABLE b ();")},
+		"1_first.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"1_first.down.sql": {Data: []byte("DROP TABLE a;")},
+		"migrations.go":    {Data: []byte("package migrations")},
+		"3_third.down.sql": {Data: []byte("DROP TABLE c;")},
+		"3_third.up.sql":   {Data: []byte("CREATE TABLE c ();")},
+		"README":           {Data: []byte("ignored")},
+	}
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(migrations))
+	}
+	for i, want := range []int64{1, 2, 3} {
+		if migrations[i].Version != want {
+			t.Errorf("migration %d has version %d, want %d", i, migrations[i].Version, want)
+		}
+	}
+	if migrations[0].Name != "first" || migrations[0].Down != "DROP TABLE a;" || migrations[1].Down != "" {
+		t.Errorf("unexpected migration %+v %+v", migrations[0], migrations[1])
+	}
+
+	// Down script without up script is rejected
+	if _, err := Load(fstest.MapFS{"1_first.down.sql": {Data: []byte("DROP TABLE a;")}}); err == nil {
+		t.Error("expected error on missing up script")
+	}
+}
+
+func TestLoadEmbedded(t *testing.T) {
+	migrations, err := Load(migrationsfs.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		if m.Down == "" {
+			t.Errorf("migration %d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	migrations := []*Migration{
+		{Version: 1, Name: "first", Up: "up", Down: "down"},
+		{Version: 2, Name: "second", Up: "up", Down: "down"},
+		{Version: 3, Name: "third", Up: "up"},
+	}
+
+	tests := []struct {
+		name            string
+		current, target int64
+		want            []int64
+		err             error
+	}{
+		{"up from empty", 0, 3, []int64{1, 2, 3}, nil},
+		{"up partial", 1, 2, []int64{2}, nil},
+		{"no change", 2, 2, []int64{}, nil},
+		{"down", 2, 0, []int64{1, 0}, nil},
+		{"down without script", 3, 2, nil, ErrNoDown},
+		{"ahead", 4, 3, nil, ErrAhead},
+		{"unknown target", 1, 5, nil, ErrUnknownStep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := plan(migrations, tt.current, tt.target)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]int64, 0, len(steps))
+			for _, s := range steps {
+				got = append(got, s.to)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int64
+		dirty   bool
+		err     error
+	}{
+		{"current", 3, false, nil},
+		{"dirty", 3, true, ErrDirty},
+		{"ahead", 4, false, ErrAhead},
+		{"behind", 2, false, nil},
+		{"empty", 0, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkVersion(tt.current, tt.dirty, 3); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// fakeConn keep schema_migrations row in memory. Statement run inside
+// transaction is only applied on commit.
+type fakeConn struct {
+	querier
+	version int64
+	dirty   bool
+	// fail is script returning error
+	fail string
+}
+
+func (c *fakeConn) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case sql == c.fail:
+		return pgconn.CommandTag{}, errors.New("script failed")
+	case strings.HasPrefix(sql, "DELETE FROM schema_migrations"):
+		c.version, c.dirty = 0, false
+	case strings.HasPrefix(sql, "INSERT INTO schema_migrations"):
+		c.version, c.dirty = args[0].(int64), args[1].(bool)
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (c *fakeConn) Begin(context.Context) (pgx.Tx, error) {
+	tx := *c
+	return &fakeTx{conn: c, tx: &tx}, nil
+}
+
+type fakeTx struct {
+	pgx.Tx
+	conn *fakeConn
+	tx   *fakeConn
+}
+
+func (t *fakeTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.tx.Exec(ctx, sql, args...)
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	t.conn.version, t.conn.dirty = t.tx.version, t.tx.dirty
+	return nil
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	return nil
+}
+
+func TestRunStep(t *testing.T) {
+	m := &Migration{Version: 2, Name: "second", Up: "up", Down: "down"}
+
+	tests := []struct {
+		name    string
+		step    step
+		fail    string
+		version int64
+		dirty   bool
+	}{
+		{"up", step{m: m, up: true, to: 2}, "", 2, false},
+		{"down", step{m: m, up: false, to: 1}, "", 1, false},
+		{"failed up", step{m: m, up: true, to: 2}, "up", 2, true},
+		{"failed down", step{m: m, up: false, to: 1}, "down", 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{version: 1, fail: tt.fail}
+			if !tt.step.up {
+				conn.version = 2
+			}
+			err := runStep(context.Background(), conn, tt.step)
+			if (tt.fail != "") != (err != nil) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if conn.version != tt.version || conn.dirty != tt.dirty {
+				t.Errorf("got version %d dirty %v, want %d dirty %v", conn.version, conn.dirty, tt.version, tt.dirty)
+			}
+		})
+	}
+}
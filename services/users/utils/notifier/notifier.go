@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Message delivered to user
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier deliver message to user
+type Notifier interface {
+	Notify(ctx context.Context, m *Message) error
+}
+
+type logNotifier struct{}
+
+// NewLog create notifier which print message to standard log. It is
+// intended for local development only.
+func NewLog() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, m *Message) error {
+	log.Printf("notify: to=%s subject=%q body=%q\n", m.To, m.Subject, m.Body)
+	return nil
+}
+
+type fileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFile create notifier which append message as JSON line to file
+// at path. It is intended for local development only.
+func NewFile(path string) Notifier {
+	return &fileNotifier{path: path}
+}
+
+func (n *fileNotifier) Notify(ctx context.Context, m *Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(struct {
+		*Message
+		SentAt time.Time `json:"sentAt"`
+	}{m, time.Now()})
+}
@@ -0,0 +1,42 @@
+package notifier_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nurfianqodar/school-microservices/services/users/utils/notifier"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.jsonl")
+	n := notifier.NewFile(path)
+
+	for _, to := range []string{"first@email.com", "second@email.com"} {
+		err := n.Notify(t.Context(), &notifier.Message{To: to, Subject: "subject", Body: "body"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var got []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m notifier.Message
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m.To)
+	}
+	if len(got) != 2 || got[0] != "first@email.com" || got[1] != "second@email.com" {
+		t.Errorf("unexpected messages %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DenyList report whether password is known to be common or breached
+type DenyList interface {
+	Contains(password string) (bool, error)
+}
+
+var sha1Line = regexp.MustCompile(`^[0-9A-Fa-f]{40}(:\d+)?$`)
+
+// LoadDenyList load deny-list at path. Directory is treated as
+// k-anonymity dump holding one file per 5 character SHA-1 prefix, each
+// line being remaining hash suffix optionally followed by :count. File
+// may contain plain password or full SHA-1 hash, one per line.
+func LoadDenyList(path string) (DenyList, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("passwordpolicy: load deny-list: %w", err)
+	}
+	if info.IsDir() {
+		return prefixDir(path), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("passwordpolicy: load deny-list: %w", err)
+	}
+	defer f.Close()
+
+	set := &denySet{
+		plain:  make(map[string]struct{}),
+		hashes: make(map[string]struct{}),
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if sha1Line.MatchString(line) {
+			set.hashes[strings.ToUpper(line[:40])] = struct{}{}
+			continue
+		}
+		set.plain[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("passwordpolicy: load deny-list: %w", err)
+	}
+	return set, nil
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// denySet is deny-list fully loaded in memory
+type denySet struct {
+	plain  map[string]struct{}
+	hashes map[string]struct{}
+}
+
+func (s *denySet) Contains(password string) (bool, error) {
+	if _, ok := s.plain[strings.ToLower(password)]; ok {
+		return true, nil
+	}
+	_, ok := s.hashes[sha1Hex(password)]
+	return ok, nil
+}
+
+// prefixDir is k-anonymity dump directory read on demand
+type prefixDir string
+
+func (d prefixDir) Contains(password string) (bool, error) {
+	hash := sha1Hex(password)
+	prefix, suffix := hash[:5], hash[5:]
+
+	var f *os.File
+	var err error
+	for _, name := range []string{prefix, prefix + ".txt", strings.ToLower(prefix), strings.ToLower(prefix) + ".txt"} {
+		f, err = os.Open(filepath.Join(string(d), name))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
+	}
+	if f == nil {
+		return false, nil
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entry, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(entry, suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
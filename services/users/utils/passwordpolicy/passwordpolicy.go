@@ -0,0 +1,182 @@
+// Package passwordpolicy check new password against configurable rules:
+// length, character classes, email substring and deny-list.
+package passwordpolicy
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule identify violated policy rule. It is also used as translation key.
+type Rule string
+
+const (
+	RuleMinLength Rule = "password_min_length"
+	RuleMaxLength Rule = "password_max_length"
+	RuleUpper     Rule = "password_upper"
+	RuleLower     Rule = "password_lower"
+	RuleDigit     Rule = "password_digit"
+	RuleSymbol    Rule = "password_symbol"
+	RuleEmail     Rule = "password_email"
+	RuleDenied    Rule = "password_denied"
+	RuleReused    Rule = "password_reused"
+)
+
+// Violation describe single rule password does not satisfy
+type Violation struct {
+	Rule  Rule
+	Param string
+}
+
+// Policy hold password rules
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// ForbidEmail reject password containing local part of user email
+	ForbidEmail bool
+	// History is number of previous password which may not be reused
+	History int
+	// DenyList reject common or breached password when set
+	DenyList DenyList
+}
+
+// Default is policy used when no environment variable was set
+func Default() *Policy {
+	return &Policy{
+		MinLength:   8,
+		MaxLength:   128,
+		ForbidEmail: true,
+		History:     5,
+	}
+}
+
+// FromEnv load policy from PASSWORD_* environment variables. Missing
+// variable fallback to Default.
+//
+//	PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH, PASSWORD_HISTORY  integer
+//	PASSWORD_REQUIRE       comma separated classes: upper,lower,digit,symbol
+//	PASSWORD_FORBID_EMAIL  boolean
+//	PASSWORD_DENY_LIST     path of deny-list file or prefix directory
+func FromEnv() (*Policy, error) {
+	p := Default()
+	for env, dst := range map[string]*int{
+		"PASSWORD_MIN_LENGTH": &p.MinLength,
+		"PASSWORD_MAX_LENGTH": &p.MaxLength,
+		"PASSWORD_HISTORY":    &p.History,
+	} {
+		if value, ok := os.LookupEnv(env); ok && value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("passwordpolicy: invalid %s", env)
+			}
+			*dst = n
+		}
+	}
+
+	if value, ok := os.LookupEnv("PASSWORD_REQUIRE"); ok {
+		for class := range strings.SplitSeq(value, ",") {
+			switch strings.TrimSpace(class) {
+			case "":
+			case "upper":
+				p.RequireUpper = true
+			case "lower":
+				p.RequireLower = true
+			case "digit":
+				p.RequireDigit = true
+			case "symbol":
+				p.RequireSymbol = true
+			default:
+				return nil, fmt.Errorf("passwordpolicy: unknown character class %q", class)
+			}
+		}
+	}
+
+	if value, ok := os.LookupEnv("PASSWORD_FORBID_EMAIL"); ok && value != "" {
+		forbid, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("passwordpolicy: invalid PASSWORD_FORBID_EMAIL")
+		}
+		p.ForbidEmail = forbid
+	}
+
+	if path, ok := os.LookupEnv("PASSWORD_DENY_LIST"); ok && path != "" {
+		denyList, err := LoadDenyList(path)
+		if err != nil {
+			return nil, err
+		}
+		p.DenyList = denyList
+	}
+
+	if p.MaxLength != 0 && p.MaxLength < p.MinLength {
+		return nil, fmt.Errorf("passwordpolicy: max length is less than min length")
+	}
+	return p, nil
+}
+
+// Check return every rule password violate. Password history is checked
+// by caller since it require stored hashes.
+func (p *Policy) Check(password, email string) ([]Violation, error) {
+	violations := make([]Violation, 0)
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, Violation{Rule: RuleMinLength, Param: strconv.Itoa(p.MinLength)})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{Rule: RuleMaxLength, Param: strconv.Itoa(p.MaxLength)})
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	for _, class := range []struct {
+		required, found bool
+		rule            Rule
+	}{
+		{p.RequireUpper, upper, RuleUpper},
+		{p.RequireLower, lower, RuleLower},
+		{p.RequireDigit, digit, RuleDigit},
+		{p.RequireSymbol, symbol, RuleSymbol},
+	} {
+		if class.required && !class.found {
+			violations = append(violations, Violation{Rule: class.rule})
+		}
+	}
+
+	if p.ForbidEmail && email != "" {
+		local, _, _ := strings.Cut(strings.ToLower(email), "@")
+		if len(local) >= 3 && strings.Contains(strings.ToLower(password), local) {
+			violations = append(violations, Violation{Rule: RuleEmail})
+		}
+	}
+
+	if p.DenyList != nil {
+		denied, err := p.DenyList.Contains(password)
+		if err != nil {
+			return nil, err
+		}
+		if denied {
+			violations = append(violations, Violation{Rule: RuleDenied})
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,144 @@
+package passwordpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+)
+
+func rules(violations []Violation) []Rule {
+	r := make([]Rule, 0, len(violations))
+	for _, v := range violations {
+		r = append(r, v.Rule)
+	}
+	return r
+}
+
+func TestCheck(t *testing.T) {
+	p := &Policy{
+		MinLength:     8,
+		MaxLength:     16,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		ForbidEmail:   true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     []Rule
+	}{
+		{"valid", "Str0ng!Pass", []Rule{}},
+		{"short", "S0!a", []Rule{RuleMinLength}},
+		{"long", "Str0ng!Password1234", []Rule{RuleMaxLength}},
+		{"no upper", "str0ng!pass", []Rule{RuleUpper}},
+		{"no lower", "STR0NG!PASS", []Rule{RuleLower}},
+		{"no digit", "Strong!Pass", []Rule{RuleDigit}},
+		{"no symbol", "Str0ngPass", []Rule{RuleSymbol}},
+		{"email", "Budi!2024x", []Rule{RuleEmail}},
+		{"many", "abc", []Rule{RuleMinLength, RuleUpper, RuleDigit, RuleSymbol}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := p.Check(tt.password, "budi@school.id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rules(violations); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDenyList(t *testing.T) {
+	dir := t.TempDir()
+
+	// "P@ssw0rd" listed as SHA-1 hash with breach count
+	path := filepath.Join(dir, "deny.txt")
+	content := "# common passwords\npassword\nQwerty123\n21BD12DC183F740EE76F27B78EB39C8AD972A757:52579\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadDenyList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for password, want := range map[string]bool{
+		"password":  true,
+		"PASSWORD":  true,
+		"qwerty123": true,
+		"P@ssw0rd":  true,
+		"p@ssw0rd":  false,
+		"unlisted":  false,
+	} {
+		got, err := list.Contains(password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Contains(%q) = %v, want %v", password, got, want)
+		}
+	}
+}
+
+func TestLoadDenyListPrefixDir(t *testing.T) {
+	dir := t.TempDir()
+
+	// SHA-1 of "P@ssw0rd" is 21BD12DC183F740EE76F27B78EB39C8AD972A757
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\n2DC183F740EE76F27B78EB39C8AD972A757:52579\n"
+	if err := os.WriteFile(filepath.Join(dir, "21BD1"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadDenyList(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := list.Contains("P@ssw0rd"); err != nil || !got {
+		t.Errorf("Contains(P@ssw0rd) = %v, %v, want true", got, err)
+	}
+	if got, err := list.Contains("unlisted"); err != nil || got {
+		t.Errorf("Contains(unlisted) = %v, %v, want false", got, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_REQUIRE", "upper, digit")
+	t.Setenv("PASSWORD_HISTORY", "3")
+	t.Setenv("PASSWORD_FORBID_EMAIL", "false")
+
+	p, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.MinLength != 12 || p.History != 3 || p.ForbidEmail {
+		t.Errorf("unexpected policy %+v", p)
+	}
+	if !p.RequireUpper || !p.RequireDigit || p.RequireLower || p.RequireSymbol {
+		t.Errorf("unexpected character classes %+v", p)
+	}
+
+	t.Setenv("PASSWORD_REQUIRE", "emoji")
+	if _, err := FromEnv(); err == nil {
+		t.Error("expected error on unknown character class")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	idLoc := id.New()
+	trans, _ := ut.New(idLoc, idLoc).GetTranslator("id")
+	if err := RegisterTranslations(trans); err != nil {
+		t.Fatal(err)
+	}
+	got := Violation{Rule: RuleMinLength, Param: "8"}.Translate(trans)
+	if want := "Password harus minimal 8 karakter"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package passwordpolicy
+
+import (
+	ut "github.com/go-playground/universal-translator"
+)
+
+// Indonesian message of every rule, {0} is rule param
+var translations = map[Rule]string{
+	RuleMinLength: "Password harus minimal {0} karakter",
+	RuleMaxLength: "Password harus maksimal {0} karakter",
+	RuleUpper:     "Password harus mengandung huruf besar",
+	RuleLower:     "Password harus mengandung huruf kecil",
+	RuleDigit:     "Password harus mengandung angka",
+	RuleSymbol:    "Password harus mengandung simbol",
+	RuleEmail:     "Password tidak boleh mengandung email",
+	RuleDenied:    "Password terlalu umum atau pernah bocor",
+	RuleReused:    "Password sudah pernah digunakan, gunakan password lain",
+}
+
+// RegisterTranslations add message of every rule to trans
+func RegisterTranslations(trans ut.Translator) error {
+	for rule, text := range translations {
+		if err := trans.Add(string(rule), text, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Translate return localized message of v
+func (v Violation) Translate(trans ut.Translator) string {
+	msg, err := trans.T(string(v.Rule), v.Param)
+	if err != nil {
+		return string(v.Rule)
+	}
+	return msg
+}
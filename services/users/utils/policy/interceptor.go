@@ -0,0 +1,109 @@
+package policy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrMissingToken     = status.Error(codes.Unauthenticated, "missing bearer token")
+	ErrInvalidToken     = status.Error(codes.Unauthenticated, "invalid token")
+	ErrPermissionDenied = status.Error(codes.PermissionDenied, "permission denied")
+)
+
+type contextKey int
+
+const (
+	subjectKey contextKey = iota
+	roleKey
+	claimsKey
+)
+
+// UnaryServerInterceptor enforce Methods policy on every unary call.
+// Caller is authenticated using bearer token from authorization metadata
+// and authorized using role claim inside it.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		rule, ok := Methods[info.FullMethod]
+		if !ok {
+			return nil, ErrPermissionDenied
+		}
+		if rule.Public {
+			return handler(ctx, req)
+		}
+
+		accessToken, ok := bearerToken(ctx)
+		if !ok {
+			return nil, ErrMissingToken
+		}
+		claims, err := token.VerifyToken(ctx, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		role := db.UserRole(claims.Role)
+		switch claims.GetTokenType() {
+		case token.TokenTypeAccess:
+			if !rule.Allows(claims.Sub, role, req) {
+				return nil, ErrPermissionDenied
+			}
+		case token.TokenTypeMfaEnrollment:
+			if !rule.AllowsEnrollment(claims.Sub, req) {
+				return nil, ErrPermissionDenied
+			}
+		default:
+			return nil, ErrInvalidToken
+		}
+
+		ctx = context.WithValue(ctx, subjectKey, claims.Sub)
+		ctx = context.WithValue(ctx, roleKey, role)
+		ctx = context.WithValue(ctx, claimsKey, claims)
+		return handler(ctx, req)
+	}
+}
+
+// Subject return authenticated user id from context
+func Subject(ctx context.Context) (string, bool) {
+	sub, ok := ctx.Value(subjectKey).(string)
+	return sub, ok
+}
+
+// Claims return claims of token which authenticated caller
+func Claims(ctx context.Context) (*token.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*token.Claims)
+	return claims, ok
+}
+
+// Role return authenticated user role from context
+func Role(ctx context.Context) (db.UserRole, bool) {
+	role, ok := ctx.Value(roleKey).(db.UserRole)
+	return role, ok
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,102 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Rule describe who may invoke a gRPC method
+type Rule struct {
+	// Public method does not require authentication
+	Public bool
+	// Roles allowed to invoke method
+	Roles []db.UserRole
+	// Self allow user to invoke method against their own id
+	// regardless their role
+	Self bool
+	// Enrollment allow MFA enrollment token to invoke method against id
+	// it was issued for
+	Enrollment bool
+}
+
+var (
+	staff        = []db.UserRole{db.UserRoleStaff}
+	staffTeacher = []db.UserRole{db.UserRoleStaff, db.UserRoleTeacher}
+)
+
+// Methods is access policy for every UserService method. Method which
+// is not listed here is denied.
+var Methods = map[string]Rule{
+	pbusers.UserService_CreateOneUser_FullMethodName:         {Roles: staff},
+	pbusers.UserService_GetOneUser_FullMethodName:            {Roles: staffTeacher, Self: true},
+	pbusers.UserService_GetManyUser_FullMethodName:           {Roles: staffTeacher},
+	pbusers.UserService_SearchUsers_FullMethodName:           {Roles: staffTeacher},
+	pbusers.UserService_UpdateOnePasswordUser_FullMethodName: {Roles: staff, Self: true},
+	pbusers.UserService_UpdateOneEmailUser_FullMethodName:    {Roles: staff, Self: true},
+	pbusers.UserService_UpdateOneRoleUser_FullMethodName:     {Roles: staff},
+	pbusers.UserService_DeleteSoftOneUser_FullMethodName:     {Roles: staff},
+	pbusers.UserService_DeleteHardOneUser_FullMethodName:     {Roles: staff},
+
+	// Credential contain password hash, never exposed to any user
+	pbusers.UserService_GetOneCredentialUserByEmail_FullMethodName: {},
+
+	// Auth services
+	pbusers.UserService_LoginUser_FullMethodName:        {Public: true},
+	pbusers.UserService_VerifyTokenUser_FullMethodName:  {Public: true},
+	pbusers.UserService_RefreshTokenUser_FullMethodName: {Public: true},
+	pbusers.UserService_GetJwksUser_FullMethodName:      {Public: true},
+	pbusers.UserService_UnlockUser_FullMethodName:       {Roles: staff},
+
+	// Session services, logout is authenticated by refresh token
+	pbusers.UserService_LogoutUser_FullMethodName:        {Public: true},
+	pbusers.UserService_ListSessionsUser_FullMethodName:  {Roles: staff, Self: true},
+	pbusers.UserService_RevokeSessionUser_FullMethodName: {Roles: staff, Self: true},
+
+	// Password reset services, authenticated by reset token
+	pbusers.UserService_RequestPasswordReset_FullMethodName: {Public: true},
+	pbusers.UserService_ConfirmPasswordReset_FullMethodName: {Public: true},
+
+	// Email verification services, authenticated by verification token
+	pbusers.UserService_VerifyEmailUser_FullMethodName: {Public: true},
+
+	// Two-factor authentication services, challenge is authenticated by
+	// MFA token and secret is only revealed to its owner
+	pbusers.UserService_VerifyMfaUser_FullMethodName:          {Public: true},
+	pbusers.UserService_EnrollTotpUser_FullMethodName:         {Self: true, Enrollment: true},
+	pbusers.UserService_ConfirmTotpUser_FullMethodName:        {Self: true, Enrollment: true},
+	pbusers.UserService_IssueMfaEnrollmentUser_FullMethodName: {Roles: staff},
+	pbusers.UserService_DisableTotpUser_FullMethodName:        {Roles: staff, Self: true},
+	pbusers.UserService_UpdateMfaRoleUser_FullMethodName:      {Roles: staff},
+
+	// Health check is probed by orchestrator without credential
+	healthpb.Health_Check_FullMethodName: {Public: true},
+}
+
+// AllowsEnrollment report whether MFA enrollment token issued for sub
+// may invoke method with req
+func (r Rule) AllowsEnrollment(sub string, req any) bool {
+	target, ok := req.(idGetter)
+	return r.Enrollment && ok && target.GetId() == sub
+}
+
+// idGetter implemented by every request which target single user
+type idGetter interface {
+	GetId() string
+}
+
+// Allows report whether user with given id and role may invoke method
+// with req
+func (r Rule) Allows(sub string, role db.UserRole, req any) bool {
+	if r.Public || slices.Contains(r.Roles, role) {
+		return true
+	}
+	if r.Self {
+		if target, ok := req.(idGetter); ok && target.GetId() == sub {
+			return true
+		}
+	}
+	return false
+}
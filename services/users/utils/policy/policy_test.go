@@ -0,0 +1,80 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/policy"
+)
+
+func TestRuleAllows(t *testing.T) {
+	sub := "0197a1b2-0000-7000-8000-000000000001"
+	other := "0197a1b2-0000-7000-8000-000000000002"
+
+	tests := []struct {
+		name   string
+		method string
+		role   db.UserRole
+		req    any
+		want   bool
+	}{
+		{"staff delete hard", pbusers.UserService_DeleteHardOneUser_FullMethodName, db.UserRoleStaff, &pbusers.DeleteHardOneUserRequest{Id: other}, true},
+		{"student delete hard", pbusers.UserService_DeleteHardOneUser_FullMethodName, db.UserRoleStudent, &pbusers.DeleteHardOneUserRequest{Id: other}, false},
+		{"student delete hard self", pbusers.UserService_DeleteHardOneUser_FullMethodName, db.UserRoleStudent, &pbusers.DeleteHardOneUserRequest{Id: sub}, false},
+		{"student update own password", pbusers.UserService_UpdateOnePasswordUser_FullMethodName, db.UserRoleStudent, &pbusers.UpdateOnePasswordUserRequest{Id: sub}, true},
+		{"student update other password", pbusers.UserService_UpdateOnePasswordUser_FullMethodName, db.UserRoleStudent, &pbusers.UpdateOnePasswordUserRequest{Id: other}, false},
+		{"teacher update own role", pbusers.UserService_UpdateOneRoleUser_FullMethodName, db.UserRoleTeacher, &pbusers.UpdateOneRoleUserRequest{Id: sub}, false},
+		{"staff update role", pbusers.UserService_UpdateOneRoleUser_FullMethodName, db.UserRoleStaff, &pbusers.UpdateOneRoleUserRequest{Id: other}, true},
+		{"staff get credential", pbusers.UserService_GetOneCredentialUserByEmail_FullMethodName, db.UserRoleStaff, &pbusers.GetOneCredentialUserByEmailRequest{}, false},
+		{"parent list user", pbusers.UserService_GetManyUser_FullMethodName, db.UserRoleParent, &pbusers.GetManyUserRequest{}, false},
+		{"anyone login", pbusers.UserService_LoginUser_FullMethodName, "", &pbusers.LoginUserRequest{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := policy.Methods[tt.method]
+			if !ok {
+				t.Fatalf("method %s has no policy", tt.method)
+			}
+			if got := rule.Allows(sub, tt.role, tt.req); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleAllowsEnrollment(t *testing.T) {
+	sub := "0197a1b2-0000-7000-8000-000000000001"
+	other := "0197a1b2-0000-7000-8000-000000000002"
+
+	tests := []struct {
+		name   string
+		method string
+		req    any
+		want   bool
+	}{
+		{"enroll self", pbusers.UserService_EnrollTotpUser_FullMethodName, &pbusers.EnrollTotpUserRequest{Id: sub}, true},
+		{"confirm self", pbusers.UserService_ConfirmTotpUser_FullMethodName, &pbusers.ConfirmTotpUserRequest{Id: sub}, true},
+		{"enroll other", pbusers.UserService_EnrollTotpUser_FullMethodName, &pbusers.EnrollTotpUserRequest{Id: other}, false},
+		{"get self", pbusers.UserService_GetOneUser_FullMethodName, &pbusers.GetOneUserRequest{Id: sub}, false},
+		{"disable self", pbusers.UserService_DisableTotpUser_FullMethodName, &pbusers.DisableTotpUserRequest{Id: sub}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Methods[tt.method].AllowsEnrollment(sub, tt.req); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEveryMethodHasPolicy(t *testing.T) {
+	for _, m := range pbusers.UserService_ServiceDesc.Methods {
+		name := "/" + pbusers.UserService_ServiceDesc.ServiceName + "/" + m.MethodName
+		if _, ok := policy.Methods[name]; !ok {
+			t.Errorf("method %s has no policy", name)
+		}
+	}
+}
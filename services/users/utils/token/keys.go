@@ -0,0 +1,165 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoSigningKey = errors.New("token: no private key found")
+
+// JWK is public key in JSON Web Key format
+type JWK struct {
+	Kty string
+	Kid string
+	Alg string
+	Use string
+	N   string
+	E   string
+	Crv string
+	X   string
+}
+
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+}
+
+type verifyKey struct {
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+}
+
+type keySet struct {
+	signing *signingKey
+	verify  map[string]*verifyKey
+}
+
+// keys hold currently active key set. When nil token are signed using
+// HS256 with SECRET environment variable.
+var keys atomic.Pointer[keySet]
+
+// LoadKeys load every PEM encoded RSA or Ed25519 key inside dir and
+// atomically replace active key set. File name without extension is
+// used as key id. Private key with greatest key id become signing key
+// and every key, private or public, is used to verify token. Previous
+// key set is kept when loading failed.
+func LoadKeys(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	slices.Sort(paths)
+
+	set := &keySet{verify: make(map[string]*verifyKey, len(paths))}
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		priv, pub, err := readKeyFile(path)
+		if err != nil {
+			return fmt.Errorf("token: load key %s: %w", kid, err)
+		}
+		method, err := signingMethodFor(pub)
+		if err != nil {
+			return fmt.Errorf("token: load key %s: %w", kid, err)
+		}
+		set.verify[kid] = &verifyKey{method: method, key: pub}
+		if priv != nil {
+			set.signing = &signingKey{kid: kid, method: method, key: priv}
+		}
+	}
+	if set.signing == nil {
+		return ErrNoSigningKey
+	}
+
+	keys.Store(set)
+	return nil
+}
+
+// PublicKeys return every active verification key as JWK
+func PublicKeys() []JWK {
+	set := keys.Load()
+	if set == nil {
+		return []JWK{}
+	}
+
+	jwks := make([]JWK, 0, len(set.verify))
+	for kid, k := range set.verify {
+		jwk := JWK{Kid: kid, Alg: k.method.Alg(), Use: "sig"}
+		switch pub := k.key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks = append(jwks, jwk)
+	}
+	slices.SortFunc(jwks, func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return jwks
+}
+
+func readKeyFile(path string) (crypto.Signer, crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, nil, errors.New("unsupported private key")
+		}
+		return signer, signer.Public(), nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, key.Public(), nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, key, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+	}
+}
+
+func signingMethodFor(pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+}
@@ -0,0 +1,136 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func writeKey(t *testing.T, dir, kid string, key any) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupKeys(t *testing.T) string {
+	t.Helper()
+	t.Setenv("AUDIENCES", "web")
+	t.Cleanup(func() { keys.Store(nil) })
+	return t.TempDir()
+}
+
+func TestLoadKeysRotation(t *testing.T) {
+	dir := setupKeys(t)
+	sub := Subject{ID: "dummy", Role: "staff", SessionID: "session"}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "2026-01-01", rsaKey)
+	if err := LoadKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+	oldToken, _, err := CreateToken(TokenTypeAccess, sub, time.Hour, []string{"web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate to newer Ed25519 key, old token must still valid
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "2026-02-01", edKey)
+	if err := LoadKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+	newToken, _, err := CreateToken(TokenTypeAccess, sub, time.Hour, []string{"web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tokenString := range []string{oldToken, newToken} {
+		if _, err := VerifyToken(t.Context(), tokenString); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if jwks := PublicKeys(); len(jwks) != 2 || jwks[1].Kty != "OKP" || jwks[0].Kty != "RSA" {
+		t.Errorf("unexpected jwks %+v", jwks)
+	}
+
+	// Retire old key
+	if err := os.Remove(filepath.Join(dir, "2026-01-01.pem")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyToken(t.Context(), oldToken); err == nil {
+		t.Error("expected retired key to be rejected")
+	}
+	if _, err := VerifyToken(t.Context(), newToken); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLoadKeysWithoutPrivateKey(t *testing.T) {
+	dir := setupKeys(t)
+	if err := LoadKeys(dir); err != ErrNoSigningKey {
+		t.Errorf("got %v, want %v", err, ErrNoSigningKey)
+	}
+	if keys.Load() != nil {
+		t.Error("expected key set to stay unloaded")
+	}
+}
+
+func TestVerifyTokenLegacySecret(t *testing.T) {
+	dir := setupKeys(t)
+	t.Setenv("SECRET", "secret")
+	sub := Subject{ID: "dummy", Role: "staff", SessionID: "session"}
+
+	// Token signed with shared secret before keys loaded
+	hsToken, _, err := CreateToken(TokenTypeAccess, sub, time.Hour, []string{"web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "current", edKey)
+	if err := LoadKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Legacy token is refused once keys loaded unless migration window
+	// is explicitly open
+	if _, err := VerifyToken(t.Context(), hsToken); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected legacy token refused, got %v", err)
+	}
+
+	t.Setenv("TOKEN_LEGACY_SECRET_UNTIL", time.Now().Add(time.Hour).Format(time.RFC3339))
+	if _, err := VerifyToken(t.Context(), hsToken); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	t.Setenv("TOKEN_LEGACY_SECRET_UNTIL", time.Now().Add(-time.Hour).Format(time.RFC3339))
+	if _, err := VerifyToken(t.Context(), hsToken); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected legacy token refused after window, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+)
+
+// RevocationStore keep track of token revoked before their expiration
+type RevocationStore interface {
+	// RevokeToken revoke single token by its id until exp
+	RevokeToken(ctx context.Context, jti string, exp time.Time) error
+	// RevokeSubject revoke every token of sub issued before given time
+	RevokeSubject(ctx context.Context, sub string, before time.Time) error
+	// IsRevoked report whether token was revoked
+	IsRevoked(ctx context.Context, jti, sub string, iat time.Time) (bool, error)
+}
+
+// TxRevocationStore is RevocationStore kept in users database, so its
+// revocation can be committed along with caller transaction
+type TxRevocationStore interface {
+	RevocationStore
+	// WithTx return store running its queries using transaction queries q
+	WithTx(q db.Querier) RevocationStore
+}
+
+type revocationHolder struct {
+	store RevocationStore
+}
+
+// revocations hold store consulted by VerifyToken. When nil revocation
+// is not checked.
+var revocations atomic.Pointer[revocationHolder]
+
+// SetRevocationStore set store consulted by VerifyToken
+func SetRevocationStore(s RevocationStore) {
+	if s == nil {
+		revocations.Store(nil)
+		return
+	}
+	revocations.Store(&revocationHolder{store: s})
+}
+
+// RevokeToken revoke single token using configured store
+func RevokeToken(ctx context.Context, jti string, exp time.Time) error {
+	if h := revocations.Load(); h != nil {
+		return h.store.RevokeToken(ctx, jti, exp)
+	}
+	return nil
+}
+
+// RevokeSubject revoke every token of sub issued until now using
+// configured store
+func RevokeSubject(ctx context.Context, sub string) error {
+	if h := revocations.Load(); h != nil {
+		return h.store.RevokeSubject(ctx, sub, time.Now())
+	}
+	return nil
+}
+
+// RevocationJoinsTx report whether configured store can join caller
+// transaction through RevokeSubjectTx
+func RevocationJoinsTx() bool {
+	h := revocations.Load()
+	if h == nil {
+		return false
+	}
+	_, ok := h.store.(TxRevocationStore)
+	return ok
+}
+
+// RevokeSubjectTx revoke every token of sub issued until now using
+// transaction queries q. Configured store must be able to join
+// transaction, see RevocationJoinsTx.
+func RevokeSubjectTx(ctx context.Context, q db.Querier, sub string) error {
+	h := revocations.Load()
+	if h == nil {
+		return nil
+	}
+	store, ok := h.store.(TxRevocationStore)
+	if !ok {
+		return errors.New("revocation store can not join transaction")
+	}
+	return store.WithTx(q).RevokeSubject(ctx, sub, time.Now())
+}
+
+func isRevoked(ctx context.Context, c *Claims) (bool, error) {
+	if h := revocations.Load(); h != nil {
+		return h.store.IsRevoked(ctx, c.Jti, c.Sub, c.Iat.Time)
+	}
+	return false, nil
+}
@@ -0,0 +1,62 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+)
+
+var _ token.RevocationStore = (*memory)(nil)
+
+type memory struct {
+	mu       sync.RWMutex
+	tokens   map[string]time.Time
+	subjects map[string]time.Time
+}
+
+// NewMemory create revocation store kept in process memory. It is only
+// suitable for single instance deployment and local development.
+func NewMemory() token.RevocationStore {
+	return &memory{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]time.Time),
+	}
+}
+
+func (m *memory) RevokeToken(ctx context.Context, jti string, exp time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Drop expired entries
+	now := time.Now()
+	for id, tokenExp := range m.tokens {
+		if tokenExp.Before(now) {
+			delete(m.tokens, id)
+		}
+	}
+	m.tokens[jti] = exp
+	return nil
+}
+
+func (m *memory) RevokeSubject(ctx context.Context, sub string, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if before.After(m.subjects[sub]) {
+		m.subjects[sub] = before
+	}
+	return nil
+}
+
+func (m *memory) IsRevoked(ctx context.Context, jti, sub string, iat time.Time) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.tokens[jti]; ok {
+		return true, nil
+	}
+	if before, ok := m.subjects[sub]; ok && iat.Before(before) {
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,111 @@
+package revocation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/memdb"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token/revocation"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := t.Context()
+	store := revocation.NewMemory()
+	now := time.Now()
+
+	if err := store.RevokeToken(ctx, "revoked", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RevokeSubject(ctx, "user", now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		jti  string
+		sub  string
+		iat  time.Time
+		want bool
+	}{
+		{"revoked token id", "revoked", "other", now, true},
+		{"issued before subject revocation", "valid", "user", now.Add(-time.Second), true},
+		{"issued after subject revocation", "valid", "user", now.Add(time.Second), false},
+		{"other subject", "valid", "other", now.Add(-time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.IsRevoked(ctx, tt.jti, tt.sub, tt.iat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTokenRevoked(t *testing.T) {
+	t.Setenv("SECRET", "viwoqjrb20q9jb209jbvaijbioji340920gbwij")
+	t.Setenv("AUDIENCES", "web")
+	token.SetRevocationStore(revocation.NewMemory())
+	t.Cleanup(func() { token.SetRevocationStore(nil) })
+
+	sub := token.Subject{ID: "dummy", Role: "student", SessionID: "session"}
+	first, claims, err := token.CreateToken(token.TokenTypeAccess, sub, time.Hour, []string{"web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := token.CreateToken(token.TokenTypeAccess, sub, time.Hour, []string{"web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := token.RevokeToken(t.Context(), claims.Jti, claims.Exp.Time); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.VerifyToken(t.Context(), first); err == nil {
+		t.Error("expected revoked token to be rejected")
+	}
+	if _, err := token.VerifyToken(t.Context(), second); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := token.RevokeSubject(t.Context(), "dummy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.VerifyToken(t.Context(), second); err == nil {
+		t.Error("expected token issued before subject revocation to be rejected")
+	}
+}
+
+func TestRevokeSubjectTx(t *testing.T) {
+	t.Cleanup(func() { token.SetRevocationStore(nil) })
+
+	token.SetRevocationStore(revocation.NewMemory())
+	if token.RevocationJoinsTx() {
+		t.Error("expected memory store not joining transaction")
+	}
+
+	store := memdb.New()
+	token.SetRevocationStore(revocation.NewPostgres(store))
+	if !token.RevocationJoinsTx() {
+		t.Fatal("expected postgres store joining transaction")
+	}
+	userID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateOneUser(t.Context(), &db.CreateOneUserParams{ID: userID, Email: "user@email.com", Role: db.UserRoleStudent}); err != nil {
+		t.Fatal(err)
+	}
+	if err := token.RevokeSubjectTx(t.Context(), store, userID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetOneUserTokenRevocation(t.Context(), userID); err != nil {
+		t.Errorf("expected revocation stored using transaction queries, got %v", err)
+	}
+}
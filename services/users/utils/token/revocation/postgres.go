@@ -0,0 +1,83 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nurfianqodar/school-microservices/services/users/db"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/token"
+)
+
+var _ token.TxRevocationStore = (*postgres)(nil)
+
+type postgres struct {
+	q db.Querier
+}
+
+// NewPostgres create revocation store backed by token_revocations and
+// user_token_revocations table
+func NewPostgres(q db.Querier) token.RevocationStore {
+	return &postgres{q: q}
+}
+
+func (p *postgres) WithTx(q db.Querier) token.RevocationStore {
+	return &postgres{q: q}
+}
+
+func (p *postgres) RevokeToken(ctx context.Context, jti string, exp time.Time) error {
+	id, err := uuid.Parse(jti)
+	if err != nil {
+		return err
+	}
+
+	// Drop expired entries
+	if _, err := p.q.DeleteExpiredTokenRevocation(ctx); err != nil {
+		return err
+	}
+	return p.q.CreateOneTokenRevocation(ctx, &db.CreateOneTokenRevocationParams{
+		Jti:       id,
+		ExpiresAt: pgtype.Timestamptz{Time: exp, Valid: true},
+	})
+}
+
+func (p *postgres) RevokeSubject(ctx context.Context, sub string, before time.Time) error {
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return err
+	}
+	return p.q.UpsertOneUserTokenRevocation(ctx, &db.UpsertOneUserTokenRevocationParams{
+		UserID:       userID,
+		IssuedBefore: pgtype.Timestamptz{Time: before, Valid: true},
+	})
+}
+
+func (p *postgres) IsRevoked(ctx context.Context, jti, sub string, iat time.Time) (bool, error) {
+	id, err := uuid.Parse(jti)
+	if err != nil {
+		return true, nil
+	}
+	count, err := p.q.CountTokenRevocation(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if count != 0 {
+		return true, nil
+	}
+
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return true, nil
+	}
+	before, err := p.q.GetOneUserTokenRevocation(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return iat.Before(before.Time), nil
+}
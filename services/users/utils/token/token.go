@@ -1,12 +1,18 @@
 package token
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/nurfianqodar/school-microservices/utils/errs"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -17,18 +23,40 @@ type TokenType int8
 const (
 	TokenTypeAccess TokenType = iota
 	TokenTypeRefresh
+	// TokenTypeMfa is short lived challenge issued after password was
+	// verified and before two-factor authentication completed
+	TokenTypeMfa
+	// TokenTypeMfaEnrollment is issued by staff and delivered out of band
+	// so user whose role require MFA can enroll before first login
+	TokenTypeMfaEnrollment
 )
 
+// DefaultAudience used when AUDIENCES environment variable was not set
+const DefaultAudience = "web"
+
 var _ jwt.Claims = (*Claims)(nil)
 
+// Claims of issued token. Registered claims use JWT NumericDate and
+// string or array audience so token can be verified by standard JWT
+// library using published key set.
 type Claims struct {
-	Exp time.Time `json:"exp"`
-	Iat time.Time `json:"iat"`
-	Nbf time.Time `json:"nbf"`
-	Iss string    `json:"iss"`
-	Sub string    `json:"sub"`
-	Aud []string  `json:"aud"`
-	Typ TokenType `json:"typ"`
+	Exp  *jwt.NumericDate `json:"exp"`
+	Iat  *jwt.NumericDate `json:"iat"`
+	Nbf  *jwt.NumericDate `json:"nbf"`
+	Iss  string           `json:"iss"`
+	Sub  string           `json:"sub"`
+	Aud  jwt.ClaimStrings `json:"aud"`
+	Typ  TokenType        `json:"typ"`
+	Jti  string           `json:"jti"`
+	Sid  string           `json:"sid"`
+	Role string           `json:"role"`
+}
+
+// Subject identify token owner and session it belong to
+type Subject struct {
+	ID        string
+	Role      string
+	SessionID string
 }
 
 func (c *Claims) GetAudience() (jwt.ClaimStrings, error) {
@@ -36,11 +64,11 @@ func (c *Claims) GetAudience() (jwt.ClaimStrings, error) {
 }
 
 func (c *Claims) GetExpirationTime() (*jwt.NumericDate, error) {
-	return jwt.NewNumericDate(c.Exp), nil
+	return c.Exp, nil
 }
 
 func (c *Claims) GetIssuedAt() (*jwt.NumericDate, error) {
-	return jwt.NewNumericDate(c.Iat), nil
+	return c.Iat, nil
 }
 
 func (c *Claims) GetIssuer() (string, error) {
@@ -48,7 +76,7 @@ func (c *Claims) GetIssuer() (string, error) {
 }
 
 func (c *Claims) GetNotBefore() (*jwt.NumericDate, error) {
-	return jwt.NewNumericDate(c.Nbf), nil
+	return c.Nbf, nil
 }
 
 func (c *Claims) GetSubject() (string, error) {
@@ -59,17 +87,55 @@ func (c *Claims) GetTokenType() TokenType {
 	return c.Typ
 }
 
-func CreateToken(typ TokenType, sub string, expAfter time.Duration, aud []string) (string, error) {
+// Audiences return client audiences token may be issued for. It read
+// comma separated AUDIENCES environment variable.
+func Audiences() []string {
+	env, ok := os.LookupEnv("AUDIENCES")
+	if !ok || strings.TrimSpace(env) == "" {
+		return []string{DefaultAudience}
+	}
+	audiences := make([]string, 0)
+	for aud := range strings.SplitSeq(env, ",") {
+		if aud = strings.TrimSpace(aud); aud != "" {
+			audiences = append(audiences, aud)
+		}
+	}
+	return audiences
+}
+
+// CreateToken create signed token for sub and return it along with
+// generated claims
+func CreateToken(typ TokenType, sub Subject, expAfter time.Duration, aud []string) (string, *Claims, error) {
+	jti, err := uuid.NewV7()
+	if err != nil {
+		log.Printf("error: failed to generate token id. %s\n", err.Error())
+		return "", nil, errs.ErrInternalServer
+	}
+
 	now := time.Now()
-	exp := now.Add(expAfter)
 	c := &Claims{
-		Iss: "api.example.com",
-		Iat: now,
-		Nbf: now,
-		Exp: exp,
-		Sub: sub,
-		Aud: aud,
-		Typ: typ,
+		Iss:  "api.example.com",
+		Iat:  jwt.NewNumericDate(now),
+		Nbf:  jwt.NewNumericDate(now),
+		Exp:  jwt.NewNumericDate(now.Add(expAfter)),
+		Sub:  sub.ID,
+		Aud:  aud,
+		Typ:  typ,
+		Jti:  jti.String(),
+		Sid:  sub.SessionID,
+		Role: sub.Role,
+	}
+
+	// Sign using active signing key and fallback to shared secret
+	if set := keys.Load(); set != nil {
+		token := jwt.NewWithClaims(set.signing.method, c)
+		token.Header["kid"] = set.signing.kid
+		tokenString, err := token.SignedString(set.signing.key)
+		if err != nil {
+			log.Printf("error: failed to sign token. %s\n", err.Error())
+			return "", nil, errs.ErrInternalServer
+		}
+		return tokenString, c, nil
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
@@ -77,41 +143,110 @@ func CreateToken(typ TokenType, sub string, expAfter time.Duration, aud []string
 	appSecret, ok := os.LookupEnv("SECRET")
 	if !ok {
 		log.Println("error: unable to get SECRET environment variable")
-		return "", errs.ErrInternalServer
+		return "", nil, errs.ErrInternalServer
 	}
 
 	tokenString, err := token.SignedString([]byte(appSecret))
 	if err != nil {
 		log.Printf("error: failed to sign token. %s\n", err.Error())
-		return "", errs.ErrInternalServer
+		return "", nil, errs.ErrInternalServer
 	}
 
-	return tokenString, nil
+	return tokenString, c, nil
 }
 
-func VerifyToken(tokenString string) (*Claims, error) {
+// VerifyToken parse and validate tokenString and make sure it was not
+// revoked
+func VerifyToken(ctx context.Context, tokenString string) (*Claims, error) {
 	var (
 		c  *Claims = new(Claims)
 		ok bool
 	)
 
-	token, err := jwt.ParseWithClaims(tokenString, c, func(t *jwt.Token) (any, error) {
-		appSecret, ok := os.LookupEnv("SECRET")
-		if !ok {
-			log.Fatalln("error: unable to get SECRET environment variable")
-		}
-		return []byte(appSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, c, keyFunc, jwt.WithValidMethods([]string{
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+		jwt.SigningMethodHS256.Alg(),
+	}), jwt.WithExpirationRequired(), jwt.WithIssuedAt())
 	if err != nil {
 		log.Printf("error: failed to parse with claims. %s\n", err.Error())
 		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
+	if c.Iat == nil || c.Nbf == nil {
+		log.Println("error: token has no iat or nbf claim")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
 
 	c, ok = token.Claims.(*Claims)
 	if ok {
+		// Token must be issued for at least one known audience
+		audiences := Audiences()
+		if !slices.ContainsFunc(c.Aud, func(aud string) bool { return slices.Contains(audiences, aud) }) {
+			log.Println("error: token audience is not allowed")
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		revoked, err := isRevoked(ctx, c)
+		if err != nil {
+			log.Printf("error: failed to check token revocation. %s\n", err.Error())
+			return nil, errs.ErrInternalServer
+		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "token revoked")
+		}
 		return c, nil
 	}
 
 	log.Printf("error: incompatible token claims type. found %s\n", reflect.TypeOf(c).Name())
 	return nil, status.Error(codes.Unauthenticated, "invalid token")
 }
+
+// legacySecretAllowed report whether token signed with shared secret is
+// still accepted after key set was loaded. It read RFC 3339 deadline from
+// TOKEN_LEGACY_SECRET_UNTIL environment variable so migration to key set
+// is time boxed, legacy token is refused when it is not set.
+func legacySecretAllowed(now time.Time) bool {
+	env, ok := os.LookupEnv("TOKEN_LEGACY_SECRET_UNTIL")
+	if !ok || env == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, env)
+	if err != nil {
+		log.Printf("error: invalid TOKEN_LEGACY_SECRET_UNTIL value %s\n", env)
+		return false
+	}
+	return now.Before(until)
+}
+
+// keyFunc select verification key by kid header. Token without kid is
+// verified using shared secret only while no key set is loaded or during
+// legacy secret migration window.
+func keyFunc(t *jwt.Token) (any, error) {
+	kid, ok := t.Header["kid"].(string)
+	if !ok {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, errors.New("missing kid header")
+		}
+		if keys.Load() != nil && !legacySecretAllowed(time.Now()) {
+			return nil, errors.New("token signed with shared secret is no longer accepted")
+		}
+		appSecret, ok := os.LookupEnv("SECRET")
+		if !ok {
+			return nil, errors.New("unable to get SECRET environment variable")
+		}
+		return []byte(appSecret), nil
+	}
+
+	set := keys.Load()
+	if set == nil {
+		return nil, errors.New("no verification key loaded")
+	}
+	k, ok := set.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %s", kid)
+	}
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for kid %s", t.Method.Alg(), kid)
+	}
+	return k.key, nil
+}
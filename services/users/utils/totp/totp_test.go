@@ -0,0 +1,70 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nurfianqodar/school-microservices/services/users/utils/totp"
+)
+
+// RFC 6238 appendix B SHA-1 secret
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// Last six digits of RFC 6238 appendix B SHA-1 vectors
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range cases {
+		got, err := totp.Code(rfcSecret, totp.Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("code at %d: got %s want %s", unix, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	step, ok, err := totp.Validate(rfcSecret, "050471", now)
+	if err != nil || !ok || step != totp.Step(now) {
+		t.Errorf("current code rejected: step=%d ok=%v err=%v", step, ok, err)
+	}
+
+	// Code of previous period is accepted within skew
+	if _, ok, _ := totp.Validate(rfcSecret, "050471", now.Add(totp.Period*time.Second)); !ok {
+		t.Error("code within skew rejected")
+	}
+
+	if _, ok, _ := totp.Validate(rfcSecret, "050471", now.Add(time.Hour)); ok {
+		t.Error("stale code accepted")
+	}
+	if _, ok, _ := totp.Validate(rfcSecret, "12345", now); ok {
+		t.Error("short code accepted")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := totp.Code(secret, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	uri := totp.URI("School", "user@email.com", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/School:user@email.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("unexpected uri %s", uri)
+	}
+}
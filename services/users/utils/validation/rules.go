@@ -8,10 +8,59 @@ var r *pbusers.CreateOneUserRequest
 var (
 	ruleCreateOneUserRequest = map[string]string{
 		"Email":    "required,email,max=255",
-		"Password": "required,min=8",
+		"Password": "required",
 		"Role":     "required",
 	}
-	ruleUpdateOnePasswordUserRequest = map[string]string{}
-	ruleUpdateOneEmailUserRequest    = map[string]string{}
-	ruleUpdateOneRoleUserRequest     = map[string]string{}
+	ruleGetManyUserRequest = map[string]string{
+		"PageToken":   "max=512",
+		"EmailPrefix": "max=255",
+	}
+	ruleSearchUsersRequest = map[string]string{
+		"Query": "required,max=255",
+	}
+	ruleUpdateOnePasswordUserRequest = map[string]string{
+		"Id":       "required,uuid",
+		"Password": "required",
+	}
+	ruleUpdateOneEmailUserRequest = map[string]string{
+		"Id":    "required,uuid",
+		"Email": "required,email,max=255",
+	}
+	ruleUpdateOneRoleUserRequest = map[string]string{
+		"Id":   "required,uuid",
+		"Role": "required",
+	}
+	ruleRequestPasswordResetRequest = map[string]string{
+		"Email": "required,email,max=255",
+	}
+	ruleConfirmPasswordResetRequest = map[string]string{
+		"Token":    "required",
+		"Password": "required",
+	}
+	ruleVerifyEmailUserRequest = map[string]string{
+		"Token": "required",
+	}
+	ruleUnlockUserRequest = map[string]string{
+		"Id": "required,uuid",
+	}
+	ruleVerifyMfaUserRequest = map[string]string{
+		"MfaToken": "required",
+		"Code":     "required",
+	}
+	ruleEnrollTotpUserRequest = map[string]string{
+		"Id": "required,uuid",
+	}
+	ruleIssueMfaEnrollmentUserRequest = map[string]string{
+		"Id": "required,uuid",
+	}
+	ruleConfirmTotpUserRequest = map[string]string{
+		"Id":   "required,uuid",
+		"Code": "required,numeric,len=6",
+	}
+	ruleDisableTotpUserRequest = map[string]string{
+		"Id": "required,uuid",
+	}
+	ruleUpdateMfaRoleUserRequest = map[string]string{
+		"Role": "required",
+	}
 )
@@ -7,6 +7,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	idTrans "github.com/go-playground/validator/v10/translations/id"
 	pbusers "github.com/nurfianqodar/school-microservices/services/users/pb/users/v1"
+	"github.com/nurfianqodar/school-microservices/services/users/utils/passwordpolicy"
 )
 
 var (
@@ -25,10 +26,23 @@ func init() {
 	Validate = validator.New()
 
 	idTrans.RegisterDefaultTranslations(Validate, Trans)
+	passwordpolicy.RegisterTranslations(Trans)
 
 	// Register validation rules for gRPC requests
 	Validate.RegisterStructValidationMapRules(ruleCreateOneUserRequest, pbusers.CreateOneUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleGetManyUserRequest, pbusers.GetManyUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleSearchUsersRequest, pbusers.SearchUsersRequest{})
 	Validate.RegisterStructValidationMapRules(ruleUpdateOneEmailUserRequest, pbusers.UpdateOneEmailUserRequest{})
 	Validate.RegisterStructValidationMapRules(ruleUpdateOnePasswordUserRequest, pbusers.UpdateOnePasswordUserRequest{})
 	Validate.RegisterStructValidationMapRules(ruleUpdateOneRoleUserRequest, pbusers.UpdateOneRoleUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleRequestPasswordResetRequest, pbusers.RequestPasswordResetRequest{})
+	Validate.RegisterStructValidationMapRules(ruleConfirmPasswordResetRequest, pbusers.ConfirmPasswordResetRequest{})
+	Validate.RegisterStructValidationMapRules(ruleVerifyEmailUserRequest, pbusers.VerifyEmailUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleUnlockUserRequest, pbusers.UnlockUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleVerifyMfaUserRequest, pbusers.VerifyMfaUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleEnrollTotpUserRequest, pbusers.EnrollTotpUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleConfirmTotpUserRequest, pbusers.ConfirmTotpUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleIssueMfaEnrollmentUserRequest, pbusers.IssueMfaEnrollmentUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleDisableTotpUserRequest, pbusers.DisableTotpUserRequest{})
+	Validate.RegisterStructValidationMapRules(ruleUpdateMfaRoleUserRequest, pbusers.UpdateMfaRoleUserRequest{})
 }
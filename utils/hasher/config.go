@@ -0,0 +1,117 @@
+package hasher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by ConfigFromEnv
+const (
+	EnvConfigFile  = "HASH_CONFIG_FILE"
+	EnvMemory      = "HASH_MEMORY"
+	EnvIterations  = "HASH_ITERATIONS"
+	EnvParallelism = "HASH_PARALLELISM"
+	EnvSaltLength  = "HASH_SALT_LENGTH"
+	EnvKeyLength   = "HASH_KEY_LENGTH"
+)
+
+// fileConfig is JSON representation of Config
+type fileConfig struct {
+	Memory      *uint32 `json:"memory"`
+	Iterations  *uint32 `json:"iterations"`
+	Parallelism *uint8  `json:"parallelism"`
+	SaltLength  *uint32 `json:"salt_length"`
+	KeyLength   *uint32 `json:"key_length"`
+}
+
+// LoadConfig load hash config from JSON file pointed by HASH_CONFIG_FILE
+// environment variable, or from HASH_* environment variables when it was
+// not set. Missing parameter fallback to DefaultConfig.
+func LoadConfig() (*Config, error) {
+	if path, ok := os.LookupEnv(EnvConfigFile); ok && path != "" {
+		return ConfigFromFile(path)
+	}
+	return ConfigFromEnv()
+}
+
+// ConfigFromFile read hash config from JSON file at path
+func ConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("hasher: read config: %w", err)
+	}
+	var fc fileConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("hasher: parse config: %w", err)
+	}
+
+	c := *DefaultConfig
+	if fc.Memory != nil {
+		c.Memory = *fc.Memory
+	}
+	if fc.Iterations != nil {
+		c.Iterations = *fc.Iterations
+	}
+	if fc.Parallelism != nil {
+		c.Parallelism = *fc.Parallelism
+	}
+	if fc.SaltLength != nil {
+		c.SaltLength = *fc.SaltLength
+	}
+	if fc.KeyLength != nil {
+		c.KeyLength = *fc.KeyLength
+	}
+	return &c, c.Validate()
+}
+
+// ConfigFromEnv read hash config from HASH_* environment variables
+func ConfigFromEnv() (*Config, error) {
+	c := *DefaultConfig
+	for env, dst := range map[string]*uint32{
+		EnvMemory:     &c.Memory,
+		EnvIterations: &c.Iterations,
+		EnvSaltLength: &c.SaltLength,
+		EnvKeyLength:  &c.KeyLength,
+	} {
+		if err := lookupUint(env, 32, func(v uint64) { *dst = uint32(v) }); err != nil {
+			return nil, err
+		}
+	}
+	if err := lookupUint(EnvParallelism, 8, func(v uint64) { c.Parallelism = uint8(v) }); err != nil {
+		return nil, err
+	}
+	return &c, c.Validate()
+}
+
+func lookupUint(env string, bitSize int, set func(uint64)) error {
+	value, ok := os.LookupEnv(env)
+	if !ok || value == "" {
+		return nil
+	}
+	v, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil {
+		return fmt.Errorf("hasher: invalid %s: %w", env, err)
+	}
+	set(v)
+	return nil
+}
+
+// Validate make sure c satisfy minimum argon2id parameters
+func (c *Config) Validate() error {
+	switch {
+	case c.Iterations < 1:
+		return errors.New("hasher: iterations must be at least 1")
+	case c.Parallelism < 1:
+		return errors.New("hasher: parallelism must be at least 1")
+	case c.Memory < 8*uint32(c.Parallelism):
+		return errors.New("hasher: memory must be at least 8 KiB per lane")
+	case c.SaltLength < 8:
+		return errors.New("hasher: salt length must be at least 8 bytes")
+	case c.KeyLength < 16:
+		return errors.New("hasher: key length must be at least 16 bytes")
+	}
+	return nil
+}
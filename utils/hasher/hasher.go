@@ -26,27 +26,50 @@ var DefaultConfig = &Config{
 	KeyLength:   32,
 }
 
+// GenerateFromPassword hash password using argon2id. When pepper is
+// configured password is keyed with current pepper first and its id is
+// stored as k parameter.
 func GenerateFromPassword(password string, c *Config) (string, error) {
 	saltBytes, err := genRandomBytes(c.SaltLength)
 	if err != nil {
 		return "", err
 	}
-	hashBytes := argon2.IDKey([]byte(password), saltBytes, c.Iterations, c.Memory, c.Parallelism, c.KeyLength)
+
+	input := []byte(password)
+	params := fmt.Sprintf("m=%d,t=%d,p=%d", c.Memory, c.Iterations, c.Parallelism)
+	if pepperID, key := currentPepper(); key != nil {
+		input = applyPepper(key, password)
+		params += ",k=" + pepperID
+	}
+
+	hashBytes := argon2.IDKey(input, saltBytes, c.Iterations, c.Memory, c.Parallelism, c.KeyLength)
 	b64Salt := base64.RawStdEncoding.EncodeToString(saltBytes)
 	b64Hash := base64.RawStdEncoding.EncodeToString(hashBytes)
-	encodedHash := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, c.Memory, c.Iterations, c.Parallelism, b64Salt, b64Hash)
+	encodedHash := fmt.Sprintf("$argon2id$v=%d$%s$%s$%s", argon2.Version, params, b64Salt, b64Hash)
 
 	return encodedHash, nil
 }
 
-func CompareHashWithPassword(hash string, password string) error {
-	c, saltBytes, hashBytes, err := decodeHash(hash)
+// compareArgon2id verify password against hash generated by
+// GenerateFromPassword
+func compareArgon2id(hash string, password string) error {
+	c, pepperID, saltBytes, hashBytes, err := decodeHash(hash)
 	if err != nil {
 		return err
 	}
 
+	input := []byte(password)
+	if pepperID != "" {
+		key, err := lookupPepper(pepperID)
+		if err != nil {
+			log.Printf("error: unable to verify peppered hash. %s\n", err.Error())
+			return errs.ErrInternalServer
+		}
+		input = applyPepper(key, password)
+	}
+
 	// Derive the key from the other password using the same parameters.
-	otherHash := argon2.IDKey([]byte(password), saltBytes, c.Iterations, c.Memory, c.Parallelism, c.KeyLength)
+	otherHash := argon2.IDKey(input, saltBytes, c.Iterations, c.Memory, c.Parallelism, c.KeyLength)
 
 	if subtle.ConstantTimeCompare(hashBytes, otherHash) == 1 {
 		return nil
@@ -56,6 +79,27 @@ func CompareHashWithPassword(hash string, password string) error {
 	return errs.ErrInvalidCredential
 }
 
+// NeedsRehash report whether hash was generated with algorithm other
+// than argon2id, parameters other than c or pepper other than current
+// one and should be regenerated once password is known
+func NeedsRehash(hash string, c *Config) (bool, error) {
+	if id := algorithmID(hash); id != AlgorithmArgon2id {
+		verifiersMu.RLock()
+		_, ok := verifiers[id]
+		verifiersMu.RUnlock()
+		if !ok {
+			return false, errs.ErrInvalidCredential
+		}
+		return true, nil
+	}
+
+	current, pepperID, _, _, err := decodeHash(hash)
+	if err != nil {
+		return false, err
+	}
+	return *current != *c || pepperID != currentPepperID(), nil
+}
+
 func genRandomBytes(n uint32) ([]byte, error) {
 	b := make([]byte, n)
 	_, err := rand.Read(b)
@@ -66,42 +110,44 @@ func genRandomBytes(n uint32) ([]byte, error) {
 	return b, nil
 }
 
-func decodeHash(encodedHashString string) (c *Config, salt, hash []byte, err error) {
+func decodeHash(encodedHashString string) (c *Config, pepperID string, salt, hash []byte, err error) {
 	vals := strings.Split(encodedHashString, "$")
 	if len(vals) != 6 {
 		log.Println("error: invalid hash format")
-		return nil, nil, nil, errs.ErrInvalidCredential
+		return nil, "", nil, nil, errs.ErrInvalidCredential
 	}
 
 	var version int
 	_, err = fmt.Sscanf(vals[2], "v=%d", &version)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, "", nil, nil, err
 	}
 	if version != argon2.Version {
 		log.Println("error: incompatible argon2 version")
-		return nil, nil, nil, errs.ErrInvalidCredential
+		return nil, "", nil, nil, errs.ErrInvalidCredential
 	}
 
+	// Optional pepper id follow argon2 parameters
+	params, pepperID, _ := strings.Cut(vals[3], ",k=")
 	c = new(Config)
-	_, err = fmt.Sscanf(vals[3], "m=%d,t=%d,p=%d", &c.Memory, &c.Iterations, &c.Parallelism)
+	_, err = fmt.Sscanf(params, "m=%d,t=%d,p=%d", &c.Memory, &c.Iterations, &c.Parallelism)
 	if err != nil {
 		log.Println("error: unable to parse argon2 configuration")
-		return nil, nil, nil, errs.ErrInvalidCredential
+		return nil, "", nil, nil, errs.ErrInvalidCredential
 	}
 
 	salt, err = base64.RawStdEncoding.Strict().DecodeString(vals[4])
 	if err != nil {
 		log.Printf("error: unable to get or decode salt %s\n", err.Error())
-		return nil, nil, nil, errs.ErrInvalidCredential
+		return nil, "", nil, nil, errs.ErrInvalidCredential
 	}
 	c.SaltLength = uint32(len(salt))
 
 	hash, err = base64.RawStdEncoding.Strict().DecodeString(vals[5])
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, "", nil, nil, err
 	}
 	c.KeyLength = uint32(len(hash))
 
-	return c, salt, hash, nil
+	return c, pepperID, salt, hash, nil
 }
@@ -2,6 +2,9 @@ package hasher_test
 
 import (
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nurfianqodar/school-microservices/utils/hasher"
@@ -28,3 +31,127 @@ func TestHash(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestNeedsRehash(t *testing.T) {
+	weak := &hasher.Config{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	hash, err := hasher.GenerateFromPassword("secretpassword", weak)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if needs, err := hasher.NeedsRehash(hash, weak); err != nil || needs {
+		t.Errorf("hash with current config should not need rehash: %v %v", needs, err)
+	}
+	if needs, err := hasher.NeedsRehash(hash, hasher.DefaultConfig); err != nil || !needs {
+		t.Errorf("hash with old config should need rehash: %v %v", needs, err)
+	}
+	if _, err := hasher.NeedsRehash("invalid", hasher.DefaultConfig); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("Should read environment", func(t *testing.T) {
+		t.Setenv(hasher.EnvIterations, "5")
+		t.Setenv(hasher.EnvParallelism, "2")
+		c, err := hasher.LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Iterations != 5 || c.Parallelism != 2 || c.Memory != hasher.DefaultConfig.Memory {
+			t.Errorf("unexpected config %+v", c)
+		}
+	})
+
+	t.Run("Should read file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hasher.json")
+		if err := os.WriteFile(path, []byte(`{"memory": 131072, "iterations": 4}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(hasher.EnvConfigFile, path)
+		c, err := hasher.LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Memory != 131072 || c.Iterations != 4 || c.KeyLength != hasher.DefaultConfig.KeyLength {
+			t.Errorf("unexpected config %+v", c)
+		}
+	})
+
+	t.Run("Should reject weak config", func(t *testing.T) {
+		t.Setenv(hasher.EnvIterations, "0")
+		if _, err := hasher.LoadConfig(); err == nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestPepper(t *testing.T) {
+	t.Cleanup(func() { _ = hasher.SetPeppers("", nil) })
+	v1 := []byte("0123456789abcdef0123456789abcdef")
+	v2 := []byte("fedcba9876543210fedcba9876543210")
+
+	if err := hasher.SetPeppers("v1", map[string][]byte{"v1": v1}); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hasher.GenerateFromPassword("secretpassword", hasher.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(hash, ",k=v1$") {
+		t.Fatalf("pepper id not encoded in %s", hash)
+	}
+	if err := hasher.CompareHashWithPassword(hash, "secretpassword"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate to v2, old hash remain valid but need rehash
+	if err := hasher.SetPeppers("v2", map[string][]byte{"v1": v1, "v2": v2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := hasher.CompareHashWithPassword(hash, "secretpassword"); err != nil {
+		t.Fatal(err)
+	}
+	if needs, err := hasher.NeedsRehash(hash, hasher.DefaultConfig); err != nil || !needs {
+		t.Errorf("hash with old pepper should need rehash: %v %v", needs, err)
+	}
+
+	// Hash can not be verified once its pepper is removed
+	if err := hasher.SetPeppers("v2", map[string][]byte{"v2": v2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := hasher.CompareHashWithPassword(hash, "secretpassword"); err == nil {
+		t.Fail()
+	}
+
+	if err := hasher.SetPeppers("v3", map[string][]byte{"v2": v2}); err == nil {
+		t.Error("unknown current pepper accepted")
+	}
+}
+
+func TestLoadPeppers(t *testing.T) {
+	t.Cleanup(func() { _ = hasher.SetPeppers("", nil) })
+	t.Setenv(hasher.EnvPeppers, "v1=MDEyMzQ1Njc4OWFiY2RlZg==,v2=ZmVkY2JhOTg3NjU0MzIxMA==")
+	if err := hasher.LoadPeppers(); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hasher.GenerateFromPassword("secretpassword", hasher.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(hash, ",k=v2$") {
+		t.Errorf("last pepper should be current: %s", hash)
+	}
+
+	t.Setenv(hasher.EnvPepperCurrent, "v1")
+	if err := hasher.LoadPeppers(); err != nil {
+		t.Fatal(err)
+	}
+	hash, err = hasher.GenerateFromPassword("secretpassword", hasher.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(hash, ",k=v1$") {
+		t.Errorf("HASH_PEPPER_CURRENT not used: %s", hash)
+	}
+}
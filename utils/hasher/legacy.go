@@ -0,0 +1,142 @@
+package hasher
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Verifiers of hash imported from legacy system. They are only used to
+// verify password, new hash is always argon2id.
+func init() {
+	for _, id := range []string{"2a", "2b", "2y"} {
+		Register(id, VerifierFunc(compareBcrypt))
+	}
+	Register("scrypt", VerifierFunc(compareScrypt))
+	Register("pbkdf2-sha256", VerifierFunc(comparePBKDF2SHA256))
+}
+
+// compareBcrypt verify $2b$<cost>$<salt+hash> hash
+func compareBcrypt(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		log.Printf("error: invalid bcrypt hash. %s\n", err.Error())
+	}
+	return errs.ErrInvalidCredential
+}
+
+// Upper bound of scrypt parameters accepted from imported hash. Each
+// verification allocate 128*r*2^ln bytes, so larger values are treated as
+// invalid hash instead of letting single login exhaust memory.
+const (
+	maxScryptLn     = 20
+	maxScryptR      = 16
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30
+)
+
+// compareScrypt verify $scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<hash>
+// hash as produced by passlib
+func compareScrypt(hash, password string) error {
+	vals := strings.Split(hash, "$")
+	if len(vals) != 5 {
+		log.Println("error: invalid scrypt hash format")
+		return errs.ErrInvalidCredential
+	}
+
+	var ln, r, p int
+	if _, err := fmt.Sscanf(vals[2], "ln=%d,r=%d,p=%d", &ln, &r, &p); err != nil {
+		log.Println("error: unable to parse scrypt configuration")
+		return errs.ErrInvalidCredential
+	}
+	if ln < 1 || ln > maxScryptLn || r < 1 || r > maxScryptR || p < 1 || p > maxScryptP ||
+		128*r<<ln > maxScryptMemory {
+		log.Printf("error: scrypt configuration out of range ln=%d,r=%d,p=%d\n", ln, r, p)
+		return errs.ErrInvalidCredential
+	}
+	salt, hashBytes, err := decodeSaltAndHash(vals[3], vals[4])
+	if err != nil {
+		log.Printf("error: unable to decode scrypt hash. %s\n", err.Error())
+		return errs.ErrInvalidCredential
+	}
+
+	otherHash, err := scrypt.Key([]byte(password), salt, 1<<ln, r, p, len(hashBytes))
+	if err != nil {
+		log.Printf("error: unable to derive scrypt key. %s\n", err.Error())
+		return errs.ErrInvalidCredential
+	}
+	return compareBytes(hashBytes, otherHash)
+}
+
+// maxPBKDF2Rounds is upper bound of PBKDF2 rounds accepted from imported
+// hash, larger value would tie up CPU on every login attempt
+const maxPBKDF2Rounds = 2_000_000
+
+// comparePBKDF2SHA256 verify $pbkdf2-sha256$<rounds>$<salt>$<hash> hash
+// as produced by passlib. Rounds may also be written as i=<rounds>.
+func comparePBKDF2SHA256(hash, password string) error {
+	vals := strings.Split(hash, "$")
+	if len(vals) != 5 {
+		log.Println("error: invalid pbkdf2 hash format")
+		return errs.ErrInvalidCredential
+	}
+
+	rounds, err := strconv.Atoi(strings.TrimPrefix(vals[2], "i="))
+	if err != nil {
+		log.Println("error: unable to parse pbkdf2 rounds")
+		return errs.ErrInvalidCredential
+	}
+	if rounds < 1 || rounds > maxPBKDF2Rounds {
+		log.Printf("error: pbkdf2 rounds out of range %d\n", rounds)
+		return errs.ErrInvalidCredential
+	}
+	salt, hashBytes, err := decodeSaltAndHash(vals[3], vals[4])
+	if err != nil {
+		log.Printf("error: unable to decode pbkdf2 hash. %s\n", err.Error())
+		return errs.ErrInvalidCredential
+	}
+
+	otherHash := pbkdf2.Key([]byte(password), salt, rounds, len(hashBytes), sha256.New)
+	return compareBytes(hashBytes, otherHash)
+}
+
+// decodeSaltAndHash decode unpadded base64 salt and hash. Passlib
+// adapted base64 using "." instead of "+" is accepted as well.
+func decodeSaltAndHash(salt, hash string) ([]byte, []byte, error) {
+	decode := func(s string) ([]byte, error) {
+		s = strings.ReplaceAll(strings.TrimRight(s, "="), ".", "+")
+		return base64.RawStdEncoding.DecodeString(s)
+	}
+	saltBytes, err := decode(salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashBytes, err := decode(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(hashBytes) == 0 {
+		return nil, nil, errors.New("empty hash")
+	}
+	return saltBytes, hashBytes, nil
+}
+
+func compareBytes(hash, other []byte) error {
+	if subtle.ConstantTimeCompare(hash, other) == 1 {
+		return nil
+	}
+	return errs.ErrInvalidCredential
+}
@@ -0,0 +1,78 @@
+package hasher_test
+
+import (
+	"testing"
+
+	"github.com/nurfianqodar/school-microservices/utils/hasher"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLegacyHash(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("secretpassword"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Generated using python hashlib and passlib encoding
+	cases := map[string]string{
+		"bcrypt":        string(bcryptHash),
+		"pbkdf2-sha256": "$pbkdf2-sha256$29000$bGVnYWN5c2FsdHZhbHVlIQ$OgwzjcjThmewkTlQnLB81O6a7sivGSIXDMCMH2EllQg",
+		"scrypt":        "$scrypt$ln=14,r=8,p=1$bGVnYWN5c2FsdHZhbHVlIQ$kLgh9/LlxMzLvRzxyUWIm.AlQI8441XQ8hHycLw/FFs",
+	}
+	for name, hash := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := hasher.CompareHashWithPassword(hash, "secretpassword"); err != nil {
+				t.Errorf("valid password rejected: %v", err)
+			}
+			if err := hasher.CompareHashWithPassword(hash, "invalidpassword"); err == nil {
+				t.Error("invalid password accepted")
+			}
+			if needs, err := hasher.NeedsRehash(hash, hasher.DefaultConfig); err != nil || !needs {
+				t.Errorf("legacy hash should need rehash: %v %v", needs, err)
+			}
+		})
+	}
+}
+
+func TestScryptLimit(t *testing.T) {
+	for _, params := range []string{"ln=30,r=8,p=1", "ln=14,r=64,p=1", "ln=14,r=8,p=64", "ln=20,r=16,p=1", "ln=0,r=8,p=1"} {
+		t.Run(params, func(t *testing.T) {
+			hash := "$scrypt$" + params + "$bGVnYWN5c2FsdHZhbHVlIQ$kLgh9/LlxMzLvRzxyUWIm.AlQI8441XQ8hHycLw/FFs"
+			if err := hasher.CompareHashWithPassword(hash, "secretpassword"); err == nil {
+				t.Error("out of range scrypt configuration accepted")
+			}
+		})
+	}
+}
+
+func TestPBKDF2Limit(t *testing.T) {
+	for _, rounds := range []string{"2147483647", "2000001", "0"} {
+		t.Run(rounds, func(t *testing.T) {
+			hash := "$pbkdf2-sha256$" + rounds + "$bGVnYWN5c2FsdHZhbHVlIQ$OgwzjcjThmewkTlQnLB81O6a7sivGSIXDMCMH2EllQg"
+			if err := hasher.CompareHashWithPassword(hash, "secretpassword"); err == nil {
+				t.Error("out of range pbkdf2 rounds accepted")
+			}
+		})
+	}
+}
+
+func TestUnknownAlgorithm(t *testing.T) {
+	if err := hasher.CompareHashWithPassword("$md5$salt$hash", "secretpassword"); err == nil {
+		t.Fail()
+	}
+	if _, err := hasher.NeedsRehash("$md5$salt$hash", hasher.DefaultConfig); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRegister(t *testing.T) {
+	hasher.Register("plain-test", hasher.VerifierFunc(func(hash, password string) error {
+		if hash != "$plain-test$"+password {
+			return bcrypt.ErrMismatchedHashAndPassword
+		}
+		return nil
+	}))
+	if err := hasher.CompareHashWithPassword("$plain-test$secretpassword", "secretpassword"); err != nil {
+		t.Error(err)
+	}
+}
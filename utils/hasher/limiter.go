@@ -0,0 +1,209 @@
+package hasher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Environment variables read by LoadLimiter
+const (
+	EnvConcurrency = "HASH_CONCURRENCY"
+	EnvQueue       = "HASH_QUEUE"
+)
+
+// Default limiter size. Each argon2id hash allocate DefaultConfig.Memory
+// so concurrency bound memory used by hashing.
+const (
+	DefaultConcurrency = 4
+	DefaultQueue       = 64
+)
+
+// ErrSaturated is returned when every hashing slot is busy and queue is
+// full
+var ErrSaturated = status.Error(codes.ResourceExhausted, "too many password hashing requests, try again later")
+
+// Limiter bound number of concurrent hash operation. Caller exceeding
+// concurrency wait in queue until slot is free or its context is done.
+type Limiter struct {
+	slots    chan struct{}
+	maxQueue int64
+
+	waiting  atomic.Int64
+	acquired atomic.Uint64
+	rejected atomic.Uint64
+	canceled atomic.Uint64
+	waitSum  atomic.Int64
+	waitMax  atomic.Int64
+}
+
+// LimiterStats is snapshot of limiter metrics. Wait time is measured
+// from Acquire call until slot is obtained.
+type LimiterStats struct {
+	Concurrency int    `json:"concurrency"`
+	InFlight    int    `json:"in_flight"`
+	QueueSize   int    `json:"queue_size"`
+	QueueDepth  int64  `json:"queue_depth"`
+	Acquired    uint64 `json:"acquired"`
+	Rejected    uint64 `json:"rejected"`
+	Canceled    uint64 `json:"canceled"`
+	WaitTotalMs int64  `json:"wait_total_ms"`
+	WaitMaxMs   int64  `json:"wait_max_ms"`
+}
+
+// NewLimiter create limiter running at most concurrency operation while
+// at most queue caller wait for slot
+func NewLimiter(concurrency, queue int) *Limiter {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if queue < 0 {
+		queue = 0
+	}
+	return &Limiter{
+		slots:    make(chan struct{}, concurrency),
+		maxQueue: int64(queue),
+	}
+}
+
+// Acquire wait for free slot and return function releasing it. It
+// return ErrSaturated when queue is full and context status error when
+// ctx is done first.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	release := func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		l.acquired.Add(1)
+		return release, nil
+	default:
+	}
+
+	if l.waiting.Add(1) > l.maxQueue {
+		l.waiting.Add(-1)
+		l.rejected.Add(1)
+		return nil, ErrSaturated
+	}
+	defer l.waiting.Add(-1)
+
+	start := time.Now()
+	select {
+	case l.slots <- struct{}{}:
+		l.observeWait(time.Since(start))
+		l.acquired.Add(1)
+		return release, nil
+	case <-ctx.Done():
+		l.observeWait(time.Since(start))
+		l.canceled.Add(1)
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (l *Limiter) observeWait(d time.Duration) {
+	l.waitSum.Add(int64(d))
+	for {
+		current := l.waitMax.Load()
+		if int64(d) <= current || l.waitMax.CompareAndSwap(current, int64(d)) {
+			return
+		}
+	}
+}
+
+// Stats return current limiter metrics
+func (l *Limiter) Stats() LimiterStats {
+	return LimiterStats{
+		Concurrency: cap(l.slots),
+		InFlight:    len(l.slots),
+		QueueSize:   int(l.maxQueue),
+		QueueDepth:  l.waiting.Load(),
+		Acquired:    l.acquired.Load(),
+		Rejected:    l.rejected.Load(),
+		Canceled:    l.canceled.Load(),
+		WaitTotalMs: time.Duration(l.waitSum.Load()).Milliseconds(),
+		WaitMaxMs:   time.Duration(l.waitMax.Load()).Milliseconds(),
+	}
+}
+
+// limiter bound hashing done by context aware function. When nil
+// hashing is not limited.
+var limiter atomic.Pointer[Limiter]
+
+func init() {
+	limiter.Store(NewLimiter(DefaultConcurrency, DefaultQueue))
+}
+
+// SetLimiter replace limiter used by GenerateFromPasswordContext and
+// CompareHashWithPasswordContext. Nil disable limiting.
+func SetLimiter(l *Limiter) {
+	limiter.Store(l)
+}
+
+// LoadLimiter configure limiter from HASH_CONCURRENCY and HASH_QUEUE,
+// fallback to DefaultConcurrency and DefaultQueue
+func LoadLimiter() error {
+	concurrency, queue := DefaultConcurrency, DefaultQueue
+	for env, dst := range map[string]*int{
+		EnvConcurrency: &concurrency,
+		EnvQueue:       &queue,
+	} {
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("hasher: invalid %s", env)
+		}
+		*dst = n
+	}
+	if concurrency == 0 {
+		return fmt.Errorf("hasher: %s must be greater than zero", EnvConcurrency)
+	}
+	SetLimiter(NewLimiter(concurrency, queue))
+	return nil
+}
+
+// Stats return metrics of current limiter
+func Stats() LimiterStats {
+	l := limiter.Load()
+	if l == nil {
+		return LimiterStats{}
+	}
+	return l.Stats()
+}
+
+func acquire(ctx context.Context) (func(), error) {
+	l := limiter.Load()
+	if l == nil {
+		return func() {}, nil
+	}
+	return l.Acquire(ctx)
+}
+
+// GenerateFromPasswordContext is GenerateFromPassword run within
+// limiter. It honor ctx deadline while waiting for slot.
+func GenerateFromPasswordContext(ctx context.Context, password string, c *Config) (string, error) {
+	release, err := acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return GenerateFromPassword(password, c)
+}
+
+// CompareHashWithPasswordContext is CompareHashWithPassword run within
+// limiter. It honor ctx deadline while waiting for slot.
+func CompareHashWithPasswordContext(ctx context.Context, hash string, password string) error {
+	release, err := acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return CompareHashWithPassword(hash, password)
+}
@@ -0,0 +1,83 @@
+package hasher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(1, 1)
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Second caller wait in queue
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := l.Acquire(context.Background())
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	for l.Stats().QueueDepth != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Third caller is rejected since queue is full
+	if _, err := l.Acquire(context.Background()); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+
+	release()
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+
+	stats := l.Stats()
+	if stats.Acquired != 2 || stats.Rejected != 1 || stats.QueueDepth != 0 || stats.InFlight != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLimiterDeadline(t *testing.T) {
+	l := NewLimiter(1, 8)
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if stats := l.Stats(); stats.Canceled != 1 || stats.WaitMaxMs < 10 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLoadLimiter(t *testing.T) {
+	defer SetLimiter(NewLimiter(DefaultConcurrency, DefaultQueue))
+
+	t.Setenv(EnvConcurrency, "2")
+	t.Setenv(EnvQueue, "0")
+	if err := LoadLimiter(); err != nil {
+		t.Fatal(err)
+	}
+	if stats := Stats(); stats.Concurrency != 2 || stats.QueueSize != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	t.Setenv(EnvConcurrency, "0")
+	if err := LoadLimiter(); err == nil {
+		t.Error("expected error on zero concurrency")
+	}
+}
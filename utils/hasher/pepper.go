@@ -0,0 +1,123 @@
+package hasher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Environment variables read by LoadPeppers
+const (
+	EnvPeppers       = "HASH_PEPPERS"
+	EnvPepperCurrent = "HASH_PEPPER_CURRENT"
+)
+
+var pepperIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+type pepperSet struct {
+	current string
+	keys    map[string][]byte
+}
+
+// peppers hold active pepper keys. When nil password is hashed without
+// pepper.
+var peppers atomic.Pointer[pepperSet]
+
+// SetPeppers replace pepper keys by id. New hash use pepper current while
+// every key remain usable to verify existing hash so pepper can be
+// rotated. Empty current disable pepper for new hash.
+func SetPeppers(current string, keys map[string][]byte) error {
+	if current == "" && len(keys) == 0 {
+		peppers.Store(nil)
+		return nil
+	}
+
+	set := &pepperSet{current: current, keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		if !pepperIDPattern.MatchString(id) {
+			return fmt.Errorf("hasher: invalid pepper id %q", id)
+		}
+		if len(key) < 16 {
+			return fmt.Errorf("hasher: pepper %s must be at least 16 bytes", id)
+		}
+		set.keys[id] = key
+	}
+	if _, ok := set.keys[current]; current != "" && !ok {
+		return fmt.Errorf("hasher: unknown current pepper %s", current)
+	}
+
+	peppers.Store(set)
+	return nil
+}
+
+// LoadPeppers read base64 encoded pepper keys from HASH_PEPPERS as
+// comma separated id=key pairs. HASH_PEPPER_CURRENT select pepper used
+// for new hash and default to the last pair.
+func LoadPeppers() error {
+	env, ok := os.LookupEnv(EnvPeppers)
+	if !ok || strings.TrimSpace(env) == "" {
+		return SetPeppers("", nil)
+	}
+
+	keys := make(map[string][]byte)
+	last := ""
+	for pair := range strings.SplitSeq(env, ",") {
+		id, encoded, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return fmt.Errorf("hasher: invalid %s entry %q", EnvPeppers, pair)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("hasher: decode pepper %s: %w", id, err)
+		}
+		keys[id] = key
+		last = id
+	}
+
+	current := last
+	if id, ok := os.LookupEnv(EnvPepperCurrent); ok && id != "" {
+		current = id
+	}
+	return SetPeppers(current, keys)
+}
+
+// currentPepper return id and key of pepper used for new hash
+func currentPepper() (string, []byte) {
+	set := peppers.Load()
+	if set == nil || set.current == "" {
+		return "", nil
+	}
+	return set.current, set.keys[set.current]
+}
+
+// currentPepperID return id of pepper used for new hash
+func currentPepperID() string {
+	id, _ := currentPepper()
+	return id
+}
+
+// lookupPepper return key of pepper id
+func lookupPepper(id string) ([]byte, error) {
+	set := peppers.Load()
+	if set == nil {
+		return nil, errors.New("no pepper configured")
+	}
+	key, ok := set.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown pepper %s", id)
+	}
+	return key, nil
+}
+
+// applyPepper return HMAC-SHA256 of password keyed by pepper key
+func applyPepper(key []byte, password string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
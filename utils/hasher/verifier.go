@@ -0,0 +1,66 @@
+package hasher
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/nurfianqodar/school-microservices/utils/errs"
+)
+
+// Algorithm id of hash generated by GenerateFromPassword
+const AlgorithmArgon2id = "argon2id"
+
+// Verifier check password against encoded hash of single algorithm
+type Verifier interface {
+	// Verify return nil when password match encodedHash
+	Verify(encodedHash, password string) error
+}
+
+// VerifierFunc adapt ordinary function as Verifier
+type VerifierFunc func(encodedHash, password string) error
+
+func (f VerifierFunc) Verify(encodedHash, password string) error {
+	return f(encodedHash, password)
+}
+
+var (
+	verifiersMu sync.RWMutex
+	verifiers   = make(map[string]Verifier)
+)
+
+// Register make v verify hash whose PHC algorithm prefix is id, e.g.
+// "argon2id" for $argon2id$... hash. Registering same id twice replace
+// previous verifier.
+func Register(id string, v Verifier) {
+	verifiersMu.Lock()
+	defer verifiersMu.Unlock()
+	verifiers[id] = v
+}
+
+func init() {
+	Register(AlgorithmArgon2id, VerifierFunc(compareArgon2id))
+}
+
+// algorithmID return PHC algorithm prefix of encodedHash
+func algorithmID(encodedHash string) string {
+	if !strings.HasPrefix(encodedHash, "$") {
+		return ""
+	}
+	id, _, _ := strings.Cut(encodedHash[1:], "$")
+	return id
+}
+
+// CompareHashWithPassword verify password using verifier registered for
+// algorithm of hash
+func CompareHashWithPassword(hash string, password string) error {
+	id := algorithmID(hash)
+	verifiersMu.RLock()
+	v, ok := verifiers[id]
+	verifiersMu.RUnlock()
+	if !ok {
+		log.Printf("error: unsupported hash algorithm %q\n", id)
+		return errs.ErrInvalidCredential
+	}
+	return v.Verify(hash, password)
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/nurfianqodar/school-microservices/utils/httpres"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -23,9 +26,10 @@ type HTTPErr interface {
 }
 
 type httperr struct {
-	Code    int    `json:"-"`
-	Message string `json:"message"`
-	Detail  any    `json:"detail,omitempty"`
+	Code       int           `json:"-"`
+	RetryAfter time.Duration `json:"-"`
+	Message    string        `json:"message"`
+	Detail     any           `json:"detail,omitempty"`
 }
 
 func (e *httperr) Error() string {
@@ -49,6 +53,9 @@ func New(code int, message string, detail ...any) HTTPErr {
 }
 
 func (e *httperr) Send(w http.ResponseWriter) {
+	if e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
 	w.WriteHeader(e.Code)
 	if err := json.NewEncoder(w).Encode(httpres.New(false, e)); err != nil {
 		log.Printf("error: unable to write error response. %s", err.Error())
@@ -60,6 +67,7 @@ func ConvertGRPCErrorToHTTPErr(err error) HTTPErr {
 
 	var httpCode int
 	var finalDetail any
+	var retryAfter time.Duration
 
 	st := status.Convert(err)
 	message := st.Message()
@@ -71,10 +79,18 @@ func ConvertGRPCErrorToHTTPErr(err error) HTTPErr {
 		httpCode = http.StatusConflict
 	case codes.InvalidArgument:
 		httpCode = http.StatusBadRequest
+	case codes.NotFound:
+		httpCode = http.StatusNotFound
 	case codes.Unauthenticated:
 		httpCode = http.StatusUnauthorized
+	case codes.PermissionDenied, codes.FailedPrecondition:
+		httpCode = http.StatusForbidden
 	case codes.Aborted:
 		httpCode = http.StatusConflict
+	case codes.ResourceExhausted:
+		httpCode = http.StatusTooManyRequests
+	case codes.Unavailable:
+		httpCode = http.StatusServiceUnavailable
 	default:
 		httpCode = http.StatusInternalServerError
 	}
@@ -87,6 +103,8 @@ func ConvertGRPCErrorToHTTPErr(err error) HTTPErr {
 			detail = append(detail, info.GetViolations())
 		case *epb.QuotaFailure:
 			detail = append(detail, info.GetViolations())
+		case *epb.RetryInfo:
+			retryAfter = info.GetRetryDelay().AsDuration()
 		}
 	}
 
@@ -99,9 +117,10 @@ func ConvertGRPCErrorToHTTPErr(err error) HTTPErr {
 	}
 
 	return &httperr{
-		Code:    httpCode,
-		Message: message,
-		Detail:  finalDetail,
+		Code:       httpCode,
+		RetryAfter: retryAfter,
+		Message:    message,
+		Detail:     finalDetail,
 	}
 
 }
@@ -0,0 +1,43 @@
+package httperr_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nurfianqodar/school-microservices/utils/httperr"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestConvertGRPCErrorRetryInfo(t *testing.T) {
+	st, err := status.New(codes.ResourceExhausted, "too many failed login attempts").WithDetails(&epb.RetryInfo{
+		RetryDelay: durationpb.New(time.Millisecond * 1500),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	httperr.ConvertGRPCErrorToHTTPErr(st.Err()).Send(w)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("unexpected status code %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("unexpected Retry-After %q", got)
+	}
+}
+
+func TestConvertGRPCErrorWithoutRetryInfo(t *testing.T) {
+	w := httptest.NewRecorder()
+	httperr.ConvertGRPCErrorToHTTPErr(status.Error(codes.NotFound, "user not found")).Send(w)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "" {
+		t.Errorf("unexpected Retry-After %q", got)
+	}
+}
@@ -2,6 +2,13 @@ package httpres
 
 import "time"
 
+// Pagination describe position of page inside list response
+type Pagination struct {
+	PageSize      int    `json:"pageSize"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	TotalCount    uint64 `json:"totalCount"`
+}
+
 func New(success bool, data any) map[string]any {
 	return map[string]any{
 		"success":    success,
@@ -9,3 +16,11 @@ func New(success bool, data any) map[string]any {
 		"data":       data,
 	}
 }
+
+// NewPaginated create successful response of list along with its
+// pagination metadata
+func NewPaginated(data any, p Pagination) map[string]any {
+	res := New(true, data)
+	res["pagination"] = p
+	return res
+}